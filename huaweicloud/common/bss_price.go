@@ -0,0 +1,195 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+const (
+	// the measure ID of the hour, which is the usage unit of the on-demand products
+	measureIDHour = 4
+	// the measure ID of the GB, which is the size unit of the volumes
+	measureIDGB = 17
+	// the measure ID of the Mbit/s, which is the size unit of the bandwidths
+	measureIDMbps = 15
+)
+
+// PriceProductType is the type of the product which can be inquired by QueryOnDemandPrice and QueryPeriodPrice.
+type PriceProductType struct {
+	CloudServiceType string
+	ResourceType     string
+	// SizeMeasureID is the measure ID of the resource size, 0 means the size is not required
+	SizeMeasureID int
+}
+
+// PriceProductTypes are the supported types of the price inquiry, the keys are the values of the product type in
+// the data source huaweicloud_bss_price.
+var PriceProductTypes = map[string]PriceProductType{
+	// the spec is the flavor with the OS type suffix, e.g. s6.small.1.linux
+	"ecs": {CloudServiceType: "hws.service.type.ec2", ResourceType: "hws.resource.type.vm"},
+	// the spec is the volume type, e.g. SSD
+	"evs": {CloudServiceType: "hws.service.type.ebs", ResourceType: "hws.resource.type.volume",
+		SizeMeasureID: measureIDGB},
+	// the spec is the bandwidth type, e.g. 19_bgp
+	"eip_bandwidth": {CloudServiceType: "hws.service.type.vpc", ResourceType: "hws.resource.type.bandwidth",
+		SizeMeasureID: measureIDMbps},
+	// the spec is the flavor of the RDS instance, e.g. rds.mysql.n1.large.2.ha
+	"rds": {CloudServiceType: "hws.service.type.rds", ResourceType: "hws.resource.type.rds.vm"},
+}
+
+// PriceProduct is the product to inquire the price.
+type PriceProduct struct {
+	// Type is the key of PriceProductTypes
+	Type             string
+	Spec             string
+	AvailabilityZone string
+	// Size is the resource size, the unit is GB for the volumes and Mbit/s for the bandwidths
+	Size     int
+	Quantity int
+}
+
+// ProductPrice is the price of a product, the ID is the index of the product in the inquiry.
+type ProductPrice struct {
+	ID                    string
+	Amount                float64
+	OfficialWebsiteAmount float64
+	DiscountAmount        float64
+}
+
+// PriceResult is the result of the price inquiry.
+type PriceResult struct {
+	Currency              string
+	Amount                float64
+	OfficialWebsiteAmount float64
+	DiscountAmount        float64
+	Products              []ProductPrice
+}
+
+func buildPriceProductInfos(region string, products []PriceProduct) ([]map[string]interface{}, error) {
+	result := make([]map[string]interface{}, len(products))
+	for i, p := range products {
+		productType, ok := PriceProductTypes[p.Type]
+		if !ok {
+			return nil, fmt.Errorf("the product type %s is not supported", p.Type)
+		}
+
+		quantity := p.Quantity
+		if quantity < 1 {
+			quantity = 1
+		}
+		info := map[string]interface{}{
+			"id":                 strconv.Itoa(i),
+			"cloud_service_type": productType.CloudServiceType,
+			"resource_type":      productType.ResourceType,
+			"resource_spec":      p.Spec,
+			"region":             region,
+			"subscription_num":   quantity,
+		}
+		if p.AvailabilityZone != "" {
+			info["available_zone"] = p.AvailabilityZone
+		}
+		if productType.SizeMeasureID != 0 {
+			if p.Size < 1 {
+				return nil, fmt.Errorf("the size of the product type %s must be specified", p.Type)
+			}
+			info["resource_size"] = p.Size
+			info["size_measure_id"] = productType.SizeMeasureID
+		}
+		result[i] = info
+	}
+	return result, nil
+}
+
+func queryPrice(cfg *config.Config, region, httpUrl string, body map[string]interface{}) (interface{}, error) {
+	client, err := cfg.NewServiceClient("bss", region)
+	if err != nil {
+		return nil, fmt.Errorf("error creating BSS client: %s", err)
+	}
+
+	body["project_id"] = client.ProjectID
+	opt := golangsdk.RequestOpts{
+		KeepResponseBody: true,
+		JSONBody:         body,
+	}
+	resp, err := client.Request("POST", client.Endpoint+httpUrl, &opt)
+	if err != nil {
+		return nil, fmt.Errorf("error inquiring the price: %s", err)
+	}
+	return utils.FlattenResponse(resp)
+}
+
+func flattenPriceResult(result interface{}, currency string) *PriceResult {
+	rst := PriceResult{
+		Currency:              utils.PathSearch("currency", result, currency).(string),
+		Amount:                utils.PathSearch("amount", result, float64(0)).(float64),
+		OfficialWebsiteAmount: utils.PathSearch("official_website_amount", result, float64(0)).(float64),
+		DiscountAmount:        utils.PathSearch("discount_amount", result, float64(0)).(float64),
+	}
+	products := utils.PathSearch("product_rating_results", result, make([]interface{}, 0)).([]interface{})
+	for _, p := range products {
+		rst.Products = append(rst.Products, ProductPrice{
+			ID:                    utils.PathSearch("id", p, "").(string),
+			Amount:                utils.PathSearch("amount", p, float64(0)).(float64),
+			OfficialWebsiteAmount: utils.PathSearch("official_website_amount", p, float64(0)).(float64),
+			DiscountAmount:        utils.PathSearch("discount_amount", p, float64(0)).(float64),
+		})
+	}
+	return &rst
+}
+
+// QueryOnDemandPrice inquires the hourly price of the on-demand (postPaid) products.
+// @API BSS POST /v2/bills/ratings/on-demand-resources
+func QueryOnDemandPrice(cfg *config.Config, region string, products []PriceProduct) (*PriceResult, error) {
+	infos, err := buildPriceProductInfos(region, products)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		info["usage_factor"] = "Duration"
+		info["usage_value"] = 1
+		info["usage_measure_id"] = measureIDHour
+	}
+
+	respBody, err := queryPrice(cfg, region, "v2/bills/ratings/on-demand-resources",
+		map[string]interface{}{"product_infos": infos})
+	if err != nil {
+		return nil, err
+	}
+	return flattenPriceResult(respBody, ""), nil
+}
+
+// QueryPeriodPrice inquires the price of the yearly/monthly (prePaid) products, the periodUnit is month or year.
+// The result is the optimal price with the promotions.
+// @API BSS POST /v2/bills/ratings/period-resources/subscribe-rate
+func QueryPeriodPrice(cfg *config.Config, region, periodUnit string, period int,
+	products []PriceProduct) (*PriceResult, error) {
+	infos, err := buildPriceProductInfos(region, products)
+	if err != nil {
+		return nil, err
+	}
+
+	periodType := 2
+	if periodUnit == "year" {
+		periodType = 3
+	}
+	for _, info := range infos {
+		info["period_type"] = periodType
+		info["period_num"] = period
+	}
+
+	respBody, err := queryPrice(cfg, region, "v2/bills/ratings/period-resources/subscribe-rate",
+		map[string]interface{}{"product_infos": infos})
+	if err != nil {
+		return nil, err
+	}
+	result := utils.PathSearch("optimal_promotion_rating_result", respBody, nil)
+	if result == nil {
+		return nil, fmt.Errorf("the price is not found in the response")
+	}
+	return flattenPriceResult(result, utils.PathSearch("currency", respBody, "").(string)), nil
+}
@@ -18,6 +18,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -294,6 +295,17 @@ func HasFilledOpt(d *schema.ResourceData, param string) bool {
 	return b
 }
 
+// GetWriteOnlyString returns the string value of the write-only attribute from the raw config, an empty string is
+// returned if it's not specified. The write-only attributes are never persisted to the plan and state, so d.Get
+// always returns the zero value, and their changes should be triggered by another attribute, e.g. a version number.
+func GetWriteOnlyString(d *schema.ResourceData, path cty.Path) string {
+	v, diags := d.GetRawConfigAt(path)
+	if diags.HasError() || !v.IsKnown() || v.IsNull() || !v.Type().Equals(cty.String) {
+		return ""
+	}
+	return v.AsString()
+}
+
 // RetryFunc is the function retried until it succeeds.
 // The first return parameter is the result of the retry func.
 // The second return parameter indicates whether a retry is required.
@@ -0,0 +1,152 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+// The quota types used by the preflight quota checks, which are keyed by the services.
+const (
+	QuotaECSInstances = "instances"
+	QuotaECSCores     = "cores"
+	QuotaECSRAM       = "ram"
+	QuotaEVSVolumes   = "volumes"
+	QuotaEVSGigabytes = "gigabytes"
+	QuotaVPCVPCs      = "vpc"
+	QuotaVPCPublicIPs = "publicIp"
+)
+
+// QuotaDemandFunc returns the quota demands of the resource being created, which are keyed by the services and the
+// quota types. The demands which are unknown during the plan should be omitted.
+type QuotaDemandFunc func(ctx context.Context, d *schema.ResourceDiff, cfg *config.Config) (map[string]map[string]int,
+	error)
+
+var quotaQueries = map[string]func(cfg *config.Config, region string) (map[string]config.QuotaUsage, error){
+	"ecs": queryECSQuotas,
+	"evs": queryEVSQuotas,
+	"vpc": queryVPCQuotas,
+}
+
+// PreflightQuotaCheck returns a CustomizeDiffFunc which checks the quotas of the resource being created when the
+// preflight_quota_checks is enabled in the provider, so that the plan fails before any resource is created.
+func PreflightQuotaCheck(demandFunc QuotaDemandFunc) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		cfg, ok := meta.(*config.Config)
+		if !ok || !cfg.PreflightQuotaChecks || d.Id() != "" {
+			return nil
+		}
+
+		region := cfg.Region
+		if v, ok := d.GetOk("region"); ok {
+			region = v.(string)
+		}
+
+		demands, err := demandFunc(ctx, d, cfg)
+		if err != nil {
+			return err
+		}
+		for service, serviceDemands := range demands {
+			query, ok := quotaQueries[service]
+			if !ok {
+				return fmt.Errorf("the quotas of %s are not supported", service)
+			}
+			err := cfg.ReserveQuota(region, service, serviceDemands, func() (map[string]config.QuotaUsage, error) {
+				return query(cfg, region)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func queryQuotas(cfg *config.Config, service, region, httpUrl string) (interface{}, error) {
+	client, err := cfg.NewServiceClient(service, region)
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s client: %s", strings.ToUpper(service), err)
+	}
+
+	requestPath := client.Endpoint + strings.ReplaceAll(httpUrl, "{project_id}", client.ProjectID)
+	opt := golangsdk.RequestOpts{
+		KeepResponseBody: true,
+	}
+	resp, err := client.Request("GET", requestPath, &opt)
+	if err != nil {
+		return nil, err
+	}
+	return utils.FlattenResponse(resp)
+}
+
+func pathSearchInt(expression string, obj interface{}) int {
+	return int(utils.PathSearch(expression, obj, float64(-1)).(float64))
+}
+
+// @API ECS GET /v1/{project_id}/cloudservers/limits
+func queryECSQuotas(cfg *config.Config, region string) (map[string]config.QuotaUsage, error) {
+	respBody, err := queryQuotas(cfg, "ecs", region, "v1/{project_id}/cloudservers/limits")
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]config.QuotaUsage{
+		QuotaECSInstances: {
+			Limit: pathSearchInt("absolute.maxTotalInstances", respBody),
+			Used:  pathSearchInt("absolute.totalInstancesUsed", respBody),
+		},
+		QuotaECSCores: {
+			Limit: pathSearchInt("absolute.maxTotalCores", respBody),
+			Used:  pathSearchInt("absolute.totalCoresUsed", respBody),
+		},
+		QuotaECSRAM: {
+			Limit: pathSearchInt("absolute.maxTotalRAMSize", respBody),
+			Used:  pathSearchInt("absolute.totalRAMUsed", respBody),
+		},
+	}, nil
+}
+
+// @API EVS GET /v2/{project_id}/os-quota-sets/{project_id}
+func queryEVSQuotas(cfg *config.Config, region string) (map[string]config.QuotaUsage, error) {
+	respBody, err := queryQuotas(cfg, "evs", region, "v2/{project_id}/os-quota-sets/{project_id}?usage=True")
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]config.QuotaUsage)
+	for _, quotaType := range []string{QuotaEVSVolumes, QuotaEVSGigabytes} {
+		// the reserved quotas are being used by the volumes being created
+		result[quotaType] = config.QuotaUsage{
+			Limit: pathSearchInt(fmt.Sprintf("quota_set.%s.limit", quotaType), respBody),
+			Used: pathSearchInt(fmt.Sprintf("quota_set.%s.in_use", quotaType), respBody) +
+				max(pathSearchInt(fmt.Sprintf("quota_set.%s.reserved", quotaType), respBody), 0),
+		}
+	}
+	return result, nil
+}
+
+// @API VPC GET /v1/{project_id}/quotas
+func queryVPCQuotas(cfg *config.Config, region string) (map[string]config.QuotaUsage, error) {
+	respBody, err := queryQuotas(cfg, "vpc", region, "v1/{project_id}/quotas")
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]config.QuotaUsage)
+	resources := utils.PathSearch("quotas.resources", respBody, make([]interface{}, 0)).([]interface{})
+	for _, r := range resources {
+		quotaType := utils.PathSearch("type", r, "").(string)
+		result[quotaType] = config.QuotaUsage{
+			Limit: pathSearchInt("quota", r),
+			Used:  pathSearchInt("used", r),
+		}
+	}
+	return result, nil
+}
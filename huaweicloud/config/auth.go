@@ -1,22 +1,20 @@
 package config
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
-	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"os"
 	"time"
 
-	"github.com/jmespath/go-jmespath"
-
 	"github.com/chnsz/golangsdk"
 	"github.com/chnsz/golangsdk/auth"
 	huaweisdk "github.com/chnsz/golangsdk/openstack"
 
+	iamv3 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/iam/v3"
 	iam_model "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/iam/v3/model"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/pathorcontents"
@@ -48,6 +46,10 @@ type Profile struct {
 	AgencyDomainName string  `json:"agencyDomainName"`
 	AgencyName       string  `json:"agencyName"`
 	SsoAuth          SsoAuth `json:"ssoAuth"`
+
+	CredentialProcess    string `json:"credentialProcess"`
+	WebIdentityTokenFile string `json:"webIdentityTokenFile"`
+	WebIdentityProvider  string `json:"webIdentityProvider"`
 }
 
 type SsoAuth struct {
@@ -60,16 +62,29 @@ type StsToken struct {
 	SecurityToken   string `json:"securityToken"`
 }
 
+// AssumeRole is the IAM agency to be assumed.
+type AssumeRole struct {
+	AgencyName string
+	DomainName string
+	// DomainID is the ID of the agency domain, the IAM v5 API is used to assume the agency if it's specified
+	DomainID string
+	// Duration is the validity period of the temporary AK/SK, defaults to 12 hours
+	Duration time.Duration
+	// SessionPolicy and SessionTags are the policy in JSON format and the tags of the agency session, which are
+	// only supported by the IAM v5 API
+	SessionPolicy string
+	SessionTags   map[string]string
+}
+
 func buildClient(c *Config) error {
 	if c.Token != "" {
 		return buildClientByToken(c)
-	} else if c.AccessKey != "" && c.SecretKey != "" {
-		return buildClientByAKSK(c)
-	} else if c.Password != "" && (c.Username != "" || c.UserID != "") {
+	} else if (c.AccessKey == "" || c.SecretKey == "") && c.Password != "" && (c.Username != "" || c.UserID != "") {
 		return buildClientByPassword(c)
 	}
 
-	return buildClientByMeta(c)
+	// static AK/SK, credential process, web identity token file and ECS metadata API
+	return buildClientByCredentialProviders(c)
 }
 
 func generateTLSConfig(c *Config) (*tls.Config, error) {
@@ -128,15 +143,24 @@ func genClient(c *Config, ao golangsdk.AuthOptionsProvider) (*golangsdk.Provider
 	if err != nil {
 		return nil, err
 	}
-	transport := &http.Transport{
+	var transport http.RoundTripper = &http.Transport{
 		Proxy:           http.ProxyFromEnvironment,
 		TLSClientConfig: config,
 	}
+	if c.Recorder != nil {
+		transport = c.Recorder.RoundTripper(transport)
+	}
 
 	client.HTTPClient = http.Client{
-		Transport: &LogRoundTripper{
-			Rt:         transport,
-			MaxRetries: c.MaxRetries,
+		Transport: &credentialRoundTripper{
+			Rt: &LogRoundTripper{
+				Rt: &rateLimitRoundTripper{
+					Rt:     transport,
+					config: c,
+				},
+				MaxRetries: c.MaxRetries,
+			},
+			config: c,
 		},
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if client.AKSKAuthOptions.AccessKey != "" {
@@ -154,6 +178,11 @@ func genClient(c *Config, ao golangsdk.AuthOptionsProvider) (*golangsdk.Provider
 		client.RetryBackoffFunc = retryBackoffFunc
 	}
 
+	// the tags helpers honour the ignored tags of the provider configuration by the context of the clients
+	if len(c.IgnoreTagKeys) > 0 || len(c.IgnoreTagKeyPrefixes) > 0 {
+		client.Context = utils.ContextWithIgnoreTags(context.Background(), c.IgnoreTagKeys, c.IgnoreTagKeyPrefixes)
+	}
+
 	// Validate authentication normally.
 	err = huaweisdk.Authenticate(client, ao)
 	if err != nil {
@@ -215,6 +244,7 @@ func buildClientByToken(c *Config) error {
 
 func buildClientByAKSK(c *Config) error {
 	var projectAuthOptions, domainAuthOptions golangsdk.AKSKAuthOptions
+	var isProjectIDQueried bool
 
 	if c.AgencyDomainName != "" && c.AgencyName != "" {
 		projectAuthOptions = golangsdk.AKSKAuthOptions{
@@ -241,6 +271,15 @@ func buildClientByAKSK(c *Config) error {
 			DomainID: c.DomainID,
 			Domain:   c.DomainName,
 		}
+
+		// the project ID and domain ID are queried by name during the authentication if they are not cached
+		if projectAuthOptions.ProjectId == "" && c.TenantName != "" {
+			projectAuthOptions.ProjectId, _ = c.cache.get(cacheKeyProjectID + c.TenantName)
+			isProjectIDQueried = projectAuthOptions.ProjectId == ""
+		}
+		if domainAuthOptions.DomainID == "" && c.DomainName != "" {
+			domainAuthOptions.DomainID, _ = c.cache.get(cacheKeyDomainID)
+		}
 	}
 
 	for _, ao := range []*golangsdk.AKSKAuthOptions{&projectAuthOptions, &domainAuthOptions} {
@@ -256,7 +295,15 @@ func buildClientByAKSK(c *Config) error {
 			ao.SecurityToken = c.SecurityToken
 		}
 	}
-	return genClients(c, projectAuthOptions, domainAuthOptions)
+	if err := genClients(c, projectAuthOptions, domainAuthOptions); err != nil {
+		return err
+	}
+
+	// cache the project ID only if it's queried from IAM, the cached one is not written back to let it expire
+	if isProjectIDQueried && c.HwClient.ProjectID != "" {
+		c.cache.set(map[string]string{cacheKeyProjectID + c.TenantName: c.HwClient.ProjectID})
+	}
+	return nil
 }
 
 func buildClientByPassword(c *Config) error {
@@ -300,17 +347,53 @@ func buildClientByPassword(c *Config) error {
 	return genClients(c, projectAuthOptions, domainAuthOptions)
 }
 
-func buildClientByAgency(c *Config) error {
-	client, err := c.HcIamV3Client(c.Region)
+// buildClientByAgencies assumes the agencies in order, each agency is assumed with the temporary AK/SK of the
+// previous one, and builds the clients with the temporary AK/SK of the last agency.
+func buildClientByAgencies(c *Config) error {
+	for _, role := range c.AssumeRoles {
+		creds, err := assumeRole(c, role)
+		if err != nil {
+			return err
+		}
+
+		c.AccessKey, c.SecretKey, c.SecurityToken = creds.AccessKey, creds.SecretKey, creds.SecurityToken
+		c.SecurityKeyExpiresAt = creds.ExpiresAt
+		log.Printf("[DEBUG] Successfully assumed agency %s of domain %s, the temporary security key will expire at: %s",
+			role.AgencyName, role.DomainName, creds.ExpiresAt)
+	}
+	return buildClientByAKSK(c)
+}
+
+// assumeRole assumes the agency with the current AK/SK of the Config and returns the temporary AK/SK of the agency.
+// The IAM v5 API is used if the domain ID of the agency is specified, otherwise the IAM v3 API is used.
+func assumeRole(c *Config, role AssumeRole) (*Credentials, error) {
+	if role.DomainID != "" {
+		return assumeRoleByAgencyV5(c, role)
+	}
+	return assumeRoleByAgency(c, role)
+}
+
+func (role AssumeRole) durationSeconds() int32 {
+	if role.Duration <= 0 {
+		return assumeRoleDuration
+	}
+	return int32(role.Duration.Seconds())
+}
+
+func assumeRoleByAgency(c *Config, role AssumeRole) (*Credentials, error) {
+	// the client is created without checking the expiration of the security key, as the method is also used to
+	// reload the security key
+	hcClient, err := implNewHcClient(c, c.Region, "iam", true, false)
 	if err != nil {
-		return fmt.Errorf("Error creating Huaweicloud IAM client: %s", err)
+		return nil, fmt.Errorf("Error creating Huaweicloud IAM client: %s", err)
 	}
+	client := iamv3.NewIamClient(hcClient)
 
 	request := &iam_model.CreateTemporaryAccessKeyByAgencyRequest{}
-	domainNameAssumeRoleIdentityAssumerole := c.AssumeRoleDomain
-	durationSecondsAssumeRoleIdentityAssumerole := assumeRoleDuration
+	domainNameAssumeRoleIdentityAssumerole := role.DomainName
+	durationSecondsAssumeRoleIdentityAssumerole := role.durationSeconds()
 	assumeRoleIdentity := &iam_model.IdentityAssumerole{
-		AgencyName:      c.AssumeRoleAgency,
+		AgencyName:      role.AgencyName,
 		DomainName:      &domainNameAssumeRoleIdentityAssumerole,
 		DurationSeconds: &durationSecondsAssumeRoleIdentityAssumerole,
 	}
@@ -329,26 +412,46 @@ func buildClientByAgency(c *Config) error {
 	}
 	response, err := client.CreateTemporaryAccessKeyByAgency(request)
 	if err != nil {
-		return fmt.Errorf("Error Creating temporary accesskey by agency: %s", err)
+		return nil, fmt.Errorf("Error Creating temporary accesskey by agency: %s", err)
+	}
+	if response.Credential == nil {
+		return nil, fmt.Errorf("Error Creating temporary accesskey by agency: credential is not found in API response")
 	}
-	c.AccessKey, c.SecretKey, c.SecurityToken = response.Credential.Access, response.Credential.Secret, response.Credential.Securitytoken
 
-	return buildClientByAKSK(c)
+	expiresAt, err := time.Parse(time.RFC3339, response.Credential.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the expiration time of the temporary accesskey: %s", err)
+	}
+	return &Credentials{
+		AccessKey:     response.Credential.Access,
+		SecretKey:     response.Credential.Secret,
+		SecurityToken: response.Credential.Securitytoken,
+		ExpiresAt:     expiresAt,
+	}, nil
 }
 
-func buildClientByAgencyV5(c *Config) error {
-	client, err := c.NewServiceClient("sts", c.Region)
+func assumeRoleByAgencyV5(c *Config, role AssumeRole) (*Credentials, error) {
+	client, err := c.newServiceClient("sts", c.Region)
 	if err != nil {
-		return fmt.Errorf("Error creating Huaweicloud IAM V5 client: %s", err)
+		return nil, fmt.Errorf("Error creating Huaweicloud IAM V5 client: %s", err)
 	}
 
+	// sign the request with the current AK/SK of the Config, which may be the temporary AK/SK of the previous agency
+	providerClient := *client.ProviderClient
+	providerClient.AKSKAuthOptions.AccessKey = c.AccessKey
+	providerClient.AKSKAuthOptions.SecretKey = c.SecretKey
+	providerClient.AKSKAuthOptions.SecurityToken = c.SecurityToken
+	client.ProviderClient = &providerClient
+
 	createAssumeHttpUrl := "v5/agencies/assume"
 	createAssumePath := client.Endpoint + createAssumeHttpUrl
-	agencyUrn := "iam::" + c.AssumeRoleDomainID + ":agency:" + c.AssumeRoleAgency
+	agencyUrn := "iam::" + role.DomainID + ":agency:" + role.AgencyName
 	createAssumeOpts := map[string]interface{}{
-		"duration_seconds":    assumeRoleDuration,
+		"duration_seconds":    role.durationSeconds(),
 		"agency_urn":          agencyUrn,
-		"agency_session_name": c.AssumeRoleAgency,
+		"agency_session_name": role.AgencyName,
+		"policy":              utils.ValueIgnoreEmpty(role.SessionPolicy),
+		"tags":                buildAssumeRoleSessionTags(role.SessionTags),
 	}
 	createAssumeOpt := golangsdk.RequestOpts{
 		KeepResponseBody: true,
@@ -356,101 +459,129 @@ func buildClientByAgencyV5(c *Config) error {
 	}
 	createAssumeResp, err := client.Request("POST", createAssumePath, &createAssumeOpt)
 	if err != nil {
-		return fmt.Errorf("error creating IAM agency assume: %s", err)
+		return nil, fmt.Errorf("error creating IAM agency assume: %s", err)
 	}
 	createAssumeRespBody, err := utils.FlattenResponse(createAssumeResp)
 	if err != nil {
-		return fmt.Errorf("error extracting IAM agency assume response: %s", err)
+		return nil, fmt.Errorf("error extracting IAM agency assume response: %s", err)
 	}
 
-	accessKey, err := jmespath.Search("credentials.access_key_id", createAssumeRespBody)
-	if err != nil {
-		return fmt.Errorf("error fetching assume credentials: access_key_id is not found in API response")
+	accessKey := utils.PathSearch("credentials.access_key_id", createAssumeRespBody, "").(string)
+	if accessKey == "" {
+		return nil, fmt.Errorf("error fetching assume credentials: access_key_id is not found in API response")
 	}
-	secretKey, err := jmespath.Search("credentials.secret_access_key", createAssumeRespBody)
-	if err != nil {
-		return fmt.Errorf("error fetching assume credentials: secret_access_id is not found in API response")
+	secretKey := utils.PathSearch("credentials.secret_access_key", createAssumeRespBody, "").(string)
+	if secretKey == "" {
+		return nil, fmt.Errorf("error fetching assume credentials: secret_access_key is not found in API response")
+	}
+	securityToken := utils.PathSearch("credentials.security_token", createAssumeRespBody, "").(string)
+	if securityToken == "" {
+		return nil, fmt.Errorf("error fetching assume credentials: security_token is not found in API response")
 	}
-	securityToken, err := jmespath.Search("credentials.security_token", createAssumeRespBody)
+	expiresAt, err := time.Parse(time.RFC3339,
+		utils.PathSearch("credentials.expires_at", createAssumeRespBody, "").(string))
 	if err != nil {
-		return fmt.Errorf("error fetching assume credentials: security_token is not found in API response")
+		return nil, fmt.Errorf("error parsing the expiration time of the assume credentials: %s", err)
 	}
-	c.AccessKey, c.SecretKey, c.SecurityToken = accessKey.(string), secretKey.(string), securityToken.(string)
 
-	return buildClientByAKSK(c)
+	return &Credentials{
+		AccessKey:     accessKey,
+		SecretKey:     secretKey,
+		SecurityToken: securityToken,
+		ExpiresAt:     expiresAt,
+	}, nil
 }
 
-func (c *Config) reloadSecurityKey() error {
-	err := getAuthConfigByMeta(c)
-	if err != nil {
-		return fmt.Errorf("Error reloading Auth credentials from ECS Metadata API: %s", err)
+func buildAssumeRoleSessionTags(sessionTags map[string]string) []map[string]interface{} {
+	if len(sessionTags) == 0 {
+		return nil
 	}
-	log.Printf("Successfully reload metadata security key, which will expire at: %s", c.SecurityKeyExpiresAt)
-	return buildClientByAKSK(c)
-}
 
-func getAuthConfigByMeta(c *Config) error {
-	req, err := http.NewRequest("GET", securityKeyURL, nil)
-	if err != nil {
-		return fmt.Errorf("Error building metadata API request: %s", err.Error())
+	result := make([]map[string]interface{}, 0, len(sessionTags))
+	for k, v := range sessionTags {
+		result = append(result, map[string]interface{}{
+			"key":   k,
+			"value": v,
+		})
 	}
+	return result
+}
 
-	httpClient := &http.Client{}
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("Error requesting metadata API: %s", err.Error())
-	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Error requesting metadata API: status code = %d", resp.StatusCode)
+// reloadSecurityKey refreshes the temporary AK/SK from the credential source which they were retrieved from, and
+// assumes the agencies again if any.
+// The clients are not rebuilt, the requests signed with the previous AK/SK will be signed again with the new one by
+// credentialRoundTripper, so that the service clients in use (e.g. waiting for a long-running job) can still work.
+func (c *Config) reloadSecurityKey() error {
+	provider := c.credentialProvider
+	if provider == nil {
+		provider = &MetadataCredentialProvider{}
 	}
 
-	var parsedBody interface{}
-
-	defer resp.Body.Close()
-	rawBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("Error parsing metadata API response: %s", err.Error())
+	// the credentials are reloaded with a copy of the Config, so that the AK/SK in use are not changed before all
+	// agencies are assumed again
+	tmp := *c
+	if err := tmp.retrieveCredentials(provider); err != nil {
+		return fmt.Errorf("Error reloading Auth credentials: %s", err)
 	}
+	for _, role := range c.AssumeRoles {
+		creds, err := assumeRole(&tmp, role)
+		if err != nil {
+			return fmt.Errorf("Error reloading Auth credentials: %s", err)
+		}
+		tmp.AccessKey, tmp.SecretKey, tmp.SecurityToken = creds.AccessKey, creds.SecretKey, creds.SecurityToken
+		tmp.SecurityKeyExpiresAt = creds.ExpiresAt
+	}
+
+	c.AccessKey, c.SecretKey, c.SecurityToken = tmp.AccessKey, tmp.SecretKey, tmp.SecurityToken
+	c.SecurityKeyExpiresAt = tmp.SecurityKeyExpiresAt
+	c.credentialProvider = tmp.credentialProvider
+	c.signingCredentials.update(&Credentials{
+		AccessKey:     c.AccessKey,
+		SecretKey:     c.SecretKey,
+		SecurityToken: c.SecurityToken,
+		ExpiresAt:     c.SecurityKeyExpiresAt,
+	})
+
+	log.Printf("Successfully reload security key from %s, which will expire at: %s", provider.Name(),
+		c.SecurityKeyExpiresAt)
+	return nil
+}
 
-	err = json.Unmarshal(rawBody, &parsedBody)
-	if err != nil {
-		return fmt.Errorf("Error unmarshal metadata API, agency_name is empty: %s", err.Error())
+// reloadSecurityKeyIfExpiring reloads the temporary AK/SK if they will expire in keyExpiresDuration seconds.
+// If wait is false and the AK/SK are being reloaded by another request, it returns immediately and the current AK/SK
+// which are still valid for a while will be used.
+func (c *Config) reloadSecurityKeyIfExpiring(wait bool) error {
+	if c.SecurityKeyLock == nil {
+		return nil
 	}
 
-	expiresAt, err := jmespath.Search("credential.expires_at", parsedBody)
-	if err != nil {
-		return fmt.Errorf("Error fetching metadata expires_at: %s", err.Error())
-	}
-	accessKey, err := jmespath.Search("credential.access", parsedBody)
-	if err != nil {
-		return fmt.Errorf("Error fetching metadata access: %s", err.Error())
-	}
-	secretKey, err := jmespath.Search("credential.secret", parsedBody)
-	if err != nil {
-		return fmt.Errorf("Error fetching metadata secret: %s", err.Error())
-	}
-	securityToken, err := jmespath.Search("credential.securitytoken", parsedBody)
-	if err != nil {
-		return fmt.Errorf("Error fetching metadata securitytoken: %s", err.Error())
-	}
+	// the Config of a resource operation uses the security key reloaded by the Config of the provider, which is
+	// shared by all operations
+	if c.root != nil {
+		if err := c.root.reloadSecurityKeyIfExpiring(wait); err != nil {
+			return err
+		}
+		if wait {
+			c.SecurityKeyLock.Lock()
+		} else if !c.SecurityKeyLock.TryLock() {
+			return nil
+		}
+		defer c.SecurityKeyLock.Unlock()
 
-	if accessKey == nil || secretKey == nil || securityToken == nil || expiresAt == nil {
-		return fmt.Errorf("Error fetching metadata authentication information")
+		c.AccessKey, c.SecretKey, c.SecurityToken = c.root.AccessKey, c.root.SecretKey, c.root.SecurityToken
+		c.SecurityKeyExpiresAt = c.root.SecurityKeyExpiresAt
+		return nil
 	}
-	expairesTime, err := time.Parse(time.RFC3339, expiresAt.(string))
-	if err != nil {
-		return err
-	}
-	c.AccessKey, c.SecretKey, c.SecurityToken, c.SecurityKeyExpiresAt = accessKey.(string), secretKey.(string), securityToken.(string), expairesTime
 
-	return nil
-}
+	if wait {
+		c.SecurityKeyLock.Lock()
+	} else if !c.SecurityKeyLock.TryLock() {
+		return nil
+	}
+	defer c.SecurityKeyLock.Unlock()
 
-func buildClientByMeta(c *Config) error {
-	err := getAuthConfigByMeta(c)
-	if err != nil {
-		return fmt.Errorf("Error fetching Auth credentials from ECS Metadata API, AkSk or ECS agency must be provided: %s", err)
+	if c.SecurityKeyExpiresAt.IsZero() || time.Now().Unix()+keyExpiresDuration <= c.SecurityKeyExpiresAt.Unix() {
+		return nil
 	}
-	log.Printf("[DEBUG] Successfully got metadata security key, which will expire at: %s", c.SecurityKeyExpiresAt)
-	return buildClientByAKSK(c)
+	return c.reloadSecurityKey()
 }
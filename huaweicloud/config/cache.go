@@ -0,0 +1,189 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCacheTTL is the default validity period of the entries in the cache directory
+	DefaultCacheTTL = time.Hour
+
+	// the lock of the cache file is waited for at most cacheLockTimeout, and the lock file is considered stale
+	// (e.g. the process holding it was killed) if it's older than cacheStaleLockAge
+	cacheLockTimeout  = 2 * time.Second
+	cacheStaleLockAge = 10 * time.Second
+	cacheLockInterval = 50 * time.Millisecond
+
+	cacheKeyDomainID    = "domain_id"
+	cacheKeyWebsiteType = "website_type"
+	cacheKeyUserID      = "user_id/"
+	cacheKeyProjectID   = "project_id/"
+)
+
+type cacheEntry struct {
+	Value     string    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// diskCache is a persistent cache of the lookup results which rarely change, e.g. the domain ID and the project
+// IDs, so that they are not queried from IAM by every terraform process. Each credential has its own cache file,
+// the file is replaced atomically, so it can be read without locking, and the updates of the concurrent processes
+// are serialized by a lock file.
+type diskCache struct {
+	mu   sync.Mutex
+	path string
+	ttl  time.Duration
+}
+
+// newDiskCache returns nil if the cache directory is not specified, and all methods of a nil cache do nothing.
+func newDiskCache(dir string, ttl time.Duration, fingerprint string) *diskCache {
+	if dir == "" {
+		return nil
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &diskCache{
+		path: filepath.Join(dir, fingerprint+".json"),
+		ttl:  ttl,
+	}
+}
+
+func (dc *diskCache) load() map[string]cacheEntry {
+	entries := make(map[string]cacheEntry)
+	content, err := os.ReadFile(dc.path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("[WARN] failed to read the cache file %s: %s", dc.path, err)
+		}
+		return entries
+	}
+	if err := json.Unmarshal(content, &entries); err != nil {
+		log.Printf("[WARN] ignore the invalid cache file %s: %s", dc.path, err)
+		return make(map[string]cacheEntry)
+	}
+	return entries
+}
+
+// get returns the unexpired value of the key.
+func (dc *diskCache) get(key string) (string, bool) {
+	if dc == nil {
+		return "", false
+	}
+
+	entry, ok := dc.load()[key]
+	if !ok || time.Now().After(entry.ExpiresAt) {
+		return "", false
+	}
+	log.Printf("[DEBUG] get %s from the cache: %s", key, entry.Value)
+	return entry.Value, true
+}
+
+// set stores the values, the cache is best-effort, so the errors are only logged.
+func (dc *diskCache) set(values map[string]string) {
+	if dc == nil || len(values) == 0 {
+		return
+	}
+
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	if err := dc.update(values); err != nil {
+		log.Printf("[WARN] failed to update the cache file %s: %s", dc.path, err)
+	}
+}
+
+func (dc *diskCache) update(values map[string]string) error {
+	if err := os.MkdirAll(filepath.Dir(dc.path), 0700); err != nil {
+		return err
+	}
+
+	unlock, err := dc.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	now := time.Now()
+	entries := dc.load()
+	for k, entry := range entries {
+		if now.After(entry.ExpiresAt) {
+			delete(entries, k)
+		}
+	}
+	for k, v := range values {
+		entries[k] = cacheEntry{Value: v, ExpiresAt: now.Add(dc.ttl)}
+	}
+
+	content, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(dc.path), filepath.Base(dc.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(content); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), dc.path)
+}
+
+// lock creates the lock file exclusively, which works across processes and platforms.
+func (dc *diskCache) lock() (func(), error) {
+	lockPath := dc.path + ".lock"
+	deadline := time.Now().Add(cacheLockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > cacheStaleLockAge {
+			log.Printf("[DEBUG] remove the stale lock file %s", lockPath)
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timeout waiting for the lock file %s", lockPath)
+		}
+		time.Sleep(cacheLockInterval)
+	}
+}
+
+// credentialFingerprint identifies the account and the user of the credentials, the secrets are hashed and never
+// written to the cache directory.
+func (c *Config) credentialFingerprint() string {
+	parts := []string{
+		c.Cloud, c.IdentityEndpoint, c.AccessKey, c.Token, c.DomainID, c.DomainName, c.UserID, c.Username,
+		c.TenantID, c.TenantName, c.AgencyDomainName, c.AgencyName, c.DelegatedProject, c.SharedConfigFile,
+		c.Profile, c.CredentialProcess, c.WebIdentityTokenFile, c.WebIdentityProvider,
+	}
+	for _, role := range c.AssumeRoles {
+		parts = append(parts, role.DomainID, role.DomainName, role.AgencyName)
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+func TestDiskCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	cache := newDiskCache(dir, time.Hour, "fingerprint")
+
+	_, ok := cache.get(cacheKeyDomainID)
+	th.AssertEquals(t, false, ok)
+
+	cache.set(map[string]string{cacheKeyDomainID: "domain-id"})
+	value, ok := cache.get(cacheKeyDomainID)
+	th.AssertEquals(t, true, ok)
+	th.AssertEquals(t, "domain-id", value)
+
+	// the entries are merged, and shared by the caches with the same fingerprint
+	other := newDiskCache(dir, time.Hour, "fingerprint")
+	other.set(map[string]string{cacheKeyProjectID + "region-0": "project-0"})
+	value, ok = cache.get(cacheKeyDomainID)
+	th.AssertEquals(t, true, ok)
+	th.AssertEquals(t, "domain-id", value)
+	value, ok = cache.get(cacheKeyProjectID + "region-0")
+	th.AssertEquals(t, true, ok)
+	th.AssertEquals(t, "project-0", value)
+
+	// the caches with different fingerprints are isolated
+	_, ok = newDiskCache(dir, time.Hour, "another").get(cacheKeyDomainID)
+	th.AssertEquals(t, false, ok)
+
+	// the expired entries are ignored
+	expired := newDiskCache(dir, time.Nanosecond, "expired")
+	expired.set(map[string]string{cacheKeyDomainID: "domain-id"})
+	time.Sleep(time.Millisecond)
+	_, ok = expired.get(cacheKeyDomainID)
+	th.AssertEquals(t, false, ok)
+
+	// the nil cache does nothing
+	var disabled *diskCache
+	disabled.set(map[string]string{cacheKeyDomainID: "domain-id"})
+	_, ok = disabled.get(cacheKeyDomainID)
+	th.AssertEquals(t, false, ok)
+	th.AssertEquals(t, true, newDiskCache("", time.Hour, "fingerprint") == nil)
+}
+
+func TestDiskCacheConcurrentUpdates(t *testing.T) {
+	dir := t.TempDir()
+
+	// each cache simulates a process, the updates should not be lost
+	var wg sync.WaitGroup
+	for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			newDiskCache(dir, time.Hour, "fingerprint").set(map[string]string{key: key})
+		}(key)
+	}
+	wg.Wait()
+
+	cache := newDiskCache(dir, time.Hour, "fingerprint")
+	for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		value, ok := cache.get(key)
+		th.AssertEquals(t, true, ok)
+		th.AssertEquals(t, key, value)
+	}
+	_, err := os.Stat(cache.path + ".lock")
+	th.AssertEquals(t, true, os.IsNotExist(err))
+
+	// the stale lock file is removed
+	th.AssertNoErr(t, os.WriteFile(cache.path+".lock", nil, 0600))
+	staleTime := time.Now().Add(-2 * cacheStaleLockAge)
+	th.AssertNoErr(t, os.Chtimes(cache.path+".lock", staleTime, staleTime))
+	cache.set(map[string]string{"i": "i"})
+	value, ok := cache.get("i")
+	th.AssertEquals(t, true, ok)
+	th.AssertEquals(t, "i", value)
+}
+
+func TestCredentialFingerprint(t *testing.T) {
+	cfg := &Config{Cloud: "myhuaweicloud.com", AccessKey: "ak", SecretKey: "sk"}
+	fingerprint := cfg.credentialFingerprint()
+	th.AssertEquals(t, 64, len(fingerprint))
+
+	// the secret key is not a part of the fingerprint
+	cfg.SecretKey = "another-sk"
+	th.AssertEquals(t, fingerprint, cfg.credentialFingerprint())
+
+	cfg.AssumeRoles = []AssumeRole{{AgencyName: "agency", DomainName: "domain"}}
+	if cfg.credentialFingerprint() == fingerprint {
+		t.Error("the fingerprint should be changed by the assumed agency")
+	}
+}
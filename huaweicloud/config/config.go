@@ -5,7 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"math"
+	"net"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -49,9 +50,6 @@ type Config struct {
 	TenantName          string
 	Token               string
 	SecurityToken       string
-	AssumeRoleAgency    string
-	AssumeRoleDomain    string
-	AssumeRoleDomainID  string
 	Cloud               string
 	MaxRetries          int
 	TerraformVersion    string
@@ -60,8 +58,25 @@ type Config struct {
 	SharedConfigFile    string
 	Profile             string
 
-	// metadata security key expires at
+	// CredentialProcess is the external command which outputs the temporary AK/SK in JSON format
+	CredentialProcess string
+	// WebIdentityTokenFile and WebIdentityProvider are used to exchange the OpenID Connect ID token for the
+	// temporary AK/SK through IAM identity federation
+	WebIdentityTokenFile string
+	WebIdentityProvider  string
+	// CredentialProviders is the credential provider chain, DefaultCredentialProviders() is used if it's empty
+	CredentialProviders []CredentialProvider
+	// credentialProvider is the credential source which the current AK/SK are retrieved from
+	credentialProvider CredentialProvider
+
+	// AssumeRoles is the agencies to be assumed in order, each agency is assumed with the credentials of the
+	// previous one
+	AssumeRoles []AssumeRole
+
+	// temporary security key expires at
 	SecurityKeyExpiresAt time.Time
+	// signingCredentials is used to sign again the requests which are signed with the expired security key
+	signingCredentials *signingCredentials
 
 	HwClient     *golangsdk.ProviderClient
 	DomainClient *golangsdk.ProviderClient
@@ -71,6 +86,10 @@ type Config struct {
 	websiteType string
 	// the custom endpoints used to override the default endpoint URL
 	Endpoints map[string]string
+	// EndpointDiscovery indicates whether to discover the endpoints of the provider-level region from the IAM
+	// service catalog
+	EndpointDiscovery   bool
+	discoveredEndpoints map[string]string
 
 	// RegionProjectIDMap is a map which stores the region-projectId pairs,
 	// and region name will be the key and projectID will be the value in this map.
@@ -96,12 +115,47 @@ type Config struct {
 	Metadata any
 
 	EnableForceNew bool
+
+	// DefaultTags is the provider-level tags which will be merged into the tags of all taggable resources
+	DefaultTags map[string]string
+	// IgnoreTagKeys and IgnoreTagKeyPrefixes are the provider-level tag keys and key prefixes which are managed
+	// externally and should be ignored by all resources
+	IgnoreTagKeys        []string
+	IgnoreTagKeyPrefixes []string
+
+	// RateLimits is the client-side rate limits of the services
+	RateLimits  []RateLimit
+	rateLimiter *rateLimiter
+
+	// PreflightQuotaChecks indicates whether to check the quotas of the resources being created during the plan
+	PreflightQuotaChecks bool
+	quotaTracker         *quotaTracker
+
+	// CacheDir is the directory to cache the domain ID, user ID, project IDs and website type across the terraform
+	// processes, the cache is disabled if it's empty, and the entries expire after CacheTTL
+	CacheDir string
+	CacheTTL time.Duration
+	cache    *diskCache
+
+	// root is the Config of the provider which the Config of a resource operation is copied from, and
+	// operationContext is the context of the operation, see withOperationContext
+	root             *Config
+	operationContext context.Context
+
+	// Recorder records or replays the API interactions in the acceptance tests, the requests are sent directly if
+	// it's nil
+	Recorder *Recorder
 }
 
 func (c *Config) LoadAndValidate() error {
 	if c.MaxRetries < 0 {
 		return fmt.Errorf("max_retries should be a positive value")
 	}
+	c.signingCredentials = newSigningCredentials()
+	c.rateLimiter = newRateLimiter(c.RateLimits)
+	c.quotaTracker = newQuotaTracker(c.PreflightQuotaChecks)
+	c.cache = newDiskCache(c.CacheDir, c.CacheTTL, c.credentialFingerprint())
+	c.Recorder.registerConfig(c)
 
 	err := buildClient(c)
 	if err != nil {
@@ -113,26 +167,36 @@ func (c *Config) LoadAndValidate() error {
 	}
 
 	// Assume role
-	if c.AssumeRoleAgency != "" {
-		if c.AssumeRoleDomainID != "" {
-			err = buildClientByAgencyV5(c)
-		} else {
-			err = buildClientByAgency(c)
-		}
-		if err != nil {
+	if len(c.AssumeRoles) > 0 {
+		if err := buildClientByAgencies(c); err != nil {
 			return err
 		}
 	}
+	c.signingCredentials.update(&Credentials{
+		AccessKey:     c.AccessKey,
+		SecretKey:     c.SecretKey,
+		SecurityToken: c.SecurityToken,
+		ExpiresAt:     c.SecurityKeyExpiresAt,
+	})
 
 	if c.HwClient != nil && c.HwClient.ProjectID != "" {
 		c.RegionProjectIDMap[c.Region] = c.HwClient.ProjectID
 	}
 	log.Printf("[DEBUG] init region and project map: %#v", c.RegionProjectIDMap)
 
+	// only the values queried from IAM are cached, the cached values are not written back, so that they expire
+	fetchedIdentities := make(map[string]string)
+
 	// set DomainID for IAM resource
 	if c.DomainID == "" {
-		if domainID, err := c.getDomainID(); err == nil {
+		if domainID, ok := c.cache.get(cacheKeyDomainID); ok {
+			c.DomainID = domainID
+			if c.DomainClient.AKSKAuthOptions.AccessKey != "" {
+				c.DomainClient.AKSKAuthOptions.DomainID = c.DomainID
+			}
+		} else if domainID, err := c.getDomainID(); err == nil {
 			c.DomainID = domainID
+			fetchedIdentities[cacheKeyDomainID] = domainID
 
 			// update DomainClient.AKSKAuthOptions
 			if c.DomainClient.AKSKAuthOptions.AccessKey != "" {
@@ -144,13 +208,25 @@ func (c *Config) LoadAndValidate() error {
 	}
 
 	if c.UserID == "" && c.Username != "" {
-		if userID, err := c.getUserIDbyName(c.Username); err == nil {
+		if userID, ok := c.cache.get(cacheKeyUserID + c.Username); ok {
 			c.UserID = userID
+		} else if userID, err := c.getUserIDbyName(c.Username); err == nil {
+			c.UserID = userID
+			fetchedIdentities[cacheKeyUserID+c.Username] = userID
 		} else {
 			log.Printf("[WARN] get user id failed: %s", err)
 		}
 	}
 
+	c.cache.set(fetchedIdentities)
+
+	if c.EndpointDiscovery {
+		// the endpoints built from the service catalog are still used if the discovery fails
+		if err := c.discoverEndpoints(); err != nil {
+			log.Printf("[WARN] failed to discover the endpoints: %s", err)
+		}
+	}
+
 	return nil
 }
 
@@ -165,6 +241,11 @@ func (c *Config) LoadAndValidate() error {
 // we can call the probe API and parse the response body to decide whether the account belongs to International website or not.
 // we select https://support.huaweicloud.com/intl/zh-cn/api-oce/zh-cn_topic_0000001256679455.html as the probe API.
 func (c *Config) SetWebsiteType() error {
+	if websiteType, ok := c.cache.get(cacheKeyWebsiteType); ok {
+		c.websiteType = websiteType
+		return nil
+	}
+
 	bssClient, err := c.NewServiceClient("bss", c.Region)
 	if err != nil {
 		return fmt.Errorf("error creating BSS client: %s", err)
@@ -189,12 +270,14 @@ func (c *Config) SetWebsiteType() error {
 			if resp.ErrorCode == "CBC.0150" {
 				log.Printf("[DEBUG] the current account belongs to %s website", InternationalSite)
 				c.websiteType = InternationalSite
+				c.cache.set(map[string]string{cacheKeyWebsiteType: c.websiteType})
 				return nil
 			}
 		}
 		return err
 	}
 
+	c.cache.set(map[string]string{cacheKeyWebsiteType: c.websiteType})
 	return nil
 }
 
@@ -216,13 +299,9 @@ func (c *Config) SetServiceEndpoint(service, endpoint string) {
 }
 
 func retryBackoffFunc(ctx context.Context, respErr *golangsdk.ErrUnexpectedResponseCode, e error, retries uint) error {
-	minutes := int(math.Pow(2, float64(retries)))
-	if minutes > 30 { // won't wait more than 30 minutes
-		minutes = 30
-	}
-
-	log.Printf("[WARN] Received StatusTooManyRequests response code, try to sleep %d minutes", minutes)
-	sleep := time.Duration(minutes) * time.Minute
+	// the rate of the throttled service is also decreased by the rate limiter if it's configured
+	sleep := jitteredBackoff(500*time.Millisecond, 30*time.Second, retries)
+	log.Printf("[WARN] Received StatusTooManyRequests response code, try to sleep %s", sleep)
 
 	if ctx != nil {
 		select {
@@ -240,6 +319,11 @@ func retryBackoffFunc(ctx context.Context, respErr *golangsdk.ErrUnexpectedRespo
 
 func getObsEndpoint(c *Config, region string) string {
 	if endpoint, ok := c.Endpoints["obs"]; ok {
+		// the endpoint with an IP address, e.g. a local mock server, has no region to replace
+		if u, err := url.Parse(endpoint); err == nil && net.ParseIP(u.Hostname()) != nil {
+			return endpoint
+		}
+
 		// replace the region in customizing OBS endpoint
 		subparts := strings.Split(endpoint, ".")
 		if len(subparts) >= 3 && subparts[1] != region {
@@ -248,9 +332,18 @@ func getObsEndpoint(c *Config, region string) string {
 		}
 		return endpoint
 	}
+	if endpoint, ok := c.getDiscoveredEndpoint("obs", region); ok {
+		return endpoint
+	}
 	return fmt.Sprintf("https://obs.%s.%s/", region, c.Cloud)
 }
 
+// ObjectStorageEndpoint returns the OBS endpoint of the region, it's used to send the requests which are not
+// supported by the OBS SDK.
+func (c *Config) ObjectStorageEndpoint(region string) string {
+	return getObsEndpoint(c, region)
+}
+
 func (c *Config) ObjectStorageClientWithSignature(region string) (*obs.ObsClient, error) {
 	if c.AccessKey == "" || c.SecretKey == "" {
 		return nil, fmt.Errorf("missing credentials for OBS, need access_key and secret_key values for provider")
@@ -274,17 +367,8 @@ func (c *Config) ObjectStorageClient(region string) (*obs.ObsClient, error) {
 		return nil, fmt.Errorf("missing credentials for OBS, need access_key and secret_key values for provider")
 	}
 
-	if !c.SecurityKeyExpiresAt.IsZero() {
-		c.SecurityKeyLock.Lock()
-		defer c.SecurityKeyLock.Unlock()
-		timeNow := time.Now().Unix()
-		expairesAtInt := c.SecurityKeyExpiresAt.Unix()
-		if timeNow+keyExpiresDuration > expairesAtInt {
-			err := c.reloadSecurityKey()
-			if err != nil {
-				return nil, err
-			}
-		}
+	if err := c.reloadSecurityKeyIfExpiring(true); err != nil {
+		return nil, err
 	}
 
 	clientConfigure := obs.WithHttpClient(&c.DomainClient.HTTPClient)
@@ -311,26 +395,17 @@ func buildObsUserAgent() string {
 // If you want to add new ServiceClient, please make sure the catalog was already in allServiceCatalog.
 // the endpoint likes https://{Name}.{Region}.myhuaweicloud.com/{Version}/{project_id}/{ResourceBase}
 func (c *Config) NewServiceClient(srv, region string) (*golangsdk.ServiceClient, error) {
-	serviceCatalog, ok := allServiceCatalog[srv]
-	if !ok {
-		return nil, fmt.Errorf("service type %s is invalid or not supportted", srv)
-	}
-	// update the service catalog name if necessary
-	if name := getServiceCatalogNameByRegion(srv, region); name != "" {
-		serviceCatalog.Name = name
+	if err := c.reloadSecurityKeyIfExpiring(true); err != nil {
+		return nil, err
 	}
+	return c.newServiceClient(srv, region)
+}
 
-	if !c.SecurityKeyExpiresAt.IsZero() {
-		c.SecurityKeyLock.Lock()
-		defer c.SecurityKeyLock.Unlock()
-		timeNow := time.Now().Unix()
-		expairesAtInt := c.SecurityKeyExpiresAt.Unix()
-		if timeNow+keyExpiresDuration > expairesAtInt {
-			err := c.reloadSecurityKey()
-			if err != nil {
-				return nil, err
-			}
-		}
+// newServiceClient creates a ServiceClient without checking the expiration of the security key.
+func (c *Config) newServiceClient(srv, region string) (*golangsdk.ServiceClient, error) {
+	serviceCatalog, err := serviceCatalogByRegion(srv, region)
+	if err != nil {
+		return nil, err
 	}
 
 	client := c.HwClient
@@ -342,12 +417,22 @@ func (c *Config) NewServiceClient(srv, region string) (*golangsdk.ServiceClient,
 		if region != "" && region != c.Region {
 			return nil, fmt.Errorf("Resource-level region must be the same as Provider-level region when using customizing endpoints")
 		}
+		c.rateLimiter.register(srv, serviceCatalog.Name, c.Region, endpoint)
+		registerServiceEndpoint(serviceCatalog.Name, c.Region, endpoint)
 		return c.newServiceClientByEndpoint(client, srv, endpoint)
 	}
-	return c.newServiceClientByName(client, serviceCatalog, region)
+
+	sc, err := c.newServiceClientByName(client, srv, serviceCatalog, region)
+	if err != nil {
+		return nil, err
+	}
+	c.rateLimiter.register(srv, serviceCatalog.Name, region, sc.Endpoint)
+	registerServiceEndpoint(serviceCatalog.Name, region, sc.Endpoint)
+	return sc, nil
 }
 
-func (c *Config) newServiceClientByName(client *golangsdk.ProviderClient, catalog ServiceCatalog, region string) (*golangsdk.ServiceClient, error) {
+func (c *Config) newServiceClientByName(client *golangsdk.ProviderClient, srv string, catalog ServiceCatalog,
+	region string) (*golangsdk.ServiceClient, error) {
 	if catalog.Name == "" {
 		return nil, fmt.Errorf("must specify the service name")
 	}
@@ -381,24 +466,33 @@ func (c *Config) newServiceClientByName(client *golangsdk.ProviderClient, catalo
 		ProviderClient: clone,
 	}
 
-	if catalog.Scope == "global" && !c.RegionClient {
-		sc.Endpoint = fmt.Sprintf("https://%s.%s/", catalog.Name, c.Cloud)
-	} else {
-		sc.Endpoint = fmt.Sprintf("https://%s.%s.%s/", catalog.Name, region, c.Cloud)
-	}
+	sc.Endpoint = c.serviceEndpointByName(srv, catalog, region)
+	sc.ResourceBase = buildResourceBase(catalog, sc.Endpoint, projectID)
+	return sc, nil
+}
 
-	sc.ResourceBase = sc.Endpoint
-	if catalog.Version != "" {
-		sc.ResourceBase = sc.ResourceBase + catalog.Version + "/"
+// serviceEndpointByName returns the discovered endpoint of the service in the region, or the endpoint built from
+// the catalog if it's not discovered.
+func (c *Config) serviceEndpointByName(srv string, catalog ServiceCatalog, region string) string {
+	if endpoint, ok := c.getDiscoveredEndpoint(srv, region); ok {
+		return endpoint
 	}
-	if !catalog.WithOutProjectID {
-		sc.ResourceBase = sc.ResourceBase + projectID + "/"
+	return buildCatalogEndpoint(catalog, region, c.Cloud, catalog.Scope == "global" && !c.RegionClient)
+}
+
+// BuildServiceEndpoint returns the endpoint and the resource base of a service in the region which are used by the
+// service clients of the config, the endpoint is the same as the one returned by GetServiceEndpoint.
+func (c *Config) BuildServiceEndpoint(srv, region, projectID string) (endpoint, resourceBase string, err error) {
+	catalog, err := serviceCatalogByRegion(srv, region)
+	if err != nil {
+		return "", "", err
 	}
-	if catalog.ResourceBase != "" {
-		sc.ResourceBase = sc.ResourceBase + catalog.ResourceBase + "/"
+	if !catalog.WithOutProjectID && projectID == "" {
+		return "", "", fmt.Errorf("the project ID is required by the project-level service %s", srv)
 	}
 
-	return sc, nil
+	endpoint = GetServiceEndpoint(c, srv, region)
+	return endpoint, buildResourceBase(catalog, endpoint, projectID), nil
 }
 
 // newServiceClientByEndpoint returns a ServiceClient which the endpoint was initialized by customer
@@ -412,17 +506,7 @@ func (c *Config) newServiceClientByEndpoint(client *golangsdk.ProviderClient, sr
 	sc := &golangsdk.ServiceClient{
 		ProviderClient: client,
 		Endpoint:       endpoint,
-	}
-
-	sc.ResourceBase = sc.Endpoint
-	if catalog.Version != "" {
-		sc.ResourceBase = sc.ResourceBase + catalog.Version + "/"
-	}
-	if !catalog.WithOutProjectID {
-		sc.ResourceBase = sc.ResourceBase + client.ProjectID + "/"
-	}
-	if catalog.ResourceBase != "" {
-		sc.ResourceBase = sc.ResourceBase + catalog.ResourceBase + "/"
+		ResourceBase:   buildResourceBase(catalog, endpoint, client.ProjectID),
 	}
 	return sc, nil
 }
@@ -491,6 +575,11 @@ func (c *Config) getUserIDbyName(name string) (string, error) {
 func (c *Config) loadUserProjects(client *golangsdk.ProviderClient, region string) error {
 
 	log.Printf("[DEBUG] Load project ID for region: %s", region)
+	if projectID, ok := c.cache.get(cacheKeyProjectID + region); ok {
+		c.RegionProjectIDMap[region] = projectID
+		return nil
+	}
+
 	domainID := client.DomainID
 	opts := projects.ListOpts{
 		DomainID: domainID,
@@ -513,10 +602,13 @@ func (c *Config) loadUserProjects(client *golangsdk.ProviderClient, region strin
 		return fmt.Errorf("Wrong name or no access to the region: %s", region)
 	}
 
+	cachedProjects := make(map[string]string, len(all))
 	for _, item := range all {
 		log.Printf("[DEBUG] add %s/%s to region and project map", item.Name, item.ID)
 		c.RegionProjectIDMap[item.Name] = item.ID
+		cachedProjects[cacheKeyProjectID+item.Name] = item.ID
 	}
+	c.cache.set(cachedProjects)
 	return nil
 }
 
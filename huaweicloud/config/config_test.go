@@ -79,4 +79,9 @@ func TestCheckObsEndpoint(t *testing.T) {
 	// the region is not equal to the region in customizing endpoint
 	expected = "https://oss.region-1.myhuaweicloud.com/"
 	th.AssertEquals(t, expected, getObsEndpoint(cfg, "region-1"))
+
+	// the customizing endpoint with an IP address is used as it is
+	cfg.Endpoints["obs"] = "http://127.0.0.1:8080/"
+	expected = "http://127.0.0.1:8080/"
+	th.AssertEquals(t, expected, getObsEndpoint(cfg, "region-1"))
 }
@@ -0,0 +1,500 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmespath/go-jmespath"
+
+	"github.com/chnsz/golangsdk/auth"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/pathorcontents"
+)
+
+const (
+	credentialProcessTimeout = 5 * time.Minute
+	webIdentityDuration      = 3600
+)
+
+// Credentials is the temporary or permanent AK/SK retrieved from a credential source.
+type Credentials struct {
+	AccessKey     string
+	SecretKey     string
+	SecurityToken string
+	// ExpiresAt is the expiration time of the credentials, the zero value means that the credentials never expire.
+	ExpiresAt time.Time
+}
+
+// CredentialProvider is a source of the AK/SK credentials.
+// The providers are tried in order, and the first available one is used to authenticate. If the credentials retrieved
+// have an expiration time, the same provider will be used to refresh them before they expire.
+type CredentialProvider interface {
+	// Name returns the name of the credential source, it's used in the logs and error messages.
+	Name() string
+	// IsAvailable checks whether the credential source is configured.
+	IsAvailable(c *Config) bool
+	// Retrieve retrieves the credentials from the credential source.
+	Retrieve(c *Config) (*Credentials, error)
+}
+
+// DefaultCredentialProviders returns the default credential provider chain, the order is:
+// static credentials (provider arguments, environment variables or shared config file), credential process,
+// web identity token file and ECS metadata API.
+func DefaultCredentialProviders() []CredentialProvider {
+	return []CredentialProvider{
+		&StaticCredentialProvider{},
+		&ProcessCredentialProvider{},
+		&WebIdentityCredentialProvider{},
+		&MetadataCredentialProvider{},
+	}
+}
+
+func buildClientByCredentialProviders(c *Config) error {
+	providers := c.CredentialProviders
+	if len(providers) == 0 {
+		providers = DefaultCredentialProviders()
+	}
+
+	for _, p := range providers {
+		if !p.IsAvailable(c) {
+			continue
+		}
+
+		if err := c.retrieveCredentials(p); err != nil {
+			return err
+		}
+		return buildClientByAKSK(c)
+	}
+	return fmt.Errorf("no valid credential source found, AK/SK, credential process, web identity token file " +
+		"or ECS agency must be provided")
+}
+
+// retrieveCredentials retrieves the credentials from the specified provider and saves them into the Config.
+func (c *Config) retrieveCredentials(p CredentialProvider) error {
+	creds, err := p.Retrieve(c)
+	if err != nil {
+		return fmt.Errorf("error retrieving credentials from %s: %s", p.Name(), err)
+	}
+	if creds.AccessKey == "" || creds.SecretKey == "" {
+		return fmt.Errorf("error retrieving credentials from %s: the access key or secret key is empty", p.Name())
+	}
+
+	c.AccessKey, c.SecretKey, c.SecurityToken = creds.AccessKey, creds.SecretKey, creds.SecurityToken
+	c.SecurityKeyExpiresAt = creds.ExpiresAt
+	c.credentialProvider = p
+
+	if creds.ExpiresAt.IsZero() {
+		log.Printf("[DEBUG] Successfully retrieved credentials from %s", p.Name())
+	} else {
+		log.Printf("[DEBUG] Successfully retrieved credentials from %s, which will expire at: %s", p.Name(),
+			creds.ExpiresAt)
+	}
+	return nil
+}
+
+// StaticCredentialProvider provides the AK/SK which are specified in the provider arguments, environment variables
+// or the shared config file.
+type StaticCredentialProvider struct {
+	// the AK/SK are saved at the first retrieval, as the AK/SK in Config will be replaced by the temporary AK/SK if
+	// any agency is assumed
+	credentials *Credentials
+}
+
+func (*StaticCredentialProvider) Name() string {
+	return "static credentials"
+}
+
+func (*StaticCredentialProvider) IsAvailable(c *Config) bool {
+	return c.AccessKey != "" && c.SecretKey != ""
+}
+
+func (p *StaticCredentialProvider) Retrieve(c *Config) (*Credentials, error) {
+	if p.credentials == nil {
+		p.credentials = &Credentials{
+			AccessKey:     c.AccessKey,
+			SecretKey:     c.SecretKey,
+			SecurityToken: c.SecurityToken,
+		}
+	}
+
+	creds := *p.credentials
+	return &creds, nil
+}
+
+// ProcessCredentialProvider runs an external command and reads the credentials from its standard output.
+// The output must be a JSON object like:
+//
+//	{
+//	  "access_key_id": "xxx",
+//	  "secret_access_key": "xxx",
+//	  "security_token": "xxx",
+//	  "expires_at": "2024-01-01T00:00:00Z"
+//	}
+//
+// The security_token and expires_at are optional, expires_at must be in RFC3339 format.
+type ProcessCredentialProvider struct{}
+
+func (*ProcessCredentialProvider) Name() string {
+	return "credential process"
+}
+
+func (*ProcessCredentialProvider) IsAvailable(c *Config) bool {
+	return c.CredentialProcess != ""
+}
+
+func (*ProcessCredentialProvider) Retrieve(c *Config) (*Credentials, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), credentialProcessTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd.exe", "/C", c.CredentialProcess)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", c.CredentialProcess)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = os.Environ()
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("error running the credential process: %s, stderr: %s", err,
+			strings.TrimSpace(stderr.String()))
+	}
+
+	var output struct {
+		AccessKey     string `json:"access_key_id"`
+		SecretKey     string `json:"secret_access_key"`
+		SecurityToken string `json:"security_token"`
+		ExpiresAt     string `json:"expires_at"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return nil, fmt.Errorf("error parsing the output of the credential process: %s", err)
+	}
+
+	creds := Credentials{
+		AccessKey:     output.AccessKey,
+		SecretKey:     output.SecretKey,
+		SecurityToken: output.SecurityToken,
+	}
+	if output.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, output.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing the expires_at of the credential process output: %s", err)
+		}
+		creds.ExpiresAt = expiresAt
+	}
+	return &creds, nil
+}
+
+// WebIdentityCredentialProvider exchanges an OpenID Connect ID token, which is read from a file, for the temporary
+// AK/SK through IAM identity federation.
+// The token file is read again on every refresh, so that it can be rotated by the external system.
+type WebIdentityCredentialProvider struct{}
+
+func (*WebIdentityCredentialProvider) Name() string {
+	return "web identity token file"
+}
+
+func (*WebIdentityCredentialProvider) IsAvailable(c *Config) bool {
+	return c.WebIdentityTokenFile != "" && c.WebIdentityProvider != ""
+}
+
+func (*WebIdentityCredentialProvider) Retrieve(c *Config) (*Credentials, error) {
+	idToken, _, err := pathorcontents.Read(c.WebIdentityTokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the web identity token file: %s", err)
+	}
+
+	httpClient, err := newCredentialHTTPClient(c)
+	if err != nil {
+		return nil, err
+	}
+	iamEndpoint := strings.TrimSuffix(strings.TrimSuffix(c.IdentityEndpoint, "/"), "/v3")
+
+	// step 1: obtain a federated token with the ID token
+	tokenAuth := map[string]interface{}{
+		"id_token": map[string]interface{}{
+			"id": strings.TrimSpace(idToken),
+		},
+	}
+	if c.DomainID != "" {
+		tokenAuth["scope"] = map[string]interface{}{
+			"domain": map[string]interface{}{"id": c.DomainID},
+		}
+	} else if c.DomainName != "" {
+		tokenAuth["scope"] = map[string]interface{}{
+			"domain": map[string]interface{}{"name": c.DomainName},
+		}
+	}
+	tokenResp, err := doCredentialRequest(httpClient, iamEndpoint+"/v3.0/OS-AUTH/id-token/tokens",
+		map[string]string{"X-Idp-Id": c.WebIdentityProvider}, map[string]interface{}{"auth": tokenAuth})
+	if err != nil {
+		return nil, fmt.Errorf("error obtaining the federated token with the ID token: %s", err)
+	}
+	subjectToken := tokenResp.Header.Get("X-Subject-Token")
+	if subjectToken == "" {
+		return nil, fmt.Errorf("error obtaining the federated token with the ID token: X-Subject-Token is not " +
+			"found in API response")
+	}
+
+	// step 2: obtain the temporary AK/SK with the federated token
+	securityTokenBody := map[string]interface{}{
+		"auth": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"methods": []string{"token"},
+				"token": map[string]interface{}{
+					"id":               subjectToken,
+					"duration_seconds": webIdentityDuration,
+				},
+			},
+		},
+	}
+	securityTokenResp, err := doCredentialRequest(httpClient, iamEndpoint+"/v3.0/OS-CREDENTIAL/securitytokens",
+		nil, securityTokenBody)
+	if err != nil {
+		return nil, fmt.Errorf("error obtaining the temporary AK/SK with the federated token: %s", err)
+	}
+	return parseTemporaryCredentials(securityTokenResp.Body)
+}
+
+// MetadataCredentialProvider retrieves the temporary AK/SK of the ECS agency from the ECS metadata API.
+type MetadataCredentialProvider struct{}
+
+func (*MetadataCredentialProvider) Name() string {
+	return "ECS metadata API"
+}
+
+func (*MetadataCredentialProvider) IsAvailable(*Config) bool {
+	return true
+}
+
+func (*MetadataCredentialProvider) Retrieve(*Config) (*Credentials, error) {
+	req, err := http.NewRequest("GET", securityKeyURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error building metadata API request: %s", err)
+	}
+
+	httpClient := &http.Client{}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting metadata API: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error requesting metadata API: status code = %d", resp.StatusCode)
+	}
+
+	return parseTemporaryCredentials(resp.Body)
+}
+
+// signingCredentials holds the security key in use and the expired ones, which is shared by all clients created from
+// the same Config.
+type signingCredentials struct {
+	sync.RWMutex
+	current *Credentials
+	// the access keys which have been replaced by the reloaded security key
+	expired map[string]bool
+}
+
+func newSigningCredentials() *signingCredentials {
+	return &signingCredentials{
+		expired: make(map[string]bool),
+	}
+}
+
+func (s *signingCredentials) update(creds *Credentials) {
+	if s == nil || creds.AccessKey == "" {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if s.current != nil && s.current.AccessKey != creds.AccessKey {
+		s.expired[s.current.AccessKey] = true
+	}
+	s.current = creds
+}
+
+// resignCredentials returns the security key in use if the accessKey has expired, otherwise returns nil.
+func (s *signingCredentials) resignCredentials(accessKey string) *Credentials {
+	if s == nil || accessKey == "" {
+		return nil
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+	if !s.expired[accessKey] {
+		return nil
+	}
+	return s.current
+}
+
+// credentialRoundTripper reloads the temporary security key before it expires, and signs again the requests which
+// are signed with the expired security key, so that the clients created before the security key is reloaded can
+// still work, e.g. waiting for a long-running job.
+type credentialRoundTripper struct {
+	Rt     http.RoundTripper
+	config *Config
+}
+
+func (crt *credentialRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	c := crt.config
+	// do not wait if the security key is being reloaded, the requests sent during reloading use this RoundTripper
+	if err := c.reloadSecurityKeyIfExpiring(false); err != nil {
+		log.Printf("[WARN] failed to reload the security key: %s", err)
+	}
+
+	creds := c.signingCredentials.resignCredentials(parseAccessKey(request.Header.Get("Authorization")))
+	if creds == nil {
+		return crt.Rt.RoundTrip(request)
+	}
+
+	// a RoundTripper should not modify the request, so sign a copy of it
+	newRequest := request.Clone(request.Context())
+	if request.Body != nil {
+		body, err := io.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		newRequest.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	// X-Project-Id, X-Domain-Id and X-Security-Token are set after the request is signed by golangsdk
+	projectID, domainID := newRequest.Header.Get("X-Project-Id"), newRequest.Header.Get("X-Domain-Id")
+	for _, header := range []string{"Authorization", "X-Sdk-Date", "X-Project-Id", "X-Domain-Id", "X-Security-Token"} {
+		newRequest.Header.Del(header)
+	}
+	if err := auth.Sign(newRequest, creds.AccessKey, creds.SecretKey); err != nil {
+		return nil, fmt.Errorf("error signing the request with the reloaded security key: %s", err)
+	}
+	if projectID != "" {
+		newRequest.Header.Set("X-Project-Id", projectID)
+	}
+	if domainID != "" {
+		newRequest.Header.Set("X-Domain-Id", domainID)
+	}
+	if creds.SecurityToken != "" {
+		newRequest.Header.Set("X-Security-Token", creds.SecurityToken)
+	}
+
+	log.Printf("[DEBUG] the request is signed again with the reloaded security key: %s %s", newRequest.Method,
+		newRequest.URL)
+	return crt.Rt.RoundTrip(newRequest)
+}
+
+// parseAccessKey parses the access key from the authorization header which likes:
+// SDK-HMAC-SHA256 Access={ak}, SignedHeaders={headers}, Signature={signature}
+func parseAccessKey(authorization string) string {
+	for _, part := range strings.Split(authorization, ",") {
+		part = strings.TrimSpace(part)
+		if index := strings.Index(part, "Access="); index >= 0 {
+			return part[index+len("Access="):]
+		}
+	}
+	return ""
+}
+
+type credentialResponse struct {
+	Header http.Header
+	Body   io.Reader
+}
+
+func newCredentialHTTPClient(c *Config) (*http.Client, error) {
+	tlsConfig, err := generateTLSConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Transport: &LogRoundTripper{
+			Rt: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsConfig,
+			},
+		},
+		Timeout: time.Minute,
+	}, nil
+}
+
+func doCredentialRequest(client *http.Client, url string, headers map[string]string,
+	body interface{}) (*credentialResponse, error) {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json;charset=UTF-8")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	rawBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("status code = %d, response: %s", resp.StatusCode, string(rawBody))
+	}
+
+	return &credentialResponse{
+		Header: resp.Header,
+		Body:   bytes.NewReader(rawBody),
+	}, nil
+}
+
+// parseTemporaryCredentials parses the temporary AK/SK from the response body of IAM or ECS metadata API, which
+// likes {"credential": {"access": "xxx", "secret": "xxx", "securitytoken": "xxx", "expires_at": "xxx"}}.
+func parseTemporaryCredentials(body io.Reader) (*Credentials, error) {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the response body: %s", err)
+	}
+
+	var parsedBody interface{}
+	if err := json.Unmarshal(rawBody, &parsedBody); err != nil {
+		return nil, fmt.Errorf("error parsing the response body: %s", err)
+	}
+
+	expiresAt, _ := jmespath.Search("credential.expires_at", parsedBody)
+	accessKey, _ := jmespath.Search("credential.access", parsedBody)
+	secretKey, _ := jmespath.Search("credential.secret", parsedBody)
+	securityToken, _ := jmespath.Search("credential.securitytoken", parsedBody)
+	if accessKey == nil || secretKey == nil || securityToken == nil || expiresAt == nil {
+		return nil, fmt.Errorf("error fetching the temporary credentials: access, secret, securitytoken or " +
+			"expires_at is not found in the response")
+	}
+
+	expiresTime, err := time.Parse(time.RFC3339, expiresAt.(string))
+	if err != nil {
+		return nil, err
+	}
+	return &Credentials{
+		AccessKey:     accessKey.(string),
+		SecretKey:     secretKey.(string),
+		SecurityToken: securityToken.(string),
+		ExpiresAt:     expiresTime,
+	}, nil
+}
@@ -0,0 +1,321 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/auth"
+	th "github.com/chnsz/golangsdk/testhelper"
+	vpcmodel "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/vpc/v3/model"
+)
+
+func TestProcessCredentialProvider(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test command is not supported on windows")
+	}
+
+	output := `{"access_key_id": "ak", "secret_access_key": "sk", "security_token": "token",
+		"expires_at": "2099-01-01T00:00:00Z"}`
+	cfg := &Config{
+		CredentialProcess: fmt.Sprintf("echo '%s'", output),
+	}
+
+	p := &ProcessCredentialProvider{}
+	th.AssertEquals(t, true, p.IsAvailable(cfg))
+	th.AssertNoErr(t, cfg.retrieveCredentials(p))
+	th.AssertEquals(t, "ak", cfg.AccessKey)
+	th.AssertEquals(t, "sk", cfg.SecretKey)
+	th.AssertEquals(t, "token", cfg.SecurityToken)
+	th.AssertEquals(t, time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC).Unix(), cfg.SecurityKeyExpiresAt.Unix())
+	th.AssertEquals(t, CredentialProvider(p), cfg.credentialProvider)
+
+	// the command exits with an error
+	cfg.CredentialProcess = "echo 'broker is unavailable' >&2; exit 1"
+	err := cfg.retrieveCredentials(p)
+	th.AssertEquals(t, true, err != nil)
+}
+
+func TestWebIdentityCredentialProvider(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	th.AssertNoErr(t, os.WriteFile(tokenFile, []byte("id-token\n"), 0600))
+
+	th.Mux.HandleFunc("/v3.0/OS-AUTH/id-token/tokens", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestHeader(t, r, "X-Idp-Id", "oidc-provider")
+
+		body, err := io.ReadAll(r.Body)
+		th.AssertNoErr(t, err)
+		var reqBody map[string]map[string]map[string]interface{}
+		th.AssertNoErr(t, json.Unmarshal(body, &reqBody))
+		th.AssertEquals(t, "id-token", reqBody["auth"]["id_token"]["id"])
+
+		w.Header().Set("X-Subject-Token", "federated-token")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, `{"token": {}}`)
+	})
+	th.Mux.HandleFunc("/v3.0/OS-CREDENTIAL/securitytokens", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		th.TestJSONRequest(t, r, `{"auth": {"identity": {"methods": ["token"],
+			"token": {"id": "federated-token", "duration_seconds": 3600}}}}`)
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, `{"credential": {"access": "ak", "secret": "sk", "securitytoken": "token",
+			"expires_at": "2099-01-01T00:00:00.000000Z"}}`)
+	})
+
+	cfg := &Config{
+		IdentityEndpoint:     th.Endpoint() + "v3",
+		WebIdentityTokenFile: tokenFile,
+		WebIdentityProvider:  "oidc-provider",
+	}
+
+	p := &WebIdentityCredentialProvider{}
+	th.AssertEquals(t, true, p.IsAvailable(cfg))
+	th.AssertNoErr(t, cfg.retrieveCredentials(p))
+	th.AssertEquals(t, "ak", cfg.AccessKey)
+	th.AssertEquals(t, "sk", cfg.SecretKey)
+	th.AssertEquals(t, "token", cfg.SecurityToken)
+	th.AssertEquals(t, false, cfg.SecurityKeyExpiresAt.IsZero())
+}
+
+func TestDefaultCredentialProviders(t *testing.T) {
+	cfg := &Config{
+		AccessKey:         "ak",
+		SecretKey:         "sk",
+		CredentialProcess: "echo '{}'",
+	}
+
+	// the first available provider is used
+	var available []string
+	for _, p := range DefaultCredentialProviders() {
+		if p.IsAvailable(cfg) {
+			available = append(available, p.Name())
+		}
+	}
+	th.AssertDeepEquals(t, []string{"static credentials", "credential process", "ECS metadata API"}, available)
+}
+
+func TestReloadSecurityKeyWithAgency(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	var assumeCount int
+	th.Mux.HandleFunc("/v5/agencies/assume", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+		// the agency is always assumed with the static credentials
+		th.AssertEquals(t, "static-ak", parseAccessKey(r.Header.Get("Authorization")))
+		th.TestJSONRequest(t, r, `{"agency_urn": "iam::domain-id:agency:agency", "agency_session_name": "agency",
+			"duration_seconds": 3600}`)
+
+		assumeCount++
+		expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		_, _ = fmt.Fprintf(w, `{"credentials": {"access_key_id": "assumed-ak-%d", "secret_access_key": "assumed-sk",
+			"security_token": "assumed-token", "expires_at": "%s"}}`, assumeCount, expiresAt)
+	})
+
+	cfg := &Config{
+		AccessKey:          "static-ak",
+		SecretKey:          "static-sk",
+		Region:             "region-0",
+		Endpoints:          map[string]string{"sts": th.Endpoint()},
+		RegionProjectIDMap: make(map[string]string),
+		RPLock:             new(sync.Mutex),
+		SecurityKeyLock:    new(sync.Mutex),
+		AssumeRoles: []AssumeRole{
+			{AgencyName: "agency", DomainName: "domain", DomainID: "domain-id", Duration: time.Hour},
+		},
+		signingCredentials: newSigningCredentials(),
+	}
+	cfg.DomainClient = &golangsdk.ProviderClient{
+		HTTPClient: http.Client{
+			Transport: &credentialRoundTripper{Rt: http.DefaultTransport, config: cfg},
+		},
+	}
+	th.AssertNoErr(t, cfg.retrieveCredentials(&StaticCredentialProvider{}))
+
+	th.AssertNoErr(t, cfg.reloadSecurityKey())
+	th.AssertEquals(t, "assumed-ak-1", cfg.AccessKey)
+	th.AssertEquals(t, "assumed-token", cfg.SecurityToken)
+	th.AssertEquals(t, false, cfg.SecurityKeyExpiresAt.IsZero())
+
+	// the security key is not reloaded until it's going to expire
+	th.AssertNoErr(t, cfg.reloadSecurityKeyIfExpiring(true))
+	th.AssertEquals(t, 1, assumeCount)
+
+	cfg.SecurityKeyExpiresAt = time.Now().Add(time.Minute)
+	th.AssertNoErr(t, cfg.reloadSecurityKeyIfExpiring(true))
+	th.AssertEquals(t, 2, assumeCount)
+	th.AssertEquals(t, "assumed-ak-2", cfg.AccessKey)
+	th.AssertEquals(t, "assumed-ak-2", cfg.signingCredentials.resignCredentials("assumed-ak-1").AccessKey)
+}
+
+func TestReloadSecurityKeyWithAgencyChain(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/v5/agencies/assume", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+
+		var hop string
+		// each agency is assumed with the temporary credentials of the previous one
+		switch parseAccessKey(r.Header.Get("Authorization")) {
+		case "static-ak":
+			hop = "security"
+			th.TestJSONRequest(t, r, `{"agency_urn": "iam::security-domain-id:agency:security-agency",
+				"agency_session_name": "security-agency", "duration_seconds": 43200}`)
+		case "security-ak":
+			hop = "workload"
+			th.TestHeader(t, r, "X-Security-Token", "security-token")
+			th.TestJSONRequest(t, r, `{"agency_urn": "iam::workload-domain-id:agency:workload-agency",
+				"agency_session_name": "workload-agency", "duration_seconds": 900,
+				"policy": "{\"Version\":\"5.0\"}", "tags": [{"key": "team", "value": "iac"}]}`)
+		default:
+			t.Fatalf("unexpected access key: %s", r.Header.Get("Authorization"))
+		}
+
+		expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		_, _ = fmt.Fprintf(w, `{"credentials": {"access_key_id": "%[1]s-ak", "secret_access_key": "%[1]s-sk",
+			"security_token": "%[1]s-token", "expires_at": "%[2]s"}}`, hop, expiresAt)
+	})
+
+	cfg := &Config{
+		AccessKey:          "static-ak",
+		SecretKey:          "static-sk",
+		Region:             "region-0",
+		Endpoints:          map[string]string{"sts": th.Endpoint()},
+		RegionProjectIDMap: make(map[string]string),
+		RPLock:             new(sync.Mutex),
+		SecurityKeyLock:    new(sync.Mutex),
+		AssumeRoles: []AssumeRole{
+			{AgencyName: "security-agency", DomainName: "security", DomainID: "security-domain-id"},
+			{
+				AgencyName:    "workload-agency",
+				DomainName:    "workload",
+				DomainID:      "workload-domain-id",
+				Duration:      15 * time.Minute,
+				SessionPolicy: `{"Version":"5.0"}`,
+				SessionTags:   map[string]string{"team": "iac"},
+			},
+		},
+		signingCredentials: newSigningCredentials(),
+	}
+	cfg.DomainClient = &golangsdk.ProviderClient{
+		HTTPClient: http.Client{
+			Transport: &credentialRoundTripper{Rt: http.DefaultTransport, config: cfg},
+		},
+	}
+	th.AssertNoErr(t, cfg.retrieveCredentials(&StaticCredentialProvider{}))
+
+	th.AssertNoErr(t, cfg.reloadSecurityKey())
+	th.AssertEquals(t, "workload-ak", cfg.AccessKey)
+	th.AssertEquals(t, "workload-sk", cfg.SecretKey)
+	th.AssertEquals(t, "workload-token", cfg.SecurityToken)
+}
+
+func TestCredentialRoundTripper(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		th.AssertEquals(t, "new-ak", parseAccessKey(r.Header.Get("Authorization")))
+		th.TestHeader(t, r, "X-Security-Token", "new-token")
+		th.TestHeader(t, r, "X-Project-Id", "project-id")
+		th.TestBody(t, r, `{"name": "test"}`)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	cfg := &Config{
+		signingCredentials: newSigningCredentials(),
+	}
+	cfg.signingCredentials.update(&Credentials{AccessKey: "old-ak", SecretKey: "old-sk", SecurityToken: "old-token"})
+	cfg.signingCredentials.update(&Credentials{AccessKey: "new-ak", SecretKey: "new-sk", SecurityToken: "new-token"})
+
+	// the request is signed with the expired security key
+	req, err := http.NewRequest("POST", th.Endpoint()+"route", strings.NewReader(`{"name": "test"}`))
+	th.AssertNoErr(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	th.AssertNoErr(t, auth.Sign(req, "old-ak", "old-sk"))
+	req.Header.Set("X-Project-Id", "project-id")
+	req.Header.Set("X-Security-Token", "old-token")
+
+	client := http.Client{
+		Transport: &credentialRoundTripper{Rt: http.DefaultTransport, config: cfg},
+	}
+	resp, err := client.Do(req)
+	th.AssertNoErr(t, err)
+	defer resp.Body.Close()
+	th.AssertEquals(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHcClientWithReloadedSecurityKey(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	var assumeCount int
+	th.Mux.HandleFunc("/v5/agencies/assume", func(w http.ResponseWriter, r *http.Request) {
+		assumeCount++
+		expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		_, _ = fmt.Fprintf(w, `{"credentials": {"access_key_id": "assumed-ak-%[1]d", "secret_access_key": "assumed-sk",
+			"security_token": "assumed-token-%[1]d", "expires_at": "%[2]s"}}`, assumeCount, expiresAt)
+	})
+
+	// the access keys of the requests sent by the hcsdk client
+	var accessKeys []string
+	th.Mux.HandleFunc("/v3/project-id/vpc/vpcs", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		th.TestHeader(t, r, "X-Project-Id", "project-id")
+		accessKey := parseAccessKey(r.Header.Get("Authorization"))
+		th.TestHeader(t, r, "X-Security-Token", strings.Replace(accessKey, "-ak-", "-token-", 1))
+		accessKeys = append(accessKeys, accessKey)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprint(w, `{"vpcs": []}`)
+	})
+
+	cfg := &Config{
+		AccessKey:          "static-ak",
+		SecretKey:          "static-sk",
+		Region:             "region-0",
+		Endpoints:          map[string]string{"sts": th.Endpoint(), "vpc": th.Endpoint()},
+		RegionProjectIDMap: map[string]string{"region-0": "project-id"},
+		RPLock:             new(sync.Mutex),
+		SecurityKeyLock:    new(sync.Mutex),
+		AssumeRoles: []AssumeRole{
+			{AgencyName: "agency", DomainName: "domain", DomainID: "domain-id", Duration: time.Hour},
+		},
+		signingCredentials: newSigningCredentials(),
+	}
+	cfg.DomainClient = &golangsdk.ProviderClient{
+		HTTPClient: http.Client{
+			Transport: &credentialRoundTripper{Rt: http.DefaultTransport, config: cfg},
+		},
+	}
+	th.AssertNoErr(t, cfg.retrieveCredentials(&StaticCredentialProvider{}))
+	th.AssertNoErr(t, cfg.reloadSecurityKey())
+
+	client, err := cfg.HcVpcV3Client("region-0")
+	th.AssertNoErr(t, err)
+	_, err = client.ListVpcs(&vpcmodel.ListVpcsRequest{})
+	th.AssertNoErr(t, err)
+
+	// the security key expires while the client is in use, e.g. waiting for a long-running job
+	cfg.SecurityKeyExpiresAt = time.Now().Add(time.Minute)
+	_, err = client.ListVpcs(&vpcmodel.ListVpcsRequest{})
+	th.AssertNoErr(t, err)
+
+	th.AssertEquals(t, 2, assumeCount)
+	th.AssertDeepEquals(t, []string{"assumed-ak-1", "assumed-ak-2"}, accessKeys)
+}
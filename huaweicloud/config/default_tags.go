@@ -0,0 +1,252 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+type contextFunc = func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
+type legacyFunc = func(*schema.ResourceData, interface{}) error
+
+// defaultTagsResources is the list of resources which apply the tags through utils.GetAllResourceTags or
+// utils.GetAllResourceTagsChange, the default tags are only supported by these resources.
+var defaultTagsResources = map[string]bool{
+	"huaweicloud_bms_instance":               true,
+	"huaweicloud_cbr_vault":                  true,
+	"huaweicloud_cce_node":                   true,
+	"huaweicloud_cce_node_v3":                true,
+	"huaweicloud_compute_instance":           true,
+	"huaweicloud_compute_instance_v2":        true,
+	"huaweicloud_csms_secret":                true,
+	"huaweicloud_css_logstash_cluster":       true,
+	"huaweicloud_dc_virtual_gateway":         true,
+	"huaweicloud_dc_virtual_interface":       true,
+	"huaweicloud_dds_instance":               true,
+	"huaweicloud_dds_instance_v3":            true,
+	"huaweicloud_dis_stream":                 true,
+	"huaweicloud_dis_stream_v2":              true,
+	"huaweicloud_dli_flinkjar_job":           true,
+	"huaweicloud_dli_flinksql_job":           true,
+	"huaweicloud_dli_package":                true,
+	"huaweicloud_dms_instance":               true,
+	"huaweicloud_dms_instance_v1":            true,
+	"huaweicloud_dms_kafka_instance":         true,
+	"huaweicloud_dms_rabbitmq_instance":      true,
+	"huaweicloud_dms_rocketmq_instance":      true,
+	"huaweicloud_dns_ptrrecord":              true,
+	"huaweicloud_dns_ptrrecord_v2":           true,
+	"huaweicloud_dns_recordset":              true,
+	"huaweicloud_dns_recordset_v2":           true,
+	"huaweicloud_dns_zone":                   true,
+	"huaweicloud_dns_zone_v2":                true,
+	"huaweicloud_drs_job":                    true,
+	"huaweicloud_ecs_instance_v1":            true,
+	"huaweicloud_elb_listener":               true,
+	"huaweicloud_elb_loadbalancer":           true,
+	"huaweicloud_er_instance":                true,
+	"huaweicloud_er_route_table":             true,
+	"huaweicloud_er_vpc_attachment":          true,
+	"huaweicloud_evs_volume":                 true,
+	"huaweicloud_gaussdb_cassandra_instance": true,
+	"huaweicloud_gaussdb_influx_instance":    true,
+	"huaweicloud_gaussdb_mongo_instance":     true,
+	"huaweicloud_gaussdb_mysql_instance":     true,
+	"huaweicloud_gaussdb_redis_instance":     true,
+	"huaweicloud_images_image_copy":          true,
+	"huaweicloud_kms_key":                    true,
+	"huaweicloud_kms_key_v1":                 true,
+	"huaweicloud_lb_listener":                true,
+	"huaweicloud_lb_listener_v2":             true,
+	"huaweicloud_lb_loadbalancer":            true,
+	"huaweicloud_lb_loadbalancer_v2":         true,
+	"huaweicloud_mrs_cluster":                true,
+	"huaweicloud_mrs_cluster_v1":             true,
+	"huaweicloud_nat_gateway":                true,
+	"huaweicloud_nat_gateway_v2":             true,
+	"huaweicloud_nat_private_gateway":        true,
+	"huaweicloud_nat_private_transit_ip":     true,
+	"huaweicloud_networking_secgroup":        true,
+	"huaweicloud_networking_secgroup_v2":     true,
+	"huaweicloud_rds_instance":               true,
+	"huaweicloud_rds_instance_v3":            true,
+	"huaweicloud_rds_read_replica_instance":  true,
+	"huaweicloud_sdrs_protected_instance":    true,
+	"huaweicloud_sfs_file_system":            true,
+	"huaweicloud_sfs_file_system_v2":         true,
+	"huaweicloud_sfs_turbo":                  true,
+	"huaweicloud_smn_topic":                  true,
+	"huaweicloud_smn_topic_v2":               true,
+	"huaweicloud_vpc":                        true,
+	"huaweicloud_vpc_eip":                    true,
+	"huaweicloud_vpc_eip_v1":                 true,
+	"huaweicloud_vpc_subnet":                 true,
+	"huaweicloud_vpc_subnet_v1":              true,
+	"huaweicloud_vpc_v1":                     true,
+	"huaweicloud_vpcep_endpoint":             true,
+	"huaweicloud_vpcep_service":              true,
+	"huaweicloud_vpnaas_site_connection":     true,
+	"huaweicloud_vpnaas_site_connection_v2":  true,
+	"huaweicloud_workspace_desktop":          true,
+}
+
+// EnableDefaultTags makes the resource support the provider-level default tags if it is in defaultTagsResources and
+// its tags is defined by common.TagsSchema(). A computed "tags_all" attribute will be added to the resource to store
+// all tags, which are merged from the default tags and the resource tags during the plan, and the default tags will be
+// removed from the "tags" attribute after reading, so that they will not be shown as drift.
+// The other resources whose tags is defined by common.TagsSchema() reject the default tags during the plan, so that
+// they are never silently created without the default tags.
+func EnableDefaultTags(name string, r *schema.Resource) {
+	if r == nil || !isTaggableSchema(r.Schema) {
+		return
+	}
+	if _, ok := r.Schema["tags_all"]; ok {
+		return
+	}
+	if !defaultTagsResources[name] {
+		r.CustomizeDiff = appendCustomizeDiff(r.CustomizeDiff, rejectDefaultTags(name))
+		return
+	}
+
+	r.Schema["tags_all"] = &schema.Schema{
+		Type:     schema.TypeMap,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+
+	r.CustomizeDiff = appendCustomizeDiff(r.CustomizeDiff, mergeDefaultTags)
+
+	r.CreateContext = wrapContextFunc(r.CreateContext)
+	r.CreateWithoutTimeout = wrapContextFunc(r.CreateWithoutTimeout)
+	r.Create = wrapLegacyFunc(r.Create)
+	r.ReadContext = wrapContextFunc(r.ReadContext)
+	r.ReadWithoutTimeout = wrapContextFunc(r.ReadWithoutTimeout)
+	r.Read = wrapLegacyFunc(r.Read)
+	r.UpdateContext = wrapContextFunc(r.UpdateContext)
+	r.UpdateWithoutTimeout = wrapContextFunc(r.UpdateWithoutTimeout)
+	r.Update = wrapLegacyFunc(r.Update)
+}
+
+// isTaggableSchema checks whether the tags schema is the same as common.TagsSchema().
+func isTaggableSchema(s map[string]*schema.Schema) bool {
+	tagsSchema, ok := s["tags"]
+	if !ok {
+		return false
+	}
+
+	elem, ok := tagsSchema.Elem.(*schema.Schema)
+	return tagsSchema.Type == schema.TypeMap && tagsSchema.Optional && !tagsSchema.Computed &&
+		!tagsSchema.ForceNew && ok && elem.Type == schema.TypeString
+}
+
+func appendCustomizeDiff(f, next schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	if f == nil {
+		return next
+	}
+	return customdiff.All(f, next)
+}
+
+// DefaultTagsResources returns the sorted names of the resources which support the provider-level default tags.
+func DefaultTagsResources() []string {
+	names := make([]string, 0, len(defaultTagsResources))
+	for name := range defaultTagsResources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rejectDefaultTags returns a CustomizeDiffFunc which fails the plan of the resource if the provider-level default
+// tags are specified, since the resource does not apply them.
+func rejectDefaultTags(name string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, _ *schema.ResourceDiff, meta interface{}) error {
+		if cfg, ok := meta.(*Config); ok && len(cfg.DefaultTags) > 0 {
+			return fmt.Errorf("the provider-level default_tags is not supported by %s yet, please specify the tags "+
+				"in the resource, or use a provider configuration without default_tags for it", name)
+		}
+		return nil
+	}
+}
+
+// mergeDefaultTags is a CustomizeDiffFunc which merges the provider-level default tags with the resource tags,
+// and stores the result into the "tags_all" attribute.
+func mergeDefaultTags(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	cfg, ok := meta.(*Config)
+	if !ok {
+		return nil
+	}
+
+	// keep the state of the resources which were created before "tags_all" was introduced if nothing to merge
+	oldTagsAll, _ := d.GetChange("tags_all")
+	if d.Id() != "" && !d.HasChange("tags") && len(cfg.DefaultTags) == 0 &&
+		len(oldTagsAll.(map[string]interface{})) == 0 {
+		return nil
+	}
+
+	if !d.NewValueKnown("tags") {
+		return d.SetNewComputed("tags_all")
+	}
+
+	tagsAll := utils.MergeDefaultTags(cfg.DefaultTags, d.Get("tags").(map[string]interface{}))
+	return d.SetNew("tags_all", tagsAll)
+}
+
+// removeDefaultTags saves the tags read from the cloud into the "tags_all" attribute, and removes the tags inherited
+// from the provider-level default tags from the "tags" attribute, configuredTags is the value of the "tags" attribute
+// before the resource is created, read or updated.
+func removeDefaultTags(d *schema.ResourceData, meta interface{}, configuredTags map[string]interface{}) error {
+	cfg, ok := meta.(*Config)
+	if !ok || d.Id() == "" {
+		return nil
+	}
+
+	tags := d.Get("tags").(map[string]interface{})
+	if err := d.Set("tags_all", tags); err != nil {
+		return fmt.Errorf("error setting tags_all: %s", err)
+	}
+
+	if len(cfg.DefaultTags) > 0 {
+		if err := d.Set("tags", utils.RemoveDefaultTags(cfg.DefaultTags, tags, configuredTags)); err != nil {
+			return fmt.Errorf("error removing the default tags from tags: %s", err)
+		}
+	}
+	return nil
+}
+
+func wrapContextFunc(f contextFunc) contextFunc {
+	if f == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		configuredTags := d.Get("tags").(map[string]interface{})
+		diags := f(ctx, d, meta)
+		if diags.HasError() {
+			return diags
+		}
+		if err := removeDefaultTags(d, meta, configuredTags); err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+		return diags
+	}
+}
+
+func wrapLegacyFunc(f legacyFunc) legacyFunc {
+	if f == nil {
+		return nil
+	}
+
+	return func(d *schema.ResourceData, meta interface{}) error {
+		configuredTags := d.Get("tags").(map[string]interface{})
+		if err := f(d, meta); err != nil {
+			return err
+		}
+		return removeDefaultTags(d, meta, configuredTags)
+	}
+}
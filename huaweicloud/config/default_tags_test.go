@@ -0,0 +1,50 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+func newTaggableResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"tags": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func TestEnableDefaultTags(t *testing.T) {
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"tags": map[string]interface{}{"env": "prod"},
+	})
+	cfg := &Config{DefaultTags: map[string]string{"owner": "terraform"}}
+
+	// the default tags are merged into "tags_all" of the supported resources
+	r := newTaggableResource()
+	EnableDefaultTags("huaweicloud_vpc", r)
+	th.AssertEquals(t, true, r.Schema["tags_all"] != nil)
+
+	diff, err := r.Diff(context.Background(), nil, config, cfg)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "terraform", diff.Attributes["tags_all.owner"].New)
+	th.AssertEquals(t, "prod", diff.Attributes["tags_all.env"].New)
+
+	// the default tags are rejected by the other taggable resources
+	r = newTaggableResource()
+	EnableDefaultTags("huaweicloud_unsupported_resource", r)
+	th.AssertEquals(t, true, r.Schema["tags_all"] == nil)
+
+	_, err = r.Diff(context.Background(), nil, config, cfg)
+	th.AssertEquals(t, true, err != nil)
+	_, err = r.Diff(context.Background(), nil, config, &Config{})
+	th.AssertNoErr(t, err)
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// DefaultTimeouts is the provider-level default timeouts of a resource type, the zero values are not specified.
+type DefaultTimeouts struct {
+	ResourceType string
+	Create       time.Duration
+	Read         time.Duration
+	Update       time.Duration
+	Delete       time.Duration
+}
+
+// builtinTimeouts stores the timeouts defined by the resources, so that the default timeouts of the provider can be
+// applied again when the provider is configured more than once, e.g. in the acceptance tests.
+var builtinTimeouts sync.Map
+
+// ApplyDefaultTimeouts replaces the default timeouts of the resources with the provider-level ones. The timeouts
+// are decoded from the resource configuration during the plan, so the provider-level timeouts are used if they are
+// not specified in the timeouts block of the resource.
+// Only the timeouts defined by the resource can be replaced, otherwise the schema of the timeouts block is changed
+// after it's sent to terraform.
+func ApplyDefaultTimeouts(resources map[string]*schema.Resource, defaults []DefaultTimeouts) error {
+	for _, r := range resources {
+		if v, ok := builtinTimeouts.Load(r); ok {
+			timeouts := v.(schema.ResourceTimeout)
+			r.Timeouts = &timeouts
+		}
+	}
+
+	for _, t := range defaults {
+		r, ok := resources[t.ResourceType]
+		if !ok {
+			return fmt.Errorf("the resource type %s in default_timeouts is not found", t.ResourceType)
+		}
+		if r.Timeouts == nil {
+			log.Printf("[WARN] ignore the default timeouts of %s which does not support timeouts", t.ResourceType)
+			continue
+		}
+
+		builtinTimeouts.LoadOrStore(r, *r.Timeouts)
+		timeouts := *r.Timeouts
+		for key, pair := range map[string]struct {
+			value  time.Duration
+			target **time.Duration
+		}{
+			schema.TimeoutCreate: {t.Create, &timeouts.Create},
+			schema.TimeoutRead:   {t.Read, &timeouts.Read},
+			schema.TimeoutUpdate: {t.Update, &timeouts.Update},
+			schema.TimeoutDelete: {t.Delete, &timeouts.Delete},
+		} {
+			if pair.value == 0 {
+				continue
+			}
+			if *pair.target == nil {
+				log.Printf("[WARN] ignore the default %s timeout of %s which does not support it", key, t.ResourceType)
+				continue
+			}
+
+			value := pair.value
+			*pair.target = &value
+			log.Printf("[INFO] the default %s timeout of %s is set to %s", key, t.ResourceType, value)
+		}
+		r.Timeouts = &timeouts
+	}
+	return nil
+}
+
+// logOperationTimeout logs the effective timeout of the resource operation, which is either specified in the
+// timeouts block of the resource, or the default one of the provider or the resource.
+func logOperationTimeout(op *resourceOperation) {
+	if op.d == nil || strings.HasPrefix(op.resourceType, "data.") {
+		return
+	}
+	log.Printf("[DEBUG] the %s timeout of %s is %s", op.operation, op.resourceType, op.d.Timeout(op.operation))
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+func TestApplyDefaultTimeouts(t *testing.T) {
+	cluster := &schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+	}
+	resources := map[string]*schema.Resource{
+		"huaweicloud_cce_cluster": cluster,
+		"huaweicloud_vpc":         {},
+	}
+
+	err := ApplyDefaultTimeouts(resources, []DefaultTimeouts{
+		{ResourceType: "huaweicloud_cce_cluster", Create: 2 * time.Hour, Update: time.Hour},
+		// the resource without timeouts is ignored
+		{ResourceType: "huaweicloud_vpc", Create: time.Hour},
+	})
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, 2*time.Hour, *cluster.Timeouts.Create)
+	th.AssertEquals(t, 20*time.Minute, *cluster.Timeouts.Delete)
+	// the timeout not defined by the resource is not added
+	th.AssertEquals(t, true, cluster.Timeouts.Update == nil)
+	th.AssertEquals(t, true, resources["huaweicloud_vpc"].Timeouts == nil)
+
+	// the timeouts in the resource configuration take precedence
+	var rt schema.ResourceTimeout
+	th.AssertNoErr(t, rt.ConfigDecode(cluster, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"timeouts": map[string]interface{}{"delete": "1h"},
+	})))
+	th.AssertEquals(t, 2*time.Hour, *rt.Create)
+	th.AssertEquals(t, time.Hour, *rt.Delete)
+
+	// the builtin timeouts are restored when the provider is configured again
+	th.AssertNoErr(t, ApplyDefaultTimeouts(resources, nil))
+	th.AssertEquals(t, 30*time.Minute, *cluster.Timeouts.Create)
+
+	err = ApplyDefaultTimeouts(resources, []DefaultTimeouts{{ResourceType: "huaweicloud_unknown", Create: time.Hour}})
+	if err == nil {
+		t.Error("expect an error for the unknown resource type")
+	}
+}
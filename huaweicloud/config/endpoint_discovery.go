@@ -0,0 +1,157 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/identity/v3/catalog"
+	"github.com/chnsz/golangsdk/openstack/identity/v3/tokens"
+	"github.com/chnsz/golangsdk/pagination"
+)
+
+// catalogServiceTypes maps the service types in the IAM catalog to the main keys of the endpoints, the services
+// whose type or name is already a key of allServiceCatalog are not listed.
+var catalogServiceTypes = map[string]string{
+	"identity":      "iam",
+	"compute":       "ecs",
+	"volume":        "evs",
+	"volumev2":      "evs",
+	"volumev3":      "evs",
+	"network":       "vpc",
+	"image":         "ims",
+	"object-store":  "obs",
+	"load-balancer": "elb",
+	"key-manager":   "kms",
+	"metering":      "ces",
+	"database":      "rds",
+}
+
+// discoverEndpoints reads the service catalog of the current user from IAM and resolves the endpoints of the
+// provider-level region. The discovered endpoints are used before the ones built from allServiceCatalog, but the
+// customizing endpoints always take precedence over them.
+// @API IAM GET /v3/auth/catalog
+func (c *Config) discoverEndpoints() error {
+	client, err := c.endpointDiscoveryClient()
+	if err != nil {
+		return err
+	}
+
+	entries := make([]tokens.CatalogEntry, 0)
+	err = catalog.List(client).EachPage(func(page pagination.Page) (bool, error) {
+		catalogList, err := catalog.ExtractServiceCatalog(page)
+		if err != nil {
+			return false, err
+		}
+		entries = append(entries, catalogList...)
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("error querying the service catalog: %s", err)
+	}
+
+	c.discoveredEndpoints = resolveCatalogEndpoints(entries, c.Region)
+	log.Printf("[DEBUG] discovered endpoints: %+v", c.discoveredEndpoints)
+	return nil
+}
+
+// endpointDiscoveryClient returns the IAM client to query the service catalog, the identity endpoint used for the
+// authentication is used if the IAM endpoint is not customized, e.g. in Huawei Cloud Stack.
+func (c *Config) endpointDiscoveryClient() (*golangsdk.ServiceClient, error) {
+	if _, ok := c.Endpoints["identity"]; ok || c.IdentityEndpoint == "" {
+		return c.IdentityV3Client(c.Region)
+	}
+
+	if err := c.reloadSecurityKeyIfExpiring(true); err != nil {
+		return nil, err
+	}
+	endpoint := strings.TrimSuffix(c.IdentityEndpoint, "/") + "/"
+	return &golangsdk.ServiceClient{
+		ProviderClient: c.DomainClient,
+		Endpoint:       endpoint,
+		ResourceBase:   endpoint,
+	}, nil
+}
+
+// resolveCatalogEndpoints returns the endpoints of all catalog keys of the services in the catalog entries, the
+// endpoints are the base URLs of the public interfaces in the region, or the global ones if not found.
+func resolveCatalogEndpoints(entries []tokens.CatalogEntry, region string) map[string]string {
+	result := make(map[string]string)
+	for _, entry := range entries {
+		mainKey := catalogMainKey(entry)
+		if mainKey == "" {
+			log.Printf("[DEBUG] ignore the service %s (%s) in the catalog", entry.Name, entry.Type)
+			continue
+		}
+
+		endpoint := catalogEntryEndpoint(entry, region)
+		if endpoint == "" {
+			continue
+		}
+
+		result[mainKey] = endpoint
+		mainCatalog, ok := allServiceCatalog[mainKey]
+		if !ok {
+			continue
+		}
+		// all clients of the service share the same host, e.g. ecsv21 and ecsv11 of ecs
+		for key, serviceCatalog := range allServiceCatalog {
+			if serviceCatalog.Name == mainCatalog.Name {
+				result[key] = endpoint
+			}
+		}
+	}
+	return result
+}
+
+func catalogMainKey(entry tokens.CatalogEntry) string {
+	if key, ok := catalogServiceTypes[entry.Type]; ok {
+		return key
+	}
+	for _, key := range []string{entry.Type, entry.Name} {
+		if _, ok := allServiceCatalog[key]; ok {
+			return key
+		}
+	}
+	return ""
+}
+
+// catalogEntryEndpoint returns the base URL of the public endpoint, the path (version and project ID) is removed
+// because it's appended by the service clients.
+func catalogEntryEndpoint(entry tokens.CatalogEntry, region string) string {
+	var rawURL string
+	for _, ep := range entry.Endpoints {
+		if ep.Interface != "" && ep.Interface != "public" {
+			continue
+		}
+		if ep.Region == region {
+			rawURL = ep.URL
+			break
+		}
+		if ep.Region == "" || ep.Region == "*" {
+			rawURL = ep.URL
+		}
+	}
+	if rawURL == "" {
+		return ""
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		log.Printf("[WARN] invalid endpoint %q of the service %s in the catalog", rawURL, entry.Name)
+		return ""
+	}
+	return fmt.Sprintf("%s://%s/", u.Scheme, u.Host)
+}
+
+// getDiscoveredEndpoint returns the discovered endpoint of the service, the discovered endpoints are only available
+// in the provider-level region.
+func (c *Config) getDiscoveredEndpoint(srv, region string) (string, bool) {
+	if region != c.Region {
+		return "", false
+	}
+	endpoint, ok := c.discoveredEndpoints[srv]
+	return endpoint, ok
+}
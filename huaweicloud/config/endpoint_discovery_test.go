@@ -0,0 +1,129 @@
+package config
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/identity/v3/tokens"
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+func TestResolveCatalogEndpoints(t *testing.T) {
+	entries := []tokens.CatalogEntry{
+		{
+			Name: "nova",
+			Type: "compute",
+			Endpoints: []tokens.Endpoint{
+				{Region: "region-1", Interface: "public", URL: "https://ecs.region-1.example.com/v2.1/$(tenant_id)s"},
+				{Region: "region-0", Interface: "internal", URL: "https://ecs-internal.region-0.example.com/v2.1"},
+				{Region: "region-0", Interface: "public", URL: "https://ecs.region-0.example.com/v2.1/$(tenant_id)s"},
+			},
+		},
+		{
+			Name: "keystone",
+			Type: "identity",
+			Endpoints: []tokens.Endpoint{
+				{Region: "*", Interface: "public", URL: "https://iam.example.com/v3"},
+			},
+		},
+		{
+			Name: "cce",
+			Type: "cce",
+			Endpoints: []tokens.Endpoint{
+				{Region: "region-0", Interface: "public", URL: "https://cce.region-0.example.com:8443/api/v3"},
+			},
+		},
+		{
+			Name: "swift",
+			Type: "object-store",
+			Endpoints: []tokens.Endpoint{
+				{Region: "region-0", URL: "https://obs.region-0.example.com"},
+			},
+		},
+		{
+			Name: "unknown",
+			Type: "unknown",
+			Endpoints: []tokens.Endpoint{
+				{Region: "region-0", Interface: "public", URL: "https://unknown.region-0.example.com"},
+			},
+		},
+		{
+			Name: "rds",
+			Type: "rds",
+			Endpoints: []tokens.Endpoint{
+				{Region: "region-1", Interface: "public", URL: "https://rds.region-1.example.com"},
+			},
+		},
+	}
+
+	endpoints := resolveCatalogEndpoints(entries, "region-0")
+	expected := map[string]string{
+		"ecs":      "https://ecs.region-0.example.com/",
+		"ecsv21":   "https://ecs.region-0.example.com/",
+		"iam":      "https://iam.example.com/",
+		"identity": "https://iam.example.com/",
+		"cce":      "https://cce.region-0.example.com:8443/",
+		"obs":      "https://obs.region-0.example.com/",
+	}
+	for k, v := range expected {
+		th.AssertEquals(t, v, endpoints[k])
+	}
+	// the services which are unknown or not in the region are not discovered
+	for _, k := range []string{"unknown", "rds"} {
+		if _, ok := endpoints[k]; ok {
+			t.Errorf("unexpected endpoint of %s: %s", k, endpoints[k])
+		}
+	}
+}
+
+func TestDiscoveredEndpointPrecedence(t *testing.T) {
+	cfg := &Config{
+		Region:             "region-0",
+		Cloud:              "myhuaweicloud.com",
+		AccessKey:          "ak",
+		SecretKey:          "sk",
+		HwClient:           &golangsdk.ProviderClient{},
+		DomainClient:       &golangsdk.ProviderClient{},
+		RegionProjectIDMap: map[string]string{"region-0": "project-0", "region-1": "project-1"},
+		RPLock:             new(sync.Mutex),
+		Endpoints: map[string]string{
+			"vpc": "https://vpc.custom.com/",
+		},
+		discoveredEndpoints: map[string]string{
+			"ecs": "https://ecs.discovered.com/",
+			"vpc": "https://vpc.discovered.com/",
+			"obs": "https://obs.discovered.com/",
+		},
+	}
+
+	// the customizing endpoint takes precedence over the discovered one
+	th.AssertEquals(t, "https://vpc.custom.com/", GetServiceEndpoint(cfg, "vpc", "region-0"))
+	// the discovered endpoint is only used in the provider-level region
+	th.AssertEquals(t, "https://ecs.discovered.com/", GetServiceEndpoint(cfg, "ecs", "region-0"))
+	th.AssertEquals(t, "https://ecs.region-1.myhuaweicloud.com/", GetServiceEndpoint(cfg, "ecs", "region-1"))
+	// the build-in catalog is the fallback
+	th.AssertEquals(t, "https://evs.region-0.myhuaweicloud.com/", GetServiceEndpoint(cfg, "evs", "region-0"))
+	th.AssertEquals(t, "https://obs.discovered.com/", getObsEndpoint(cfg, "region-0"))
+
+	client, err := cfg.newServiceClient("ecs", "region-0")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "https://ecs.discovered.com/", client.Endpoint)
+	th.AssertEquals(t, "https://ecs.discovered.com/v1/project-0/", client.ResourceBase)
+
+	client, err = cfg.newServiceClient("ecs", "region-1")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "https://ecs.region-1.myhuaweicloud.com/v1/project-1/", client.ResourceBase)
+
+	// the endpoints built by the config are the same as the ones used by the service clients
+	endpoint, resourceBase, err := cfg.BuildServiceEndpoint("ecs", "region-0", "project-0")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "https://ecs.discovered.com/", endpoint)
+	th.AssertEquals(t, "https://ecs.discovered.com/v1/project-0/", resourceBase)
+	_, resourceBase, err = cfg.BuildServiceEndpoint("vpc", "region-0", "project-0")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "https://vpc.custom.com/v1/", resourceBase)
+	if _, _, err = cfg.BuildServiceEndpoint("ecs", "region-0", ""); err == nil {
+		t.Error("expect an error without the project ID")
+	}
+}
@@ -963,24 +963,67 @@ func GetServiceEndpoint(c *Config, srv, region string) string {
 		return endpoint
 	}
 
-	// get the endpoint from build-in catalog
+	// get the discovered endpoint or the one from build-in catalog
+	catalog, err := serviceCatalogByRegion(srv, region)
+	if err != nil {
+		return ""
+	}
+	return c.serviceEndpointByName(srv, catalog, region)
+}
+
+// serviceCatalogByRegion returns the catalog of a service, the catalog name is updated for the region if necessary.
+func serviceCatalogByRegion(srv, region string) (ServiceCatalog, error) {
 	catalog, ok := allServiceCatalog[srv]
 	if !ok {
-		return ""
+		return catalog, fmt.Errorf("service type %s is invalid or not supportted", srv)
 	}
 
 	// update the service catalog name if necessary
 	if name := getServiceCatalogNameByRegion(srv, region); name != "" {
 		catalog.Name = name
 	}
+	return catalog, nil
+}
+
+// buildCatalogEndpoint returns the endpoint of a service built from the catalog, the endpoint of a global service
+// does not contain the region.
+func buildCatalogEndpoint(catalog ServiceCatalog, region, cloud string, global bool) string {
+	if global {
+		return fmt.Sprintf("https://%s.%s/", catalog.Name, cloud)
+	}
+	return fmt.Sprintf("https://%s.%s.%s/", catalog.Name, region, cloud)
+}
+
+// buildResourceBase returns the resource base of a service with the endpoint, the project ID is appended to the
+// resource base if the service is a project-level one.
+func buildResourceBase(catalog ServiceCatalog, endpoint, projectID string) string {
+	resourceBase := endpoint
+	if catalog.Version != "" {
+		resourceBase = resourceBase + catalog.Version + "/"
+	}
+	if !catalog.WithOutProjectID {
+		resourceBase = resourceBase + projectID + "/"
+	}
+	if catalog.ResourceBase != "" {
+		resourceBase = resourceBase + catalog.ResourceBase + "/"
+	}
+	return resourceBase
+}
 
-	var ep string
-	if catalog.Scope == "global" && !c.RegionClient {
-		ep = fmt.Sprintf("https://%s.%s/", catalog.Name, c.Cloud)
-	} else {
-		ep = fmt.Sprintf("https://%s.%s.%s/", catalog.Name, region, c.Cloud)
+// BuildServiceEndpoint returns the endpoint and the resource base of a service in the region from the build-in
+// catalog, the project ID is appended to the resource base if the service is a project-level one.
+// The endpoint of a global service does not contain the region.
+func BuildServiceEndpoint(srv, region, cloud, projectID string) (endpoint, resourceBase string, err error) {
+	catalog, err := serviceCatalogByRegion(srv, region)
+	if err != nil {
+		return "", "", err
 	}
-	return ep
+	if !catalog.WithOutProjectID && projectID == "" {
+		return "", "", fmt.Errorf("the project ID is required by the project-level service %s", srv)
+	}
+
+	endpoint = buildCatalogEndpoint(catalog, region, cloud, catalog.Scope == "global")
+	return endpoint, buildResourceBase(catalog, endpoint, projectID), nil
 }
 
 // GetServiceCatalog returns the catalog object of a service
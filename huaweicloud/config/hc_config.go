@@ -9,14 +9,18 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core"
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/auth"
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/auth/basic"
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/auth/global"
 	hcconfig "github.com/huaweicloud/huaweicloud-sdk-go-v3/core/config"
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/httphandler"
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/impl"
 	hcregion "github.com/huaweicloud/huaweicloud-sdk-go-v3/core/region"
+	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/request"
 	aomv2 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/aom/v2"
 	ccev3 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/cce/v3"
 	cdnv1 "github.com/huaweicloud/huaweicloud-sdk-go-v3/services/cdn/v1"
@@ -94,7 +98,7 @@ func buildGlobalAuthCredentials(c *Config, region string) (*global.Credentials,
 	return &credentials, nil
 }
 
-func buildHTTPConfig(c *Config) *hcconfig.HttpConfig {
+func buildHTTPConfig(c *Config, endpoint string) *hcconfig.HttpConfig {
 	httpConfig := hcconfig.DefaultHttpConfig()
 
 	if c.MaxRetries > 0 {
@@ -105,9 +109,32 @@ func buildHTTPConfig(c *Config) *hcconfig.HttpConfig {
 		httpConfig = httpConfig.WithIgnoreSSLVerification(true)
 	}
 
+	// the handlers are invoked synchronously before and after sending the request, so the rate limiter works
+	// as it is in the HTTP transport
+	var host string
+	if u, err := url.Parse(endpoint); err == nil {
+		host = u.Host
+	}
 	httpHandler := httphandler.NewHttpHandler().
-		AddRequestHandler(logRequestHandler).
-		AddResponseHandler(logResponseHandler)
+		AddRequestHandler(func(request http.Request) {
+			if err := c.rateLimiter.wait(request.Context(), host); err != nil {
+				logp.Printf("[WARN] failed to wait for the rate limit of %s: %s", host, err)
+			}
+			logRequestHandler(request)
+		}).
+		AddResponseHandler(func(response http.Response) {
+			c.rateLimiter.observe(host, response.StatusCode)
+			logResponseHandler(response)
+		}).
+		AddMonitorHandler(func(metric *httphandler.MonitorMetric) {
+			// the request context is not available in the monitor handler, so the context of the resource
+			// operation which the client is built for is used
+			ctx := c.requestContext()
+			traceHcRequest(ctx, metric.Method, metric.Host, metric.Path, metric.StatusCode, metric.RequestId,
+				metric.Latency)
+			recordHcSpan(ctx, metric.Method, metric.Host, metric.Path, metric.StatusCode, metric.RequestId,
+				metric.Latency)
+		})
 	httpConfig = httpConfig.WithHttpHandler(httpHandler)
 
 	if proxyURL, err := parseProxyFromEnv(); err == nil {
@@ -129,6 +156,20 @@ func buildHTTPConfig(c *Config) *hcconfig.HttpConfig {
 		logp.Printf("[WARN] parsing https proxy failed: %s", err)
 	}
 
+	// the transport takes precedence over the other configurations, e.g. proxy and SSL verification
+	if c.Recorder != nil {
+		// the TLS configuration has been validated when building the provider clients
+		tlsConfig, err := generateTLSConfig(c)
+		if err != nil {
+			logp.Printf("[WARN] failed to generate the TLS configuration: %s", err)
+		}
+		transport := &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		}
+		httpConfig = httpConfig.WithHttpTransport(c.Recorder.Transport(transport))
+	}
+
 	return httpConfig
 }
 
@@ -234,6 +275,9 @@ func (c *Config) HcMpcV1Client(region string) (*mpcv1.MpcClient, error) {
 
 // HcIoTdaV5Client is the IoTDA service client using huaweicloud-sdk-go-v3 package
 func (c *Config) HcIoTdaV5Client(region string, isDerived bool) (*iotdav5.IoTDAClient, error) {
+	if err := c.reloadSecurityKeyIfExpiring(true); err != nil {
+		return nil, err
+	}
 	hcClient, err := implNewHcClient(c, region, "iotda", false, isDerived)
 	if err != nil {
 		return nil, err
@@ -315,6 +359,9 @@ func (c *Config) HcCceV3Client(region string) (*ccev3.CceClient, error) {
 
 // NewHcClient is the common client using huaweicloud-sdk-go-v3 package
 func NewHcClient(c *Config, region, product string, isGlobal bool) (*core.HcHttpClient, error) {
+	if err := c.reloadSecurityKeyIfExpiring(true); err != nil {
+		return nil, err
+	}
 	return implNewHcClient(c, region, product, isGlobal, false)
 }
 
@@ -324,16 +371,23 @@ func implNewHcClient(c *Config, region, product string, isGlobal, isDerived bool
 		return nil, fmt.Errorf("failed to get the endpoint of %q service in region %s", product, region)
 	}
 
+	if catalog, ok := allServiceCatalog[product]; ok {
+		c.rateLimiter.register(product, catalog.Name, region, endpoint)
+		registerServiceEndpoint(catalog.Name, region, endpoint)
+	}
+
 	builder := core.NewHcHttpClientBuilder().
 		WithRegion(hcregion.NewRegion(region, endpoint)).
-		WithHttpConfig(buildHTTPConfig(c))
+		WithHttpConfig(buildHTTPConfig(c, endpoint))
 
+	var signingCreds *hcSigningCredentials
 	if isGlobal {
 		credentials, err := buildGlobalAuthCredentials(c, region)
 		if err != nil {
 			return nil, err
 		}
 		builder.WithCredentialsType("global.Credentials").WithCredential(credentials)
+		signingCreds = &hcSigningCredentials{ICredential: credentials, config: c}
 	} else {
 		credentials, err := buildAuthCredentials(c, region, isDerived)
 		if err != nil {
@@ -341,6 +395,7 @@ func implNewHcClient(c *Config, region, product string, isGlobal, isDerived bool
 		}
 
 		builder.WithCredential(credentials)
+		signingCreds = &hcSigningCredentials{ICredential: credentials, config: c}
 		if isDerived {
 			// the derivedAuthServiceName is fixed to "iotdm", now only IoTDA service need derived sign
 			builder.WithDerivedAuthServiceName("iotdm")
@@ -355,7 +410,41 @@ func implNewHcClient(c *Config, region, product string, isGlobal, isDerived bool
 		headers["User-Agent"] = providerUserAgent
 	}
 
-	return builder.Build().PreInvoke(headers), nil
+	// the credentials are replaced after the client is built, because the builder checks the type of them
+	return builder.Build().WithCredential(signingCreds).PreInvoke(headers), nil
+}
+
+// hcSigningCredentials signs the requests with the security key in use. The hcsdk client signs all requests with
+// the AK/SK which it's built with, so the clients built before the security key is reloaded, e.g. waiting for a
+// long-running job, would send the requests with the expired security key without it.
+type hcSigningCredentials struct {
+	auth.ICredential
+	// the lock protects the AK/SK of the wrapped credentials, which are updated and used in ProcessAuthRequest
+	sync.Mutex
+	config *Config
+}
+
+func (s *hcSigningCredentials) ProcessAuthRequest(client *impl.DefaultHttpClient,
+	req *request.DefaultHttpRequest) (*request.DefaultHttpRequest, error) {
+	// do not wait if the security key is being reloaded, the requests sent during reloading may use this client
+	if err := s.config.reloadSecurityKeyIfExpiring(false); err != nil {
+		log.Printf("[WARN] failed to reload the security key: %s", err)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	switch creds := s.ICredential.(type) {
+	case *basic.Credentials:
+		if newCreds := s.config.signingCredentials.resignCredentials(creds.AK); newCreds != nil {
+			creds.AK, creds.SK, creds.SecurityToken = newCreds.AccessKey, newCreds.SecretKey, newCreds.SecurityToken
+		}
+	case *global.Credentials:
+		if newCreds := s.config.signingCredentials.resignCredentials(creds.AK); newCreds != nil {
+			creds.AK, creds.SK, creds.SecurityToken = newCreds.AccessKey, newCreds.SecretKey, newCreds.SecurityToken
+		}
+	}
+	return s.ICredential.ProcessAuthRequest(client, req)
 }
 
 func parseProxyFromEnv() (*url.URL, error) {
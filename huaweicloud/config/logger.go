@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"math"
 	"net/http"
 	"sort"
 	"strings"
@@ -30,12 +29,7 @@ type LogRoundTripper struct {
 }
 
 func retryTimeout(count int) time.Duration {
-	seconds := math.Pow(2, float64(count))
-	timeout := time.Duration(seconds) * time.Second
-	if timeout > maxTimeout { // won't wait more than maxTimeout
-		timeout = maxTimeout
-	}
-	return timeout
+	return jitteredBackoff(retryBaseDelay, maxTimeout, uint(count))
 }
 
 // RoundTrip performs a round-trip HTTP request and logs relevant information about it.
@@ -44,10 +38,18 @@ func (lrt *LogRoundTripper) RoundTrip(request *http.Request) (*http.Response, er
 	var response *http.Response
 	var bs bytes.Buffer
 
+	var retries int
+	startTime := time.Now()
+
 	atomicId := atomic.AddInt64(&logAtomicId, 1)
-	logId := fmt.Sprintf("%d-%d", time.Now().UnixMilli(), atomicId)
+	logId := fmt.Sprintf("%d-%d", startTime.UnixMilli(), atomicId)
 
+	span := startHTTPSpan(request)
 	defer func() {
+		// writing the structured trace log if it's enabled
+		traceHTTPRequest(request, bs.Bytes(), response, err, startTime, retries)
+		endHTTPSpan(span, response, err, retries)
+
 		// logging the API request and response
 		var logErr error
 		if request != nil {
@@ -86,28 +88,38 @@ func (lrt *LogRoundTripper) RoundTrip(request *http.Request) (*http.Response, er
 
 	// executes a single HTTP transaction
 	response, err = lrt.Rt.RoundTrip(request)
-	if response == nil {
-		errMessage := err.Error()
-		if strings.Contains(errMessage, "no such host") {
-			return nil, err
+
+	for retry := 1; shouldRetry(request, response, err); retry++ {
+		reason := fmt.Sprintf("%v", err)
+		if response != nil {
+			reason = response.Status
 		}
-	}
 
-	// Retrying connection
-	retry := 1
-	for response == nil {
-		if retry > lrt.MaxRetries {
-			log.Printf("[DEBUG] [%s] connection error, retries exhausted. Aborting", logId)
-			err = fmt.Errorf("connection error, retries exhausted. Aborting. Last error was: %s", err)
-			return nil, err
+		delay := retryDelay(response, retry)
+		if retry > lrt.MaxRetries || time.Since(startTime)+delay > maxRetryElapsedTime {
+			log.Printf("[DEBUG] [%s] %s, retries exhausted. Aborting", logId, reason)
+			if response == nil {
+				err = fmt.Errorf("connection error, retries exhausted. Aborting. Last error was: %s", err)
+			}
+			break
 		}
 
-		log.Printf("[DEBUG] [%s] connection error, retry number %d: %s", logId, retry, err)
+		log.Printf("[DEBUG] [%s] %s, retry number %d after %s", logId, reason, retry, delay)
+		if response != nil {
+			// the response of the failed attempt is discarded
+			_, _ = io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+			response = nil
+		}
+		if err = sleepWithContext(request.Context(), delay); err != nil {
+			return nil, err
+		}
 
-		//lintignore:R018
-		time.Sleep(retryTimeout(retry))
+		if request.Body != nil {
+			request.Body = io.NopCloser(bytes.NewReader(bs.Bytes()))
+		}
 		response, err = lrt.Rt.RoundTrip(request)
-		retry++
+		retries = retry
 	}
 
 	return response, err
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+// setRetryDelays shortens the delays between the retries and restores them after the test.
+func setRetryDelays(t *testing.T, baseDelay, maxElapsedTime time.Duration) {
+	oldBaseDelay, oldMaxElapsedTime := retryBaseDelay, maxRetryElapsedTime
+	retryBaseDelay, maxRetryElapsedTime = baseDelay, maxElapsedTime
+	t.Cleanup(func() {
+		retryBaseDelay, maxRetryElapsedTime = oldBaseDelay, oldMaxElapsedTime
+	})
+}
+
+func newLogRoundTripperClient(maxRetries int) *http.Client {
+	return &http.Client{
+		Transport: &LogRoundTripper{
+			Rt:         http.DefaultTransport,
+			MaxRetries: maxRetries,
+		},
+	}
+}
+
+func TestLogRoundTripperRetryStatusCode(t *testing.T) {
+	setRetryDelays(t, time.Millisecond, time.Minute)
+
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the request body is sent again in each retry
+		body, err := io.ReadAll(r.Body)
+		th.AssertNoErr(t, err)
+		th.AssertEquals(t, `{"name": "test"}`, string(body))
+
+		if atomic.AddInt32(&count, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := newLogRoundTripperClient(5)
+
+	// the idempotent request is retried on 503
+	req, err := http.NewRequest("PUT", server.URL, strings.NewReader(`{"name": "test"}`))
+	th.AssertNoErr(t, err)
+	resp, err := client.Do(req)
+	th.AssertNoErr(t, err)
+	resp.Body.Close()
+	th.AssertEquals(t, http.StatusOK, resp.StatusCode)
+	th.AssertEquals(t, int32(3), atomic.LoadInt32(&count))
+
+	// the non-idempotent request is not retried on 503
+	atomic.StoreInt32(&count, 0)
+	resp, err = client.Post(server.URL, "application/json", strings.NewReader(`{"name": "test"}`))
+	th.AssertNoErr(t, err)
+	resp.Body.Close()
+	th.AssertEquals(t, http.StatusServiceUnavailable, resp.StatusCode)
+	th.AssertEquals(t, int32(1), atomic.LoadInt32(&count))
+
+	// the last response is returned if the retries are exhausted
+	atomic.StoreInt32(&count, 0)
+	req, err = http.NewRequest("PUT", server.URL, strings.NewReader(`{"name": "test"}`))
+	th.AssertNoErr(t, err)
+	resp, err = newLogRoundTripperClient(1).Do(req)
+	th.AssertNoErr(t, err)
+	resp.Body.Close()
+	th.AssertEquals(t, http.StatusServiceUnavailable, resp.StatusCode)
+	th.AssertEquals(t, int32(2), atomic.LoadInt32(&count))
+}
+
+func TestLogRoundTripperRetryAfter(t *testing.T) {
+	// the exponential backoff is long enough to make sure the Retry-After header is used
+	setRetryDelays(t, time.Minute, time.Hour)
+
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&count, 1) {
+		case 1:
+			// the throttled request is retried for all methods
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.Header().Set("Retry-After", time.Now().UTC().Format(http.TimeFormat))
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	startTime := time.Now()
+	resp, err := newLogRoundTripperClient(5).Post(server.URL, "application/json", strings.NewReader(`{}`))
+	th.AssertNoErr(t, err)
+	resp.Body.Close()
+	th.AssertEquals(t, http.StatusOK, resp.StatusCode)
+	th.AssertEquals(t, int32(3), atomic.LoadInt32(&count))
+	th.AssertEquals(t, true, time.Since(startTime) < 10*time.Second)
+
+	// the throttled request without Retry-After header is retried by the RetryBackoffFunc of golangsdk
+	throttledServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer throttledServer.Close()
+
+	atomic.StoreInt32(&count, 0)
+	resp, err = newLogRoundTripperClient(5).Get(throttledServer.URL)
+	th.AssertNoErr(t, err)
+	resp.Body.Close()
+	th.AssertEquals(t, http.StatusTooManyRequests, resp.StatusCode)
+	th.AssertEquals(t, int32(1), atomic.LoadInt32(&count))
+}
+
+func TestLogRoundTripperRetryConnectionError(t *testing.T) {
+	setRetryDelays(t, time.Millisecond, time.Minute)
+
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		// reset the connection without a response
+		hj, ok := w.(http.Hijacker)
+		th.AssertEquals(t, true, ok)
+		conn, _, err := hj.Hijack()
+		th.AssertNoErr(t, err)
+		conn.Close()
+	}))
+	defer server.Close()
+
+	// the idempotent request is retried until the retries are exhausted
+	_, err := newLogRoundTripperClient(2).Get(server.URL)
+	th.AssertEquals(t, true, err != nil)
+	th.AssertEquals(t, true, strings.Contains(err.Error(), "retries exhausted"))
+	th.AssertEquals(t, int32(3), atomic.LoadInt32(&count))
+
+	// the non-idempotent request is not retried if it has been sent
+	atomic.StoreInt32(&count, 0)
+	_, err = newLogRoundTripperClient(2).Post(server.URL, "application/json", strings.NewReader(`{}`))
+	th.AssertEquals(t, true, err != nil)
+	th.AssertEquals(t, int32(1), atomic.LoadInt32(&count))
+
+	// the request is retried for all methods if it's not sent
+	server.Close()
+	startTime := time.Now()
+	_, err = newLogRoundTripperClient(2).Post(server.URL, "application/json", strings.NewReader(`{}`))
+	th.AssertEquals(t, true, err != nil)
+	th.AssertEquals(t, true, strings.Contains(err.Error(), "retries exhausted"))
+	th.AssertEquals(t, true, time.Since(startTime) < 10*time.Second)
+}
+
+func TestLogRoundTripperRetryLimits(t *testing.T) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	// the request is not retried if the maximum elapsed time will be exceeded
+	setRetryDelays(t, time.Second, 100*time.Millisecond)
+	resp, err := newLogRoundTripperClient(5).Get(server.URL)
+	th.AssertNoErr(t, err)
+	resp.Body.Close()
+	th.AssertEquals(t, http.StatusBadGateway, resp.StatusCode)
+	th.AssertEquals(t, int32(1), atomic.LoadInt32(&count))
+
+	// the retry is cancelled with the context of the request
+	setRetryDelays(t, time.Minute, time.Hour)
+	atomic.StoreInt32(&count, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	th.AssertNoErr(t, err)
+
+	startTime := time.Now()
+	_, err = newLogRoundTripperClient(5).Do(req)
+	th.AssertEquals(t, true, err != nil)
+	th.AssertEquals(t, true, strings.Contains(err.Error(), context.DeadlineExceeded.Error()))
+	th.AssertEquals(t, true, time.Since(startTime) < 10*time.Second)
+	th.AssertEquals(t, int32(1), atomic.LoadInt32(&count))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{value: "", ok: false},
+		{value: "3", expected: 3 * time.Second, ok: true},
+		{value: "invalid", ok: false},
+		{value: "Mon, 02 Jan 2006 15:04:05 GMT", expected: 0, ok: true},
+	}
+
+	for _, tc := range testCases {
+		resp := &http.Response{Header: http.Header{}}
+		resp.Header.Set("Retry-After", tc.value)
+
+		delay, ok := parseRetryAfter(resp)
+		th.AssertEquals(t, tc.ok, ok)
+		th.AssertEquals(t, tc.expected, delay)
+	}
+	th.AssertEquals(t, fmt.Sprint(maxTimeout), fmt.Sprint(retryDelay(&http.Response{
+		Header: http.Header{"Retry-After": []string{"86400"}},
+	}, 1)))
+}
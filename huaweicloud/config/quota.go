@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// QuotaUsage is the quota limit and the used count of a quota type, a negative limit means unlimited.
+type QuotaUsage struct {
+	Limit int
+	Used  int
+}
+
+// QuotaQueryFunc queries the quota usages of a service in a region, the result is keyed by the quota types.
+type QuotaQueryFunc func() (map[string]QuotaUsage, error)
+
+// quotaTracker aggregates the quotas requested by the resources being created in a plan. The quota usages are only
+// queried once for each service in each region, so the resources created during the apply do not count twice.
+type quotaTracker struct {
+	mu sync.Mutex
+	// usages is keyed by {region}/{service}
+	usages map[string]map[string]QuotaUsage
+	// planned is keyed by {region}/{service}/{quota type}
+	planned map[string]int
+}
+
+func newQuotaTracker(enabled bool) *quotaTracker {
+	if !enabled {
+		return nil
+	}
+
+	return &quotaTracker{
+		usages:  make(map[string]map[string]QuotaUsage),
+		planned: make(map[string]int),
+	}
+}
+
+// reserve adds the demands to the planned counts if all quotas are sufficient, otherwise returns an error with the
+// insufficient quotas.
+func (qt *quotaTracker) reserve(region, service string, demands map[string]int, query QuotaQueryFunc) error {
+	qt.mu.Lock()
+	defer qt.mu.Unlock()
+
+	usageKey := region + "/" + service
+	usages, ok := qt.usages[usageKey]
+	if !ok {
+		var err error
+		if usages, err = query(); err != nil {
+			return fmt.Errorf("error querying the %s quotas in %s: %s", service, region, err)
+		}
+		qt.usages[usageKey] = usages
+	}
+
+	quotaTypes := make([]string, 0, len(demands))
+	for quotaType := range demands {
+		quotaTypes = append(quotaTypes, quotaType)
+	}
+	sort.Strings(quotaTypes)
+
+	var insufficient []string
+	for _, quotaType := range quotaTypes {
+		usage, ok := usages[quotaType]
+		if !ok || usage.Limit < 0 {
+			continue
+		}
+
+		planned := qt.planned[usageKey+"/"+quotaType]
+		if usage.Used+planned+demands[quotaType] > usage.Limit {
+			insufficient = append(insufficient, fmt.Sprintf("%s (limit: %d, used: %d, planned: %d, requested: %d)",
+				quotaType, usage.Limit, usage.Used, planned, demands[quotaType]))
+		}
+	}
+	if len(insufficient) > 0 {
+		return fmt.Errorf("the %s quotas in %s are insufficient: %s", service, region,
+			strings.Join(insufficient, ", "))
+	}
+
+	for quotaType, count := range demands {
+		qt.planned[usageKey+"/"+quotaType] += count
+	}
+	return nil
+}
+
+// ReserveQuota checks whether the quotas of the service are sufficient for the demands of the resource being created,
+// the demands are keyed by the quota types and aggregated with the other resources in the same plan.
+// Nothing is checked if the preflight_quota_checks is not enabled.
+func (c *Config) ReserveQuota(region, service string, demands map[string]int, query QuotaQueryFunc) error {
+	if c.quotaTracker == nil || len(demands) == 0 {
+		return nil
+	}
+	return c.quotaTracker.reserve(region, service, demands, query)
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+func TestReserveQuota(t *testing.T) {
+	queried := 0
+	query := func() (map[string]QuotaUsage, error) {
+		queried++
+		return map[string]QuotaUsage{
+			"instances": {Limit: 10, Used: 7},
+			"cores":     {Limit: -1, Used: 100},
+		}, nil
+	}
+
+	// nothing is checked if the preflight quota checks are not enabled
+	cfg := &Config{}
+	th.AssertNoErr(t, cfg.ReserveQuota("cn-north-4", "ecs", map[string]int{"instances": 100}, query))
+	th.AssertEquals(t, 0, queried)
+
+	cfg.quotaTracker = newQuotaTracker(true)
+	for i := 0; i < 3; i++ {
+		th.AssertNoErr(t, cfg.ReserveQuota("cn-north-4", "ecs", map[string]int{"instances": 1, "cores": 8}, query))
+	}
+	// the usages are queried once, and the planned counts are aggregated
+	th.AssertEquals(t, 1, queried)
+	err := cfg.ReserveQuota("cn-north-4", "ecs", map[string]int{"instances": 1, "cores": 8}, query)
+	if err == nil || !strings.Contains(err.Error(), "instances (limit: 10, used: 7, planned: 3, requested: 1)") {
+		t.Fatalf("expect an error of the insufficient instances quota, but got: %v", err)
+	}
+
+	// the quotas are tracked by regions
+	th.AssertNoErr(t, cfg.ReserveQuota("cn-south-1", "ecs", map[string]int{"instances": 3}, query))
+	th.AssertEquals(t, 2, queried)
+
+	// the query error is not cached
+	failed := func() (map[string]QuotaUsage, error) {
+		return nil, fmt.Errorf("internal error")
+	}
+	if err := cfg.ReserveQuota("cn-east-3", "ecs", map[string]int{"instances": 1}, failed); err == nil {
+		t.Fatal("expect an error of the quota query")
+	}
+	th.AssertNoErr(t, cfg.ReserveQuota("cn-east-3", "ecs", map[string]int{"instances": 1}, query))
+}
@@ -0,0 +1,242 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// the rate is decreased to rateDecreaseFactor of the current one on throttling, but not less than
+	// minRateFactor of the configured one
+	rateDecreaseFactor = 0.5
+	minRateFactor      = 0.1
+	// the rate is increased by rateIncreaseFactor of the configured one on every successful response until it
+	// reaches the configured one
+	rateIncreaseFactor = 0.05
+)
+
+// RateLimit is the client-side rate limit of the requests sent to a service.
+type RateLimit struct {
+	// Service is the service catalog name or the service key of the endpoints, e.g. ecs, vpc, evs
+	Service string
+	// RPS is the maximum requests per second sent to the service in each region
+	RPS float64
+}
+
+// tokenBucket is an adaptive token bucket, the rate is decreased multiplicatively on throttling and increased
+// additively on success.
+type tokenBucket struct {
+	mu       sync.Mutex
+	maxRate  float64
+	rate     float64
+	burst    float64
+	tokens   float64
+	lastTime time.Time
+}
+
+func newTokenBucket(rps float64) *tokenBucket {
+	burst := math.Max(1, math.Ceil(rps))
+	return &tokenBucket{
+		maxRate: rps,
+		rate:    rps,
+		burst:   burst,
+		tokens:  burst,
+	}
+}
+
+// refill adds the tokens generated since the last time, the caller must hold the lock.
+func (b *tokenBucket) refill(now time.Time) {
+	if !b.lastTime.IsZero() && now.After(b.lastTime) {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.lastTime).Seconds()*b.rate)
+	}
+	b.lastTime = now
+}
+
+// reserve takes a token from the bucket and returns how long to wait before sending the request.
+// The token is reserved even if it's not available yet, so the concurrent requests are queued.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// throttled decreases the rate and drops the remaining tokens when the request is throttled by the service.
+func (b *tokenBucket) throttled(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	b.rate = math.Max(b.rate*rateDecreaseFactor, b.maxRate*minRateFactor)
+	b.tokens = math.Min(b.tokens, 0)
+}
+
+// succeeded increases the rate until it reaches the configured one.
+func (b *tokenBucket) succeeded(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.rate < b.maxRate {
+		b.refill(now)
+		b.rate = math.Min(b.rate+b.maxRate*rateIncreaseFactor, b.maxRate)
+	}
+}
+
+func (b *tokenBucket) currentRate() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rate
+}
+
+// rateLimiter holds the token buckets of the services, each service in each region has a bucket which is shared by
+// all clients of the service.
+type rateLimiter struct {
+	mu     sync.RWMutex
+	limits map[string]float64
+	// buckets is keyed by {service catalog name}/{region}
+	buckets map[string]*tokenBucket
+	// hosts is the map of endpoint host and bucket
+	hosts map[string]*tokenBucket
+}
+
+func newRateLimiter(limits []RateLimit) *rateLimiter {
+	if len(limits) == 0 {
+		return nil
+	}
+
+	rl := rateLimiter{
+		limits:  make(map[string]float64, len(limits)),
+		buckets: make(map[string]*tokenBucket),
+		hosts:   make(map[string]*tokenBucket),
+	}
+	for _, limit := range limits {
+		rl.limits[limit.Service] = limit.RPS
+	}
+	return &rl
+}
+
+// register associates the endpoint of a service client with the bucket of the service in the region.
+// The service is matched with both the service key of the endpoints and the service catalog name.
+func (rl *rateLimiter) register(srv, catalogName, region, endpoint string) {
+	if rl == nil {
+		return
+	}
+
+	rps, ok := rl.limits[srv]
+	if !ok {
+		if rps, ok = rl.limits[catalogName]; !ok {
+			return
+		}
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		log.Printf("[WARN] unable to limit the rate of %s service, invalid endpoint: %s", srv, endpoint)
+		return
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if _, ok := rl.hosts[u.Host]; ok {
+		return
+	}
+	key := fmt.Sprintf("%s/%s", catalogName, region)
+	bucket, ok := rl.buckets[key]
+	if !ok {
+		bucket = newTokenBucket(rps)
+		rl.buckets[key] = bucket
+	}
+	rl.hosts[u.Host] = bucket
+}
+
+func (rl *rateLimiter) getBucket(host string) *tokenBucket {
+	if rl == nil {
+		return nil
+	}
+
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	return rl.hosts[host]
+}
+
+// wait blocks until the request is allowed to be sent or the context is done.
+func (rl *rateLimiter) wait(ctx context.Context, host string) error {
+	bucket := rl.getBucket(host)
+	if bucket == nil {
+		return nil
+	}
+
+	delay := bucket.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	log.Printf("[DEBUG] rate limit of %s is reached, wait %s", host, delay)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// observe adapts the rate of the service with the response status code.
+func (rl *rateLimiter) observe(host string, statusCode int) {
+	bucket := rl.getBucket(host)
+	if bucket == nil {
+		return
+	}
+
+	if statusCode == http.StatusTooManyRequests {
+		bucket.throttled(time.Now())
+		log.Printf("[WARN] requests to %s are throttled, decrease the rate to %.2f requests per second",
+			host, bucket.currentRate())
+		return
+	}
+	if statusCode < http.StatusBadRequest {
+		bucket.succeeded(time.Now())
+	}
+}
+
+// rateLimitRoundTripper limits the rate of the requests sent to the services configured by rate_limits.
+type rateLimitRoundTripper struct {
+	Rt     http.RoundTripper
+	config *Config
+}
+
+func (rrt *rateLimitRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	limiter := rrt.config.rateLimiter
+	if err := limiter.wait(request.Context(), request.URL.Host); err != nil {
+		return nil, err
+	}
+
+	response, err := rrt.Rt.RoundTrip(request)
+	if response != nil {
+		limiter.observe(request.URL.Host, response.StatusCode)
+	}
+	return response, err
+}
+
+// jitteredBackoff returns a random duration in [0.5, 1) times of base * 2^retries, and not more than maxBackoff.
+func jitteredBackoff(base, maxBackoff time.Duration, retries uint) time.Duration {
+	backoff := float64(base) * math.Pow(2, float64(retries))
+	if backoff > float64(maxBackoff) {
+		backoff = float64(maxBackoff)
+	}
+	return time.Duration(backoff * (0.5 + rand.Float64()/2)) //nolint:gosec
+}
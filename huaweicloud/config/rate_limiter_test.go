@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	bucket := newTokenBucket(2)
+
+	// the burst requests are sent without waiting
+	th.AssertEquals(t, time.Duration(0), bucket.reserve(now))
+	th.AssertEquals(t, time.Duration(0), bucket.reserve(now))
+	// the following requests are queued
+	th.AssertEquals(t, 500*time.Millisecond, bucket.reserve(now))
+	th.AssertEquals(t, time.Second, bucket.reserve(now))
+
+	// the rate is halved on throttling, but not less than 10 percent of the configured one
+	bucket.throttled(now)
+	th.AssertEquals(t, 1.0, bucket.currentRate())
+	for i := 0; i < 5; i++ {
+		bucket.throttled(now)
+	}
+	th.AssertEquals(t, 0.2, bucket.currentRate())
+
+	// the rate recovers gradually on success
+	bucket.succeeded(now)
+	th.AssertEquals(t, "0.30", fmt.Sprintf("%.2f", bucket.currentRate()))
+	for i := 0; i < 100; i++ {
+		bucket.succeeded(now)
+	}
+	th.AssertEquals(t, 2.0, bucket.currentRate())
+}
+
+func TestRateLimitRoundTripper(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	var count int
+	th.Mux.HandleFunc("/route", func(w http.ResponseWriter, r *http.Request) {
+		count++
+		if count == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	cfg := &Config{
+		rateLimiter: newRateLimiter([]RateLimit{{Service: "ecs", RPS: 10}}),
+	}
+	// the unconfigured services are not limited
+	cfg.rateLimiter.register("vpc", "vpc", "region-0", "https://vpc.region-0.myhuaweicloud.com/")
+	th.AssertEquals(t, 0, len(cfg.rateLimiter.hosts))
+	// the derived services share the bucket with the primary one
+	cfg.rateLimiter.register("ecsv21", "ecs", "region-0", th.Endpoint())
+	cfg.rateLimiter.register("ecs", "ecs", "region-0", "https://ecs.region-0.myhuaweicloud.com/")
+	th.AssertEquals(t, 1, len(cfg.rateLimiter.buckets))
+
+	client := http.Client{
+		Transport: &rateLimitRoundTripper{Rt: http.DefaultTransport, config: cfg},
+	}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(th.Endpoint() + "route")
+		th.AssertNoErr(t, err)
+		resp.Body.Close()
+	}
+
+	u, err := url.Parse(th.Endpoint())
+	th.AssertNoErr(t, err)
+	bucket := cfg.rateLimiter.getBucket(u.Host)
+	th.AssertEquals(t, "5.50", fmt.Sprintf("%.2f", bucket.currentRate()))
+}
+
+func TestJitteredBackoff(t *testing.T) {
+	for retries := uint(0); retries < 10; retries++ {
+		backoff := jitteredBackoff(500*time.Millisecond, 30*time.Second, retries)
+		maxBackoff := 500 * time.Millisecond << retries
+		if maxBackoff > 30*time.Second {
+			maxBackoff = 30 * time.Second
+		}
+		if backoff < maxBackoff/2 || backoff >= maxBackoff {
+			t.Fatalf("the backoff of retry %d is expected in [%s, %s), but got %s", retries, maxBackoff/2,
+				maxBackoff, backoff)
+		}
+	}
+}
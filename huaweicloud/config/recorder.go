@@ -0,0 +1,564 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const (
+	// recorderModeEnv is the environment variable of the recorder mode, which is "record" or "replay"
+	recorderModeEnv = "HW_RECORDER_MODE"
+	// cassetteDirEnv is the environment variable of the directory where the cassettes are stored
+	cassetteDirEnv     = "HW_CASSETTE_DIR"
+	defaultCassetteDir = "testdata/cassettes"
+
+	RecorderModeRecord = "record"
+	RecorderModeReplay = "replay"
+)
+
+// ErrCassetteNotFound is returned in replay mode if the cassette of the test has not been recorded.
+var ErrCassetteNotFound = errors.New("cassette not found")
+
+var (
+	// timestampRegexp matches the timestamps in RFC3339 or compact format, e.g. 2006-01-02T15:04:05Z and 20060102T150405Z
+	timestampRegexp = regexp.MustCompile(`\d{4}-?\d{2}-?\d{2}T\d{2}:?\d{2}:?\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`)
+
+	// signingQueryParams are the query parameters of the AK/SK signatures and the presigned URLs
+	signingQueryParams = []string{"signature", "accesskeyid", "expires", "x-sdk-date", "x-obs-date", "x-amz-"}
+
+	// recordedRequestHeaders are the request headers written to the cassettes, the others, e.g. Authorization,
+	// X-Sdk-Date and X-Security-Token, contain the signatures and credentials
+	recordedRequestHeaders = []string{"Content-Type", "Accept"}
+
+	// requestIDHeaders are the response headers of the request IDs which are replaced by sequence numbers
+	requestIDHeaders = []string{"X-Request-Id", "X-Openstack-Request-Id", "X-Obs-Request-Id", "X-Compute-Request-Id"}
+
+	// recorderCond is used to wait for the active recorder to stop
+	recorderLock   sync.Mutex
+	recorderCond   = sync.NewCond(&recorderLock)
+	activeRecorder *Recorder
+)
+
+// Interaction is a recorded pair of API request and response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the sanitized API request in the cassette.
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the sanitized API response in the cassette, the body which is not UTF-8 encoded is stored
+// in base64.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"body_base64,omitempty"`
+}
+
+// Cassette is the file of the API interactions recorded in a test.
+type Cassette struct {
+	Name         string         `json:"name"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Recorder is an HTTP transport which records the API interactions to the cassette in record mode, and serves the
+// recorded responses without sending the requests in replay mode.
+type Recorder struct {
+	mode     string
+	path     string
+	cassette *Cassette
+	// rt is the default transport which sends the requests in record mode
+	rt http.RoundTripper
+
+	mu sync.Mutex
+	// used marks the interactions which have been served in replay mode
+	used []bool
+	// configs are the provider configurations whose credentials and IDs are sanitized in the cassette
+	configs []*Config
+}
+
+// RecorderMode returns the recorder mode specified by HW_RECORDER_MODE, or an empty string if it's disabled.
+func RecorderMode() string {
+	switch mode := strings.ToLower(os.Getenv(recorderModeEnv)); mode {
+	case RecorderModeRecord, RecorderModeReplay:
+		return mode
+	default:
+		return ""
+	}
+}
+
+// StartRecorder starts recording or replaying the cassette with the specified name, it returns nil if the recorder
+// mode is disabled. The cassette is stored in the directory specified by HW_CASSETTE_DIR, which defaults to
+// testdata/cassettes.
+// Only one recorder can be active at a time, since the provider of the acceptance tests is shared, so it waits for
+// the active recorder to stop, and the parallel tests are run one by one in record or replay mode.
+func StartRecorder(name string) (*Recorder, error) {
+	mode := RecorderMode()
+	if mode == "" {
+		return nil, nil
+	}
+
+	dir := os.Getenv(cassetteDirEnv)
+	if dir == "" {
+		dir = defaultCassetteDir
+	}
+	r, err := NewRecorder(mode, filepath.Join(dir, cassetteFileName(name)))
+	if err != nil {
+		return nil, err
+	}
+	r.cassette.Name = name
+
+	recorderLock.Lock()
+	defer recorderLock.Unlock()
+	for activeRecorder != nil {
+		log.Printf("[DEBUG] waiting for the recorder of %s to stop", activeRecorder.cassette.Name)
+		recorderCond.Wait()
+	}
+	activeRecorder = r
+	return r, nil
+}
+
+// ActiveRecorder returns the recorder started by StartRecorder, or nil if there is none.
+func ActiveRecorder() *Recorder {
+	recorderLock.Lock()
+	defer recorderLock.Unlock()
+	return activeRecorder
+}
+
+func cassetteFileName(name string) string {
+	return strings.NewReplacer("/", "_", "\\", "_", " ", "_").Replace(name) + ".json"
+}
+
+// NewRecorder creates a recorder of the cassette file, the cassette is loaded in replay mode.
+func NewRecorder(mode, path string) (*Recorder, error) {
+	r := Recorder{
+		mode:     mode,
+		path:     path,
+		cassette: &Cassette{},
+		rt: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+		},
+	}
+
+	switch mode {
+	case RecorderModeRecord:
+		return &r, nil
+	case RecorderModeReplay:
+		content, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("%w: %s", ErrCassetteNotFound, path)
+			}
+			return nil, err
+		}
+		if err := json.Unmarshal(content, r.cassette); err != nil {
+			return nil, fmt.Errorf("error parsing the cassette %s: %s", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+		return &r, nil
+	default:
+		return nil, fmt.Errorf("invalid recorder mode: %s", mode)
+	}
+}
+
+// Name returns the name of the cassette.
+func (r *Recorder) Name() string {
+	return r.cassette.Name
+}
+
+// Mode returns the mode of the recorder, which is "record" or "replay".
+func (r *Recorder) Mode() string {
+	return r.mode
+}
+
+// RoundTripper returns an HTTP transport which sends the requests through the recorder, the requests are sent by rt
+// in record mode, so that the TLS and proxy configurations of the provider are kept.
+func (r *Recorder) RoundTripper(rt http.RoundTripper) http.RoundTripper {
+	return &recorderRoundTripper{
+		recorder: r,
+		rt:       rt,
+	}
+}
+
+// Transport returns an HTTP transport which sends all requests through the recorder, it's used by the clients which
+// only accept *http.Transport, e.g. huaweicloud-sdk-go-v3.
+func (r *Recorder) Transport(rt http.RoundTripper) *http.Transport {
+	transport := &http.Transport{}
+	recorderRt := r.RoundTripper(rt)
+	transport.RegisterProtocol("http", recorderRt)
+	transport.RegisterProtocol("https", recorderRt)
+	return transport
+}
+
+// registerConfig makes the credentials and IDs of the provider configuration sanitized in the cassette.
+func (r *Recorder) registerConfig(c *Config) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configs = append(r.configs, c)
+}
+
+// RoundTrip implements the http.RoundTripper interface, the requests are sent by the default transport in record mode.
+func (r *Recorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	return r.roundTrip(request, r.rt)
+}
+
+// recorderRoundTripper records the requests sent by rt, or serves the recorded responses.
+type recorderRoundTripper struct {
+	recorder *Recorder
+	rt       http.RoundTripper
+}
+
+func (rrt *recorderRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	return rrt.recorder.roundTrip(request, rrt.rt)
+}
+
+func (r *Recorder) roundTrip(request *http.Request, rt http.RoundTripper) (*http.Response, error) {
+	var body []byte
+	if request.Body != nil {
+		var err error
+		body, err = io.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.mode == RecorderModeReplay {
+		return r.replay(request)
+	}
+	return r.record(request, body, rt)
+}
+
+func (r *Recorder) record(request *http.Request, body []byte, rt http.RoundTripper) (*http.Response, error) {
+	response, err := rt.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method:  request.Method,
+			URL:     request.URL.String(),
+			Headers: http.Header{},
+			Body:    string(body),
+		},
+		Response: RecordedResponse{
+			StatusCode: response.StatusCode,
+			Headers:    response.Header.Clone(),
+		},
+	}
+	for _, key := range recordedRequestHeaders {
+		if value := request.Header.Get(key); value != "" {
+			interaction.Request.Headers.Set(key, value)
+		}
+	}
+	if utf8.Valid(responseBody) {
+		interaction.Response.Body = string(responseBody)
+	} else {
+		interaction.Response.BodyBase64 = base64.StdEncoding.EncodeToString(responseBody)
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &interaction)
+	r.mu.Unlock()
+	return response, nil
+}
+
+// replay serves the first unused interaction which matches the request, the last matched one is served again if
+// all of them have been used, e.g. polling the status of a resource more times than recorded.
+func (r *Recorder) replay(request *http.Request) (*http.Response, error) {
+	key := NormalizeRequest(request.Method, request.URL.String())
+
+	r.mu.Lock()
+	matched := -1
+	for i, interaction := range r.cassette.Interactions {
+		if NormalizeRequest(interaction.Request.Method, interaction.Request.URL) != key {
+			continue
+		}
+		matched = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if matched >= 0 {
+		r.used[matched] = true
+	}
+	r.mu.Unlock()
+
+	if matched < 0 {
+		return nil, fmt.Errorf("no interaction recorded in %s for the request: %s %s", r.path, request.Method,
+			request.URL.Redacted())
+	}
+
+	recorded := r.cassette.Interactions[matched].Response
+	body := []byte(recorded.Body)
+	if recorded.BodyBase64 != "" {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(recorded.BodyBase64); err != nil {
+			return nil, err
+		}
+	}
+
+	header := recorded.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       request,
+	}, nil
+}
+
+// Stop deactivates the recorder, and writes the sanitized cassette in record mode.
+func (r *Recorder) Stop() error {
+	recorderLock.Lock()
+	if activeRecorder == r {
+		activeRecorder = nil
+		recorderCond.Broadcast()
+	}
+	recorderLock.Unlock()
+
+	if r.mode != RecorderModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sanitizer := newCassetteSanitizer(r.configs)
+	for i, interaction := range r.cassette.Interactions {
+		sanitizer.sanitize(interaction, i+1)
+	}
+
+	content, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] writing %d interactions to the cassette %s", len(r.cassette.Interactions), r.path)
+	return os.WriteFile(r.path, append(content, '\n'), 0600)
+}
+
+// NormalizeRequest returns the key of the request which is used to match the recorded interactions. The query
+// parameters of the AK/SK signatures are removed, the timestamps are replaced with a placeholder and the others are
+// sorted, so the key is the same in every test run.
+func NormalizeRequest(method, rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return strings.ToUpper(method) + " " + rawURL
+	}
+
+	query := u.Query()
+	for key := range query {
+		if isSigningQueryParam(key) {
+			query.Del(key)
+			continue
+		}
+		values := query[key]
+		for i := range values {
+			values[i] = timestampRegexp.ReplaceAllString(values[i], "{timestamp}")
+		}
+	}
+
+	// the default ports are omitted, e.g. https://vpc.cn-north-4.myhuaweicloud.com:443
+	host := strings.ToLower(u.Host)
+	if (u.Scheme == "https" && strings.HasSuffix(host, ":443")) || (u.Scheme == "http" && strings.HasSuffix(host, ":80")) {
+		host = host[:strings.LastIndex(host, ":")]
+	}
+
+	key := fmt.Sprintf("%s %s%s", strings.ToUpper(method), host, u.EscapedPath())
+	if encoded := query.Encode(); encoded != "" {
+		key += "?" + encoded
+	}
+	return key
+}
+
+func isSigningQueryParam(key string) bool {
+	key = strings.ToLower(key)
+	for _, param := range signingQueryParams {
+		if key == param || (strings.HasSuffix(param, "-") && strings.HasPrefix(key, param)) {
+			return true
+		}
+	}
+	return false
+}
+
+// cassetteSanitizer replaces the credentials and the account IDs in the cassette with fake values.
+type cassetteSanitizer struct {
+	replacer *strings.Replacer
+}
+
+func newCassetteSanitizer(configs []*Config) *cassetteSanitizer {
+	secrets := make(map[string]string)
+	var hexIDs int
+	addSecret := func(value, placeholder string) {
+		// the short values are not replaced to avoid corrupting the other contents
+		if len(value) < 4 {
+			return
+		}
+		if _, ok := secrets[value]; !ok {
+			secrets[value] = placeholder
+		}
+	}
+	// the fake IDs have the same format as the real ones, so that they can be validated
+	addID := func(value string) {
+		if _, ok := secrets[value]; !ok && len(value) >= 4 {
+			hexIDs++
+			addSecret(value, fmt.Sprintf("%0*x", len(value), hexIDs))
+		}
+	}
+
+	for _, c := range configs {
+		addSecret(c.AccessKey, "RECORDEDACCESSKEY")
+		addSecret(c.SecretKey, "recorded-secret-key")
+		addSecret(c.SecurityToken, "recorded-security-token")
+		addSecret(c.DomainName, "recorded-domain")
+		addSecret(c.Username, "recorded-user")
+		addID(c.DomainID)
+		addID(c.UserID)
+		addID(c.TenantID)
+
+		regions := make([]string, 0, len(c.RegionProjectIDMap))
+		for region := range c.RegionProjectIDMap {
+			regions = append(regions, region)
+		}
+		sort.Strings(regions)
+		for _, region := range regions {
+			addID(c.RegionProjectIDMap[region])
+		}
+	}
+
+	// the longer values are replaced first, in case that a value contains another one
+	values := make([]string, 0, len(secrets))
+	for value := range secrets {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if len(values[i]) != len(values[j]) {
+			return len(values[i]) > len(values[j])
+		}
+		return values[i] < values[j]
+	})
+
+	oldnew := make([]string, 0, 2*len(values))
+	for _, value := range values {
+		oldnew = append(oldnew, value, secrets[value])
+	}
+	return &cassetteSanitizer{
+		replacer: strings.NewReplacer(oldnew...),
+	}
+}
+
+func (s *cassetteSanitizer) sanitize(interaction *Interaction, sequence int) {
+	request := &interaction.Request
+	request.URL = s.replacer.Replace(request.URL)
+	if u, err := url.Parse(request.URL); err == nil {
+		query := u.Query()
+		for key := range query {
+			if isSigningQueryParam(key) {
+				query.Del(key)
+			}
+		}
+		u.RawQuery = query.Encode()
+		request.URL = u.String()
+	}
+	request.Body = timestampRegexp.ReplaceAllString(s.sanitizeBody(request.Body), "{timestamp}")
+	s.sanitizeHeaders(request.Headers)
+
+	response := &interaction.Response
+	response.Body = s.sanitizeBody(response.Body)
+	response.Headers.Del("Set-Cookie")
+	response.Headers.Del("Date")
+	for _, key := range requestIDHeaders {
+		if response.Headers.Get(key) != "" {
+			response.Headers.Set(key, fmt.Sprintf("recorded-request-%d", sequence))
+		}
+	}
+	s.sanitizeHeaders(response.Headers)
+}
+
+func (s *cassetteSanitizer) sanitizeHeaders(header http.Header) {
+	for key, values := range header {
+		for i := range values {
+			values[i] = s.replacer.Replace(values[i])
+		}
+		header[key] = values
+	}
+}
+
+// sanitizeBody masks the sensitive fields of the JSON body, and replaces the credentials and IDs in any body.
+func (s *cassetteSanitizer) sanitizeBody(body string) string {
+	if body == "" {
+		return body
+	}
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(body), &data); err == nil {
+		maskRecordedFields(data)
+		if masked, err := json.Marshal(data); err == nil {
+			body = string(masked)
+		}
+	}
+	return s.replacer.Replace(body)
+}
+
+// maskRecordedFields masks the sensitive fields of the JSON value, unlike maskSecurityFields, the long strings are
+// kept since they're needed in replay mode.
+func maskRecordedFields(data interface{}) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			if _, ok := val.(string); ok && isSecurityFields(key) {
+				v[key] = "***"
+				continue
+			}
+			maskRecordedFields(val)
+		}
+	case []interface{}:
+		for _, val := range v {
+			maskRecordedFields(val)
+		}
+	}
+}
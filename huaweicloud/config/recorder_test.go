@@ -0,0 +1,169 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+func TestNormalizeRequest(t *testing.T) {
+	testCases := map[string]string{
+		"https://vpc.cn-north-4.myhuaweicloud.com:443/v1/vpcs?limit=10&marker=abc":                   "GET vpc.cn-north-4.myhuaweicloud.com/v1/vpcs?limit=10&marker=abc",
+		"https://vpc.cn-north-4.myhuaweicloud.com/v1/vpcs?marker=abc&limit=10":                       "GET vpc.cn-north-4.myhuaweicloud.com/v1/vpcs?limit=10&marker=abc",
+		"https://bucket.obs.cn-north-4.myhuaweicloud.com/key?AccessKeyId=AK&Expires=1&Signature=abc": "GET bucket.obs.cn-north-4.myhuaweicloud.com/key",
+		"https://ces.cn-north-4.myhuaweicloud.com/v1/metrics?from=2024-01-02T15:04:05Z":              "GET ces.cn-north-4.myhuaweicloud.com/v1/metrics?from=%7Btimestamp%7D",
+	}
+
+	for rawURL, expected := range testCases {
+		th.AssertEquals(t, expected, NormalizeRequest("get", rawURL))
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	projectID := "0970dd7a1300f5672ff2c003c60ae115"
+	cassette := filepath.Join(t.TempDir(), "TestAccVpc_basic.json")
+
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "real-request-id")
+		_, _ = fmt.Fprintf(w, `{"vpc": {"id": "vpc-%d", "project_id": "%s", "adminPass": "secret"}}`,
+			atomic.AddInt32(&count, 1), projectID)
+	}))
+	defer server.Close()
+
+	sendRequests := func(client *http.Client) []string {
+		var bodies []string
+		for i := 0; i < 2; i++ {
+			req, err := http.NewRequest("PUT", fmt.Sprintf("%s/v1/%s/vpcs/1", server.URL, projectID),
+				strings.NewReader(`{"vpc": {"name": "test"}}`))
+			th.AssertNoErr(t, err)
+			req.Header.Set("Authorization", "SDK-HMAC-SHA256 Access=AK, Signature=abc")
+
+			resp, err := client.Do(req)
+			th.AssertNoErr(t, err)
+			body, err := io.ReadAll(resp.Body)
+			th.AssertNoErr(t, err)
+			resp.Body.Close()
+			bodies = append(bodies, string(body))
+		}
+		return bodies
+	}
+
+	// record the interactions
+	recorder, err := NewRecorder(RecorderModeRecord, cassette)
+	th.AssertNoErr(t, err)
+	recorder.registerConfig(&Config{
+		AccessKey:          "REALACCESSKEY",
+		RegionProjectIDMap: map[string]string{"cn-north-4": projectID},
+	})
+	recorded := sendRequests(&http.Client{Transport: recorder})
+	th.AssertNoErr(t, recorder.Stop())
+	th.AssertEquals(t, true, strings.Contains(recorded[0], projectID))
+
+	content, err := os.ReadFile(cassette)
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, false, strings.Contains(string(content), projectID))
+	th.AssertEquals(t, false, strings.Contains(string(content), "Authorization"))
+	th.AssertEquals(t, false, strings.Contains(string(content), "secret"))
+	th.AssertEquals(t, false, strings.Contains(string(content), "real-request-id"))
+
+	// replay the interactions without sending the requests
+	server.Close()
+	fakeProjectID := fmt.Sprintf("%032x", 1)
+	recorder, err = NewRecorder(RecorderModeReplay, cassette)
+	th.AssertNoErr(t, err)
+
+	client := &http.Client{Transport: recorder}
+	projectID = fakeProjectID
+	replayed := sendRequests(client)
+	th.AssertEquals(t, int32(2), atomic.LoadInt32(&count))
+	th.AssertEquals(t, true, strings.Contains(replayed[0], `"id":"vpc-1"`))
+	th.AssertEquals(t, true, strings.Contains(replayed[1], `"id":"vpc-2"`))
+	th.AssertEquals(t, true, strings.Contains(replayed[0], fakeProjectID))
+
+	// the last matched interaction is served again
+	replayed = sendRequests(client)
+	th.AssertEquals(t, true, strings.Contains(replayed[0], `"id":"vpc-2"`))
+
+	// the request which is not recorded is failed
+	_, err = client.Get(server.URL + "/v1/subnets")
+	th.AssertEquals(t, true, err != nil)
+
+	_, err = NewRecorder(RecorderModeReplay, filepath.Join(t.TempDir(), "not_found.json"))
+	th.AssertEquals(t, true, err != nil)
+}
+
+// countingRoundTripper counts the requests sent through it.
+type countingRoundTripper struct {
+	count int32
+}
+
+func (c *countingRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&c.count, 1)
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+func TestRecorderRoundTripper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	recorder, err := NewRecorder(RecorderModeRecord, filepath.Join(t.TempDir(), "TestAccVpc_basic.json"))
+	th.AssertNoErr(t, err)
+
+	// the requests are sent by the transport of the provider in record mode
+	rt := &countingRoundTripper{}
+	for _, client := range []*http.Client{
+		{Transport: recorder.RoundTripper(rt)},
+		{Transport: recorder.Transport(rt)},
+	} {
+		resp, err := client.Get(server.URL + "/v1/vpcs")
+		th.AssertNoErr(t, err)
+		resp.Body.Close()
+	}
+	th.AssertEquals(t, int32(2), atomic.LoadInt32(&rt.count))
+	th.AssertNoErr(t, recorder.Stop())
+	th.AssertEquals(t, 2, len(recorder.cassette.Interactions))
+}
+
+func TestStartRecorderInParallel(t *testing.T) {
+	t.Setenv(recorderModeEnv, RecorderModeRecord)
+	t.Setenv(cassetteDirEnv, t.TempDir())
+
+	first, err := StartRecorder("TestAccVpc_basic")
+	th.AssertNoErr(t, err)
+	th.AssertEquals(t, "TestAccVpc_basic", ActiveRecorder().Name())
+
+	// the recorder of the parallel test waits for the active one to stop
+	started := make(chan *Recorder)
+	go func() {
+		second, err := StartRecorder("TestAccVpc_update")
+		if err != nil {
+			t.Errorf("failed to start the recorder: %s", err)
+		}
+		started <- second
+	}()
+
+	select {
+	case <-started:
+		t.Fatal("the recorder is started before the active one is stopped")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	th.AssertNoErr(t, first.Stop())
+	second := <-started
+	th.AssertEquals(t, "TestAccVpc_update", ActiveRecorder().Name())
+	th.AssertNoErr(t, second.Stop())
+	th.AssertEquals(t, true, ActiveRecorder() == nil)
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// retryBaseDelay is the base delay of the exponential backoff between the retries
+	retryBaseDelay = time.Second
+	// maxRetryElapsedTime is the maximum total time spent on a request including all retries
+	maxRetryElapsedTime = 30 * time.Minute
+)
+
+// idempotentMethods are the HTTP methods which can be retried safely even if the request has been processed
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+func isIdempotentMethod(method string) bool {
+	return idempotentMethods[strings.ToUpper(method)]
+}
+
+// isRequestNotSent returns whether the error occurs before the request is sent, e.g. failed to connect to the server.
+func isRequestNotSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// shouldRetry returns whether the request should be retried with the response or error of the last attempt.
+//   - connection errors: retried if the request is not sent or the method is idempotent
+//   - 429 Too Many Requests: retried if the Retry-After header is specified, otherwise it's retried by the
+//     RetryBackoffFunc of golangsdk
+//   - 502 Bad Gateway, 503 Service Unavailable and 504 Gateway Timeout: retried if the method is idempotent
+func shouldRetry(request *http.Request, response *http.Response, err error) bool {
+	if response == nil {
+		if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		if strings.Contains(err.Error(), "no such host") {
+			return false
+		}
+		return isRequestNotSent(err) || isIdempotentMethod(request.Method)
+	}
+
+	switch response.StatusCode {
+	case http.StatusTooManyRequests:
+		return response.Header.Get("Retry-After") != ""
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotentMethod(request.Method)
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses the Retry-After header which is either the delay seconds or an HTTP date.
+func parseRetryAfter(response *http.Response) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+
+	value := strings.TrimSpace(response.Header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// retryDelay returns how long to wait before the next retry, the Retry-After header takes precedence over the
+// exponential backoff.
+func retryDelay(response *http.Response, retry int) time.Duration {
+	if delay, ok := parseRetryAfter(response); ok {
+		if delay > maxTimeout {
+			delay = maxTimeout
+		}
+		return delay
+	}
+	return retryTimeout(retry)
+}
+
+// sleepWithContext waits for the duration, it returns the error of the context if it's done before that.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName         = "github.com/huaweicloud/terraform-provider-huaweicloud"
+	defaultServiceName = "terraform-provider-huaweicloud"
+	// the timeout of exporting the remaining spans when the provider is shut down
+	telemetryShutdownTimeout = 5 * time.Second
+)
+
+var telemetryOnce sync.Once
+
+// InitTelemetry sets up the OpenTelemetry tracer provider which exports the spans to the OTLP/HTTP endpoint.
+// It's disabled by default, and only enabled if the endpoint is specified by the standard environment variables
+// OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT. The other OTEL_EXPORTER_OTLP_* variables, e.g.
+// headers and timeout, and OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES are also supported.
+func InitTelemetry(ctx context.Context) {
+	telemetryOnce.Do(func() {
+		if !isTelemetryEnabled() {
+			return
+		}
+
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			log.Printf("[WARN] failed to create the OTLP trace exporter: %s", err)
+			return
+		}
+
+		// the service name in the environment variables takes precedence over the default one
+		res, err := resource.New(ctx,
+			resource.WithAttributes(semconv.ServiceNameKey.String(defaultServiceName)),
+			resource.WithTelemetrySDK(),
+			resource.WithFromEnv(),
+		)
+		if err != nil {
+			log.Printf("[WARN] failed to detect the OpenTelemetry resource: %s", err)
+		}
+
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithResource(res),
+		))
+		log.Printf("[DEBUG] OpenTelemetry tracing is enabled")
+	})
+}
+
+func isTelemetryEnabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// isTracerProviderSet returns whether the spans are recorded by an SDK tracer provider, the global tracer provider is
+// a no-op one unless the telemetry is enabled.
+func isTracerProviderSet() bool {
+	_, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	return ok
+}
+
+func getTracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// ShutdownTelemetry exports the remaining spans and shuts down the SDK tracer provider, it should be called once the
+// provider server is stopped, since the spans are exported in batches.
+func ShutdownTelemetry() {
+	tp, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryShutdownTimeout)
+	defer cancel()
+	if err := tp.Shutdown(ctx); err != nil {
+		log.Printf("[WARN] failed to export the OpenTelemetry spans: %s", err)
+	}
+}
+
+// startOperationSpan starts the span of a resource operation, e.g. huaweicloud_vpc.create.
+func startOperationSpan(ctx context.Context, op *resourceOperation) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("tf.resource_type", op.resourceType),
+		attribute.String("tf.operation", op.operation),
+	}
+	if op.d != nil && op.d.Id() != "" {
+		attrs = append(attrs, attribute.String("tf.resource_id", op.d.Id()))
+	}
+	return getTracer().Start(ctx, op.resourceType+"."+op.operation, trace.WithAttributes(attrs...))
+}
+
+// endOperationSpan ends the span of a resource operation with the resource ID and the error if any.
+func endOperationSpan(span trace.Span, d *schema.ResourceData, err error) {
+	if d != nil && d.Id() != "" {
+		span.SetAttributes(attribute.String("tf.resource_id", d.Id()))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func httpSpanAttributes(method, host, path string) []attribute.KeyValue {
+	service, region := lookupServiceEndpoint(host)
+	attrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(method),
+		semconv.NetPeerNameKey.String(host),
+		attribute.String("http.url_template", urlTemplate(path, "")),
+	}
+	if service != "" {
+		attrs = append(attrs, attribute.String("huaweicloud.service", service))
+	}
+	if region != "" {
+		attrs = append(attrs, attribute.String("huaweicloud.region", region))
+	}
+	return attrs
+}
+
+// startHTTPSpan starts the client span of the API request sent by golangsdk.
+func startHTTPSpan(request *http.Request) trace.Span {
+	_, span := getTracer().Start(request.Context(), "HTTP "+request.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(httpSpanAttributes(request.Method, request.URL.Host, request.URL.Path)...),
+	)
+	return span
+}
+
+// endHTTPSpan ends the client span of the API request with the response or error.
+func endHTTPSpan(span trace.Span, response *http.Response, err error, retries int) {
+	span.SetAttributes(attribute.Int("huaweicloud.retries", retries))
+	if response != nil {
+		setHTTPSpanStatus(span, response.StatusCode, response.Header.Get("X-Request-Id"))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func setHTTPSpanStatus(span trace.Span, statusCode int, requestID string) {
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(statusCode))
+	if requestID != "" {
+		span.SetAttributes(attribute.String("huaweicloud.request_id", requestID))
+	}
+	if statusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(statusCode))
+	}
+}
+
+// recordHcSpan records the client span of the API request sent by huaweicloud-sdk-go-v3 after it's finished, since
+// the request and response handlers can not share the span.
+func recordHcSpan(ctx context.Context, method, host, path string, statusCode int, requestID string,
+	latency time.Duration) {
+	endTime := time.Now()
+	_, span := getTracer().Start(ctx, "HTTP "+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(endTime.Add(-latency)),
+		trace.WithAttributes(httpSpanAttributes(method, host, path)...),
+	)
+	setHTTPSpanStatus(span, statusCode, requestID)
+	span.End(trace.WithTimestamp(endTime))
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/chnsz/golangsdk"
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+// setInMemoryTracerProvider exports the spans to memory and restores the global tracer provider after the test.
+func setInMemoryTracerProvider(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	oldProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(oldProvider)
+	})
+	return exporter
+}
+
+func getSpanAttribute(span tracetest.SpanStub, key attribute.Key) string {
+	for _, attr := range span.Attributes {
+		if attr.Key == key {
+			return attr.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestTelemetrySpans(t *testing.T) {
+	exporter := setInMemoryTracerProvider(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "request-id")
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	registerServiceEndpoint("vpc", "region-0", server.URL)
+
+	client := newLogRoundTripperClient(0)
+	sendRequest := func(ctx context.Context, method string) error {
+		req, err := http.NewRequestWithContext(ctx, method, server.URL+"/v1/vpcs/12345", nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+		return nil
+	}
+
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		ReadContext: func(ctx context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+			return diag.FromErr(sendRequest(ctx, http.MethodGet))
+		},
+		Delete: func(_ *schema.ResourceData, meta interface{}) error {
+			// the legacy operation sends the requests with the context of the clients in the Config
+			return sendRequest(meta.(*Config).HwClient.Context, http.MethodDelete)
+		},
+	}
+	EnableAPITrace("huaweicloud_vpc", r)
+
+	d := r.TestResourceData()
+	d.SetId("12345")
+	diags := r.ReadContext(context.Background(), d, nil)
+	th.AssertEquals(t, false, diags.HasError())
+	th.AssertEquals(t, true, r.Delete(d, &Config{HwClient: &golangsdk.ProviderClient{}}) != nil)
+
+	spans := exporter.GetSpans()
+	th.AssertEquals(t, 4, len(spans))
+
+	// the HTTP spans are ended before the operation spans
+	readRequest, readOperation, deleteRequest, deleteOperation := spans[0], spans[1], spans[2], spans[3]
+
+	th.AssertEquals(t, "huaweicloud_vpc.read", readOperation.Name)
+	th.AssertEquals(t, "huaweicloud_vpc", getSpanAttribute(readOperation, "tf.resource_type"))
+	th.AssertEquals(t, "12345", getSpanAttribute(readOperation, "tf.resource_id"))
+	th.AssertEquals(t, codes.Unset, readOperation.Status.Code)
+
+	th.AssertEquals(t, "HTTP GET", readRequest.Name)
+	th.AssertEquals(t, trace.SpanKindClient, readRequest.SpanKind)
+	th.AssertEquals(t, readOperation.SpanContext.SpanID(), readRequest.Parent.SpanID())
+	th.AssertEquals(t, "/v1/vpcs/{id}", getSpanAttribute(readRequest, "http.url_template"))
+	th.AssertEquals(t, "vpc", getSpanAttribute(readRequest, "huaweicloud.service"))
+	th.AssertEquals(t, "region-0", getSpanAttribute(readRequest, "huaweicloud.region"))
+	th.AssertEquals(t, "200", getSpanAttribute(readRequest, "http.status_code"))
+	th.AssertEquals(t, "request-id", getSpanAttribute(readRequest, "huaweicloud.request_id"))
+
+	// the legacy operation without context is also traced, and the errors are recorded
+	th.AssertEquals(t, "huaweicloud_vpc.delete", deleteOperation.Name)
+	th.AssertEquals(t, codes.Error, deleteOperation.Status.Code)
+	th.AssertEquals(t, deleteOperation.SpanContext.SpanID(), deleteRequest.Parent.SpanID())
+	th.AssertEquals(t, codes.Error, deleteRequest.Status.Code)
+
+	// the span is recorded for the requests sent by huaweicloud-sdk-go-v3
+	exporter.Reset()
+	recordHcSpan(context.Background(), http.MethodGet, "ecs.region-0.myhuaweicloud.com", "/v1/servers", http.StatusOK, "hc-request-id", 0)
+	spans = exporter.GetSpans()
+	th.AssertEquals(t, 1, len(spans))
+	th.AssertEquals(t, "ecs", getSpanAttribute(spans[0], "huaweicloud.service"))
+	th.AssertEquals(t, "hc-request-id", getSpanAttribute(spans[0], "huaweicloud.request_id"))
+}
+
+func TestTelemetryDisabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	th.AssertEquals(t, false, isTelemetryEnabled())
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	th.AssertEquals(t, true, isTelemetryEnabled())
+
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	th.AssertEquals(t, false, isTelemetryEnabled())
+}
+
+// keepSpansExporter keeps the exported spans after it's shut down.
+type keepSpansExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func (keepSpansExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestShutdownTelemetry(t *testing.T) {
+	exporter := keepSpansExporter{tracetest.NewInMemoryExporter()}
+	oldProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(oldProvider)
+	})
+
+	_, span := startOperationSpan(context.Background(), &resourceOperation{resourceType: "huaweicloud_vpc",
+		operation: "read"})
+	endOperationSpan(span, nil, nil)
+
+	// the spans are exported in batches, the remaining ones are exported when the provider is shut down
+	ShutdownTelemetry()
+	spans := exporter.GetSpans()
+	th.AssertEquals(t, 1, len(spans))
+	th.AssertEquals(t, "huaweicloud_vpc.read", spans[0].Name)
+}
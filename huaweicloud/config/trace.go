@@ -0,0 +1,403 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+// apiTraceFileEnv is the environment variable of the file path which the structured API trace log is written to,
+// the trace log is disabled if it's not set.
+const apiTraceFileEnv = "HW_API_TRACE_FILE"
+
+var (
+	// idSegmentRegexp matches the path segments which are the IDs of resources, e.g. UUID, hex string and number
+	idSegmentRegexp = regexp.MustCompile(`^([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-` +
+		`[0-9a-fA-F]{12}|[0-9a-fA-F]{16,}|[0-9]+)$`)
+
+	// serviceEndpoints is the map of endpoint host and the service in the region
+	serviceEndpoints sync.Map
+
+	tracerLock sync.Mutex
+	tracer     *apiTracer
+)
+
+// apiTraceRecord is a line of the structured API trace log.
+type apiTraceRecord struct {
+	Time         string          `json:"time"`
+	Method       string          `json:"method"`
+	URLTemplate  string          `json:"url_template"`
+	Host         string          `json:"host"`
+	Service      string          `json:"service,omitempty"`
+	Region       string          `json:"region,omitempty"`
+	Status       int             `json:"status,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	LatencyMs    int64           `json:"latency_ms"`
+	Retries      int             `json:"retries"`
+	RequestID    string          `json:"request_id,omitempty"`
+	Resource     string          `json:"resource,omitempty"`
+	ResourceID   string          `json:"resource_id,omitempty"`
+	Operation    string          `json:"operation,omitempty"`
+	RequestBody  json.RawMessage `json:"request_body,omitempty"`
+	ResponseBody json.RawMessage `json:"response_body,omitempty"`
+}
+
+type apiTracer struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// isAPITraceEnabled returns whether the structured API trace log is enabled by HW_API_TRACE_FILE.
+func isAPITraceEnabled() bool {
+	return os.Getenv(apiTraceFileEnv) != ""
+}
+
+// getAPITracer returns the tracer of the file specified by HW_API_TRACE_FILE, or nil if the trace log is disabled.
+func getAPITracer() *apiTracer {
+	if !isAPITraceEnabled() {
+		return nil
+	}
+	path := os.Getenv(apiTraceFileEnv)
+
+	tracerLock.Lock()
+	defer tracerLock.Unlock()
+
+	if tracer != nil && tracer.path == path {
+		return tracer
+	}
+
+	// the file is opened in append mode, so the lines written by multiple provider processes are not mixed
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		log.Printf("[WARN] failed to open the API trace file %s: %s", path, err)
+		return nil
+	}
+	if tracer != nil {
+		tracer.file.Close()
+	}
+	tracer = &apiTracer{
+		path: path,
+		file: file,
+	}
+	return tracer
+}
+
+func (t *apiTracer) write(record *apiTraceRecord) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		log.Printf("[WARN] failed to marshal the API trace record: %s", err)
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, err := t.file.Write(append(line, '\n')); err != nil {
+		log.Printf("[WARN] failed to write the API trace file %s: %s", t.path, err)
+	}
+}
+
+// traceHTTPRequest writes the trace record of the API request sent by golangsdk.
+func traceHTTPRequest(request *http.Request, requestBody []byte, response *http.Response, err error,
+	startTime time.Time, retries int) {
+	t := getAPITracer()
+	if t == nil {
+		return
+	}
+
+	record := newAPITraceRecord(resourceOperationFromContext(request.Context()), request.Method, request.URL,
+		request.Header.Get("X-Project-Id"), startTime, retries)
+	record.RequestBody = maskJSONBody(requestBody)
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if response != nil {
+		record.Status = response.StatusCode
+		record.RequestID = response.Header.Get("X-Request-Id")
+		if response.Body != nil {
+			body, readErr := io.ReadAll(response.Body)
+			response.Body.Close()
+			response.Body = io.NopCloser(bytes.NewReader(body))
+			if readErr == nil {
+				record.ResponseBody = maskJSONBody(body)
+			}
+		}
+	}
+	t.write(record)
+}
+
+// traceHcRequest writes the trace record of the API request sent by huaweicloud-sdk-go-v3, the bodies are not
+// available in the monitor handler, so the context of the resource operation is passed by the client builder.
+func traceHcRequest(ctx context.Context, method, host, path string, statusCode int, requestID string,
+	latency time.Duration) {
+	t := getAPITracer()
+	if t == nil {
+		return
+	}
+
+	u := &url.URL{Host: host, Path: path}
+	record := newAPITraceRecord(resourceOperationFromContext(ctx), method, u, "", time.Now().Add(-latency), 0)
+	record.Status = statusCode
+	record.RequestID = requestID
+	t.write(record)
+}
+
+func newAPITraceRecord(op *resourceOperation, method string, u *url.URL, projectID string, startTime time.Time,
+	retries int) *apiTraceRecord {
+	record := apiTraceRecord{
+		Time:        startTime.UTC().Format(time.RFC3339Nano),
+		Method:      method,
+		URLTemplate: urlTemplate(u.Path, projectID),
+		Host:        u.Host,
+		LatencyMs:   time.Since(startTime).Milliseconds(),
+		Retries:     retries,
+	}
+	record.Service, record.Region = lookupServiceEndpoint(u.Host)
+	if op != nil {
+		record.Resource = op.resourceType
+		record.Operation = op.operation
+		if op.d != nil {
+			record.ResourceID = op.d.Id()
+		}
+	}
+	return &record
+}
+
+// urlTemplate replaces the project ID and the resource IDs in the path with placeholders, so the requests of the
+// same API can be aggregated.
+func urlTemplate(path, projectID string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case segment == "":
+			continue
+		case projectID != "" && segment == projectID:
+			segments[i] = "{project_id}"
+		case idSegmentRegexp.MatchString(segment):
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// maskJSONBody masks the sensitive fields of the JSON body, the non-JSON body is not traced.
+func maskJSONBody(raw []byte) json.RawMessage {
+	var data map[string]interface{}
+	if len(raw) == 0 || json.Unmarshal(raw, &data) != nil {
+		return nil
+	}
+
+	// Ignore the catalog
+	if _, ok := data["catalog"]; ok {
+		return nil
+	}
+	maskSecurityFields(data)
+
+	masked, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+	return masked
+}
+
+// registerServiceEndpoint records the service and region of the endpoint for the trace log.
+func registerServiceEndpoint(service, region, endpoint string) {
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		serviceEndpoints.Store(u.Host, [2]string{service, region})
+	}
+}
+
+// lookupServiceEndpoint returns the service and region of the endpoint host, the host is parsed as
+// {service}.{region}.{cloud} if it's not registered.
+func lookupServiceEndpoint(host string) (service, region string) {
+	if v, ok := serviceEndpoints.Load(host); ok {
+		serviceRegion := v.([2]string)
+		return serviceRegion[0], serviceRegion[1]
+	}
+
+	parts := strings.Split(host, ".")
+	if len(parts) >= 4 {
+		return parts[0], parts[1]
+	}
+	return "", ""
+}
+
+// resourceOperation is the CRUD operation of a resource or data source.
+// Terraform does not send the resource address (e.g. huaweicloud_vpc.test[0]) to the provider, so the operation is
+// identified by the resource type and ID.
+type resourceOperation struct {
+	resourceType string
+	operation    string
+	d            *schema.ResourceData
+}
+
+// resourceOperationKey is the context key of the resource operation which sends the API requests.
+type resourceOperationKey struct{}
+
+func contextWithResourceOperation(ctx context.Context, op *resourceOperation) context.Context {
+	return context.WithValue(ctx, resourceOperationKey{}, op)
+}
+
+func resourceOperationFromContext(ctx context.Context) *resourceOperation {
+	if ctx == nil {
+		return nil
+	}
+	op, _ := ctx.Value(resourceOperationKey{}).(*resourceOperation)
+	return op
+}
+
+// withOperationContext returns a copy of the Config for the resource operation. The clients of the copy send the API
+// requests with the context of the operation, so the requests sent in any goroutine of the operation, e.g. the
+// refresh functions of StateChangeConf, are associated with the resource.
+func (c *Config) withOperationContext(ctx context.Context) *Config {
+	root := c
+	if c.root != nil {
+		root = c.root
+	}
+
+	// the security key may be being reloaded by the requests of other operations
+	if root.SecurityKeyLock != nil {
+		root.SecurityKeyLock.Lock()
+		defer root.SecurityKeyLock.Unlock()
+	}
+
+	// the requests are not cancelled with the operation, which is the same as the clients of the provider
+	ctx = context.WithoutCancel(ctx)
+	ctx = utils.ContextWithIgnoreTags(ctx, root.IgnoreTagKeys, root.IgnoreTagKeyPrefixes)
+	opConfig := *root
+	opConfig.root = root
+	opConfig.operationContext = ctx
+	opConfig.HwClient = withClientContext(root.HwClient, ctx)
+	opConfig.DomainClient = withClientContext(root.DomainClient, ctx)
+	return &opConfig
+}
+
+// requestContext returns the context of the resource operation which the Config is copied for, or the background
+// context if it's the Config of the provider.
+func (c *Config) requestContext() context.Context {
+	if c.operationContext != nil {
+		return c.operationContext
+	}
+	return context.Background()
+}
+
+// withClientContext returns a copy of the provider client which sends the requests with the context. The token of
+// the copy is reauthenticated by the original client, so that it's only reauthenticated once for all operations.
+func withClientContext(client *golangsdk.ProviderClient, ctx context.Context) *golangsdk.ProviderClient {
+	if client == nil {
+		return nil
+	}
+
+	token := client.Token()
+	opClient := *client
+	opClient.UseTokenLock()
+	opClient.TokenID = token
+	opClient.Context = ctx
+	if client.ReauthFunc != nil {
+		opClient.ReauthFunc = func() error {
+			if err := client.Reauthenticate(opClient.TokenID); err != nil {
+				return err
+			}
+			opClient.TokenID = client.Token()
+			return nil
+		}
+	}
+	return &opClient
+}
+
+// withOperationMeta returns the Config of the resource operation if the meta is the Config of the provider and the
+// API requests are traced, otherwise the meta is returned unchanged, so the Config is never copied by default.
+func withOperationMeta(ctx context.Context, meta interface{}) interface{} {
+	if !isAPITraceEnabled() && !isTracerProviderSet() {
+		return meta
+	}
+	if c, ok := meta.(*Config); ok && c != nil {
+		return c.withOperationContext(ctx)
+	}
+	return meta
+}
+
+// EnableAPITrace makes the API requests sent by the CRUD operations of the resource or data source traced with
+// the resource type and ID, and each operation is recorded as an OpenTelemetry span with the requests as children.
+// The data sources are named with a "data." prefix, e.g. data.huaweicloud_vpcs.
+func EnableAPITrace(resourceType string, r *schema.Resource) {
+	if r == nil {
+		return
+	}
+
+	r.CreateContext = traceContextFunc(resourceType, "create", r.CreateContext)
+	r.CreateWithoutTimeout = traceContextFunc(resourceType, "create", r.CreateWithoutTimeout)
+	r.Create = traceLegacyFunc(resourceType, "create", r.Create)
+	r.ReadContext = traceContextFunc(resourceType, "read", r.ReadContext)
+	r.ReadWithoutTimeout = traceContextFunc(resourceType, "read", r.ReadWithoutTimeout)
+	r.Read = traceLegacyFunc(resourceType, "read", r.Read)
+	r.UpdateContext = traceContextFunc(resourceType, "update", r.UpdateContext)
+	r.UpdateWithoutTimeout = traceContextFunc(resourceType, "update", r.UpdateWithoutTimeout)
+	r.Update = traceLegacyFunc(resourceType, "update", r.Update)
+	r.DeleteContext = traceContextFunc(resourceType, "delete", r.DeleteContext)
+	r.DeleteWithoutTimeout = traceContextFunc(resourceType, "delete", r.DeleteWithoutTimeout)
+	r.Delete = traceLegacyFunc(resourceType, "delete", r.Delete)
+}
+
+func traceContextFunc(resourceType, operation string, f contextFunc) contextFunc {
+	if f == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		op := &resourceOperation{resourceType: resourceType, operation: operation, d: d}
+		ctx, span := startOperationSpan(ctx, op)
+		ctx = contextWithResourceOperation(ctx, op)
+		logOperationTimeout(op)
+		diags := f(ctx, d, withOperationMeta(ctx, meta))
+		endOperationSpan(span, d, diagsError(diags))
+		return diags
+	}
+}
+
+func traceLegacyFunc(resourceType, operation string, f legacyFunc) legacyFunc {
+	if f == nil {
+		return nil
+	}
+
+	return func(d *schema.ResourceData, meta interface{}) error {
+		op := &resourceOperation{resourceType: resourceType, operation: operation, d: d}
+		ctx, span := startOperationSpan(context.Background(), op)
+		ctx = contextWithResourceOperation(ctx, op)
+		logOperationTimeout(op)
+		err := f(d, withOperationMeta(ctx, meta))
+		endOperationSpan(span, d, err)
+		return err
+	}
+}
+
+// diagsError returns the error summaries of the diagnostics, or nil if there is no error.
+func diagsError(diags diag.Diagnostics) error {
+	var summaries []string
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			summaries = append(summaries, d.Summary)
+		}
+	}
+	if len(summaries) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(summaries, "; "))
+}
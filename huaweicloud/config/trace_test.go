@@ -0,0 +1,187 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/chnsz/golangsdk"
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+func TestUrlTemplate(t *testing.T) {
+	projectID := "0970dd7a1300f5672ff2c003c60ae115"
+	testCases := map[string]string{
+		"/v1/" + projectID + "/vpcs":                                      "/v1/{project_id}/vpcs",
+		"/v1/" + projectID + "/vpcs/0c7a2a7a-5d38-4d0a-8d33-3b0b4f1c4e3a": "/v1/{project_id}/vpcs/{id}",
+		"/v2.1/" + projectID + "/servers/detail":                          "/v2.1/{project_id}/servers/detail",
+		"/v3/jobs/12345/status":                                           "/v3/jobs/{id}/status",
+		"/v3/projects":                                                    "/v3/projects",
+	}
+
+	for path, expected := range testCases {
+		th.AssertEquals(t, expected, urlTemplate(path, projectID))
+	}
+}
+
+func TestAPITrace(t *testing.T) {
+	traceFile := filepath.Join(t.TempDir(), "trace.log")
+	t.Setenv(apiTraceFileEnv, traceFile)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "request-id")
+		_, _ = fmt.Fprint(w, `{"user": {"name": "test", "password": "secret"}}`)
+	}))
+	defer server.Close()
+	registerServiceEndpoint("iam", "region-0", server.URL)
+
+	client := newLogRoundTripperClient(0)
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		ReadContext: func(ctx context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+			req, err := http.NewRequestWithContext(ctx, "PUT", server.URL+"/v3/users/12345", strings.NewReader(`{"password": "secret"}`))
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := client.Do(req)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			resp.Body.Close()
+			return nil
+		},
+	}
+	EnableAPITrace("huaweicloud_identity_user", r)
+
+	d := r.TestResourceData()
+	d.SetId("12345")
+	diags := r.ReadContext(context.Background(), d, nil)
+	th.AssertEquals(t, false, diags.HasError())
+
+	content, err := os.ReadFile(traceFile)
+	th.AssertNoErr(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	th.AssertEquals(t, 1, len(lines))
+
+	var record apiTraceRecord
+	th.AssertNoErr(t, json.Unmarshal([]byte(lines[0]), &record))
+	th.AssertEquals(t, "PUT", record.Method)
+	th.AssertEquals(t, "/v3/users/{id}", record.URLTemplate)
+	th.AssertEquals(t, "iam", record.Service)
+	th.AssertEquals(t, "region-0", record.Region)
+	th.AssertEquals(t, http.StatusOK, record.Status)
+	th.AssertEquals(t, "request-id", record.RequestID)
+	th.AssertEquals(t, "huaweicloud_identity_user", record.Resource)
+	th.AssertEquals(t, "12345", record.ResourceID)
+	th.AssertEquals(t, "read", record.Operation)
+	th.AssertEquals(t, `{"password":"***"}`, string(record.RequestBody))
+	th.AssertEquals(t, `{"user":{"name":"test","password":"***"}}`, string(record.ResponseBody))
+}
+
+func TestAPITraceInOtherGoroutine(t *testing.T) {
+	traceFile := filepath.Join(t.TempDir(), "trace.log")
+	t.Setenv(apiTraceFileEnv, traceFile)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &Config{
+		HwClient: &golangsdk.ProviderClient{
+			HTTPClient: *newLogRoundTripperClient(0),
+		},
+	}
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		Delete: func(_ *schema.ResourceData, meta interface{}) error {
+			// the requests are sent in another goroutine, e.g. the refresh function of StateChangeConf
+			errCh := make(chan error)
+			go func() {
+				_, err := meta.(*Config).HwClient.Request("DELETE", server.URL+"/v1/vpcs/12345",
+					&golangsdk.RequestOpts{OkCodes: []int{http.StatusOK}})
+				errCh <- err
+			}()
+			return <-errCh
+		},
+	}
+	EnableAPITrace("huaweicloud_vpc", r)
+
+	d := r.TestResourceData()
+	d.SetId("12345")
+	th.AssertNoErr(t, r.Delete(d, cfg))
+	// the clients of the provider Config are not changed
+	th.AssertEquals(t, true, cfg.HwClient.Context == nil)
+
+	// the requests sent by the provider Config are not associated with any resource
+	_, err := cfg.HwClient.Request("GET", server.URL+"/v1/vpcs", &golangsdk.RequestOpts{OkCodes: []int{http.StatusOK}})
+	th.AssertNoErr(t, err)
+
+	content, err := os.ReadFile(traceFile)
+	th.AssertNoErr(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	th.AssertEquals(t, 2, len(lines))
+
+	var record apiTraceRecord
+	th.AssertNoErr(t, json.Unmarshal([]byte(lines[0]), &record))
+	th.AssertEquals(t, "DELETE", record.Method)
+	th.AssertEquals(t, "huaweicloud_vpc", record.Resource)
+	th.AssertEquals(t, "12345", record.ResourceID)
+	th.AssertEquals(t, "delete", record.Operation)
+
+	record = apiTraceRecord{}
+	th.AssertNoErr(t, json.Unmarshal([]byte(lines[1]), &record))
+	th.AssertEquals(t, "GET", record.Method)
+	th.AssertEquals(t, "", record.Resource)
+}
+
+func TestAPITraceDisabled(t *testing.T) {
+	t.Setenv(apiTraceFileEnv, "")
+
+	cfg := &Config{HwClient: &golangsdk.ProviderClient{}}
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{},
+		Read: func(_ *schema.ResourceData, meta interface{}) error {
+			// the Config of the provider is not copied for the operation if the requests are not traced
+			th.AssertEquals(t, cfg, meta.(*Config))
+			return nil
+		},
+	}
+	EnableAPITrace("huaweicloud_vpc", r)
+
+	th.AssertNoErr(t, r.Read(r.TestResourceData(), cfg))
+}
+
+func TestWithClientContext(t *testing.T) {
+	client := &golangsdk.ProviderClient{TokenID: "old-token"}
+	client.UseTokenLock()
+	var reauthCount int
+	client.ReauthFunc = func() error {
+		reauthCount++
+		client.TokenID = "new-token"
+		return nil
+	}
+
+	ctx := contextWithResourceOperation(context.Background(), &resourceOperation{resourceType: "huaweicloud_vpc"})
+	opClient := withClientContext(client, ctx)
+	th.AssertEquals(t, "huaweicloud_vpc", resourceOperationFromContext(opClient.Context).resourceType)
+	th.AssertEquals(t, "old-token", opClient.Token())
+
+	// the token is reauthenticated by the original client, and is not reauthenticated again by the other copies
+	th.AssertNoErr(t, opClient.Reauthenticate("old-token"))
+	th.AssertEquals(t, "new-token", opClient.Token())
+	th.AssertEquals(t, "new-token", client.Token())
+	th.AssertNoErr(t, withClientContext(client, ctx).Reauthenticate("old-token"))
+	th.AssertEquals(t, 1, reauthCount)
+}
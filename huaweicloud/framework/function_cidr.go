@@ -0,0 +1,166 @@
+package framework
+
+import (
+	"context"
+	"math/big"
+	"net"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+var (
+	_ function.Function = &ValidateCIDRFunction{}
+	_ function.Function = &ExpandCIDRFunction{}
+)
+
+// cidrAttributeTypes are the attributes of the object returned by the expand_cidr function.
+var cidrAttributeTypes = map[string]attr.Type{
+	"network_address": types.StringType,
+	"netmask":         types.StringType,
+	"prefix_length":   types.Int64Type,
+	"gateway_ip":      types.StringType,
+	"last_address":    types.StringType,
+	"address_count":   types.NumberType,
+}
+
+type cidrModel struct {
+	NetworkAddress string       `tfsdk:"network_address"`
+	Netmask        string       `tfsdk:"netmask"`
+	PrefixLength   int64        `tfsdk:"prefix_length"`
+	GatewayIP      string       `tfsdk:"gateway_ip"`
+	LastAddress    string       `tfsdk:"last_address"`
+	AddressCount   types.Number `tfsdk:"address_count"`
+}
+
+// ValidateCIDRFunction checks whether a string is a valid network CIDR, in which the host bits are all zero, it's the
+// same as the validation of the CIDR arguments of the resources, such as the cidr of huaweicloud_vpc.
+type ValidateCIDRFunction struct{}
+
+func NewValidateCIDRFunction() function.Function {
+	return &ValidateCIDRFunction{}
+}
+
+func (f *ValidateCIDRFunction) Metadata(_ context.Context, _ function.MetadataRequest,
+	resp *function.MetadataResponse) {
+	resp.Name = "validate_cidr"
+}
+
+func (f *ValidateCIDRFunction) Definition(_ context.Context, _ function.DefinitionRequest,
+	resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Check whether a string is a valid network CIDR",
+		Description: "Returns true if the string is a valid IPv4 or IPv6 network CIDR, in which the host bits " +
+			"are all zero, e.g. 192.168.0.0/16.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "cidr",
+				Description: "The CIDR to validate.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *ValidateCIDRFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var cidr string
+	resp.Error = req.Arguments.Get(ctx, &cidr)
+	if resp.Error != nil {
+		return
+	}
+
+	_, errs := utils.ValidateCIDR(cidr, "cidr")
+	resp.Error = resp.Result.Set(ctx, len(errs) == 0)
+}
+
+// ExpandCIDRFunction returns the details of a network CIDR, such as the netmask, the gateway and the address range.
+type ExpandCIDRFunction struct{}
+
+func NewExpandCIDRFunction() function.Function {
+	return &ExpandCIDRFunction{}
+}
+
+func (f *ExpandCIDRFunction) Metadata(_ context.Context, _ function.MetadataRequest,
+	resp *function.MetadataResponse) {
+	resp.Name = "expand_cidr"
+}
+
+func (f *ExpandCIDRFunction) Definition(_ context.Context, _ function.DefinitionRequest,
+	resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Expand a network CIDR into its details",
+		Description: "Returns the network address, netmask, prefix length, last address and address count of " +
+			"a network CIDR. The gateway_ip is the first address after the network address, which is the default " +
+			"gateway of the subnets.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "cidr",
+				Description: "The network CIDR to expand, e.g. 192.168.0.0/24.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: cidrAttributeTypes,
+		},
+	}
+}
+
+func (f *ExpandCIDRFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var cidr string
+	resp.Error = req.Arguments.Get(ctx, &cidr)
+	if resp.Error != nil {
+		return
+	}
+
+	if _, errs := utils.ValidateCIDR(cidr, "cidr"); len(errs) > 0 {
+		resp.Error = function.NewArgumentFuncError(0, errs[0].Error())
+		return
+	}
+
+	model, err := expandCIDR(cidr)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, model)
+}
+
+func expandCIDR(cidr string) (*cidrModel, error) {
+	_, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	network := ipnet.IP
+	if v4 := network.To4(); v4 != nil {
+		network = v4
+	}
+	ones, bits := ipnet.Mask.Size()
+
+	first := new(big.Int).SetBytes(network)
+	count := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+	last := new(big.Int).Add(first, new(big.Int).Sub(count, big.NewInt(1)))
+
+	gateway := ""
+	if count.Cmp(big.NewInt(1)) > 0 {
+		gateway = intToIP(new(big.Int).Add(first, big.NewInt(1)), len(network)).String()
+	}
+
+	return &cidrModel{
+		NetworkAddress: network.String(),
+		Netmask:        net.IP(ipnet.Mask).String(),
+		PrefixLength:   int64(ones),
+		GatewayIP:      gateway,
+		LastAddress:    intToIP(last, len(network)).String(),
+		AddressCount:   types.NumberValue(new(big.Float).SetInt(count)),
+	}, nil
+}
+
+// intToIP converts an integer to an IP address with the length in bytes.
+func intToIP(i *big.Int, length int) net.IP {
+	ip := make(net.IP, length)
+	i.FillBytes(ip)
+	return ip
+}
@@ -0,0 +1,179 @@
+package framework
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+// defaultCloud is the default value of the cloud argument of the functions, which is the same as the default value
+// of the provider-level cloud.
+const defaultCloud = "myhuaweicloud.com"
+
+var (
+	_ function.Function = &ServiceEndpointFunction{}
+	_ function.Function = &ProjectEndpointFunction{}
+)
+
+// cloudParameter is the optional cloud argument of the functions, the provider config is not available when the
+// functions are called, so it should be specified if the provider-level cloud is not the default one.
+var cloudParameter = function.StringParameter{
+	Name:        "cloud",
+	Description: "The endpoint suffix of the cloud, defaults to myhuaweicloud.com. Only one value is allowed.",
+}
+
+// cloudArgument returns the cloud specified by the variadic argument at the position, or the default cloud.
+func cloudArgument(clouds []string, position int64) (string, *function.FuncError) {
+	switch len(clouds) {
+	case 0:
+		return defaultCloud, nil
+	case 1:
+		return clouds[0], nil
+	default:
+		return "", function.NewArgumentFuncError(position, "only one cloud can be specified")
+	}
+}
+
+// buildServiceEndpoint builds the endpoint and the resource base of a service. The config configured by the provider
+// is used if the cloud is not specified or is the provider-level one, so that the customizing and the discovered
+// endpoints are returned as the provider uses them, otherwise they're built from the build-in catalog.
+func buildServiceEndpoint(meta func() *config.Config, service, region, projectID string,
+	clouds []string) (string, string, error) {
+	if meta != nil {
+		if cfg := meta(); cfg != nil && (len(clouds) == 0 || clouds[0] == cfg.Cloud) {
+			return cfg.BuildServiceEndpoint(service, region, projectID)
+		}
+	}
+
+	cloud := defaultCloud
+	if len(clouds) > 0 {
+		cloud = clouds[0]
+	}
+	return config.BuildServiceEndpoint(service, region, cloud, projectID)
+}
+
+// ServiceEndpointFunction builds the endpoint of a service in the region, e.g.
+// https://vpc.cn-north-4.myhuaweicloud.com/.
+// The meta returns the config configured by the provider, or nil if the provider has not been configured.
+type ServiceEndpointFunction struct {
+	meta func() *config.Config
+}
+
+func NewServiceEndpointFunction() function.Function {
+	return &ServiceEndpointFunction{}
+}
+
+func (f *ServiceEndpointFunction) Metadata(_ context.Context, _ function.MetadataRequest,
+	resp *function.MetadataResponse) {
+	resp.Name = "service_endpoint"
+}
+
+func (f *ServiceEndpointFunction) Definition(_ context.Context, _ function.DefinitionRequest,
+	resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build the endpoint of a service in a region",
+		Description: "Builds the endpoint of a service in a region from the build-in service catalog of the " +
+			"provider, the region is ignored for the global services.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "service",
+				Description: "The service key of the provider endpoints, e.g. vpc.",
+			},
+			function.StringParameter{
+				Name:        "region",
+				Description: "The region of the endpoint, e.g. cn-north-4.",
+			},
+		},
+		VariadicParameter: cloudParameter,
+		Return:            function.StringReturn{},
+	}
+}
+
+func (f *ServiceEndpointFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var (
+		service, region string
+		clouds          []string
+	)
+	resp.Error = req.Arguments.Get(ctx, &service, &region, &clouds)
+	if resp.Error != nil {
+		return
+	}
+
+	if _, funcErr := cloudArgument(clouds, 2); funcErr != nil {
+		resp.Error = funcErr
+		return
+	}
+
+	// the project ID is not required to build the endpoint, so a placeholder is used for the project-level services
+	endpoint, _, err := buildServiceEndpoint(f.meta, service, region, "{project_id}", clouds)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, endpoint)
+}
+
+// ProjectEndpointFunction builds the base URL of the APIs of a service in the region, which contains the API version
+// and the project ID, e.g. https://vpc.cn-north-4.myhuaweicloud.com/v1/{project_id}/.
+type ProjectEndpointFunction struct {
+	meta func() *config.Config
+}
+
+func NewProjectEndpointFunction() function.Function {
+	return &ProjectEndpointFunction{}
+}
+
+func (f *ProjectEndpointFunction) Metadata(_ context.Context, _ function.MetadataRequest,
+	resp *function.MetadataResponse) {
+	resp.Name = "project_endpoint"
+}
+
+func (f *ProjectEndpointFunction) Definition(_ context.Context, _ function.DefinitionRequest,
+	resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build the base URL of the APIs of a service in a project",
+		Description: "Builds the base URL of the APIs of a service from the build-in service catalog of the " +
+			"provider, which contains the API version, and the project ID for the project-level services.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "service",
+				Description: "The service key of the provider endpoints, e.g. vpc.",
+			},
+			function.StringParameter{
+				Name:        "region",
+				Description: "The region of the endpoint, e.g. cn-north-4.",
+			},
+			function.StringParameter{
+				Name:        "project_id",
+				Description: "The ID of the project in the region, it's ignored for the services without project ID.",
+			},
+		},
+		VariadicParameter: cloudParameter,
+		Return:            function.StringReturn{},
+	}
+}
+
+func (f *ProjectEndpointFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var (
+		service, region, projectID string
+		clouds                     []string
+	)
+	resp.Error = req.Arguments.Get(ctx, &service, &region, &projectID, &clouds)
+	if resp.Error != nil {
+		return
+	}
+
+	if _, funcErr := cloudArgument(clouds, 3); funcErr != nil {
+		resp.Error = funcErr
+		return
+	}
+
+	_, resourceBase, err := buildServiceEndpoint(f.meta, service, region, projectID, clouds)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, resourceBase)
+}
@@ -0,0 +1,70 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &OBSBucketDomainFunction{}
+
+// OBSBucketDomainFunction computes the domain name of an OBS bucket, which is the same as the bucket_domain_name of
+// huaweicloud_obs_bucket, e.g. my-bucket.obs.cn-north-4.myhuaweicloud.com.
+type OBSBucketDomainFunction struct{}
+
+func NewOBSBucketDomainFunction() function.Function {
+	return &OBSBucketDomainFunction{}
+}
+
+func (f *OBSBucketDomainFunction) Metadata(_ context.Context, _ function.MetadataRequest,
+	resp *function.MetadataResponse) {
+	resp.Name = "obs_bucket_domain"
+}
+
+func (f *OBSBucketDomainFunction) Definition(_ context.Context, _ function.DefinitionRequest,
+	resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Compute the domain name of an OBS bucket",
+		Description: "Returns the domain name of an OBS bucket in a region, in the format of bucket.obs.region.cloud.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "bucket",
+				Description: "The name of the bucket.",
+			},
+			function.StringParameter{
+				Name:        "region",
+				Description: "The region of the bucket, e.g. cn-north-4.",
+			},
+		},
+		VariadicParameter: cloudParameter,
+		Return:            function.StringReturn{},
+	}
+}
+
+func (f *OBSBucketDomainFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var (
+		bucket, region string
+		clouds         []string
+	)
+	resp.Error = req.Arguments.Get(ctx, &bucket, &region, &clouds)
+	if resp.Error != nil {
+		return
+	}
+
+	if bucket == "" {
+		resp.Error = function.NewArgumentFuncError(0, "the bucket name can not be empty")
+		return
+	}
+	if region == "" {
+		resp.Error = function.NewArgumentFuncError(1, "the region can not be empty")
+		return
+	}
+	cloud, funcErr := cloudArgument(clouds, 2)
+	if funcErr != nil {
+		resp.Error = funcErr
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, fmt.Sprintf("%s.obs.%s.%s", bucket, region, cloud))
+}
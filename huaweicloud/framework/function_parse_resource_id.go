@@ -0,0 +1,77 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &ParseResourceIDFunction{}
+
+// ParseResourceIDFunction splits the ID of a resource which is composed of several IDs, such as the import ID in the
+// format of {instance_id}/{id}, into a list.
+type ParseResourceIDFunction struct{}
+
+func NewParseResourceIDFunction() function.Function {
+	return &ParseResourceIDFunction{}
+}
+
+func (f *ParseResourceIDFunction) Metadata(_ context.Context, _ function.MetadataRequest,
+	resp *function.MetadataResponse) {
+	resp.Name = "parse_resource_id"
+}
+
+func (f *ParseResourceIDFunction) Definition(_ context.Context, _ function.DefinitionRequest,
+	resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parse a composite resource ID into its parts",
+		Description: "Splits a resource ID composed of several IDs separated by slashes, such as the import ID " +
+			"{instance_id}/{id}, into a list. The number of the parts is checked if parts_count is specified.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "id",
+				Description: "The composite ID of the resource.",
+			},
+		},
+		VariadicParameter: function.Int64Parameter{
+			Name:        "parts_count",
+			Description: "The expected number of the parts, only one value is allowed.",
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *ParseResourceIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var (
+		id     string
+		counts []int64
+	)
+	resp.Error = req.Arguments.Get(ctx, &id, &counts)
+	if resp.Error != nil {
+		return
+	}
+	if len(counts) > 1 {
+		resp.Error = function.NewArgumentFuncError(1, "only one parts_count can be specified")
+		return
+	}
+
+	parts := strings.Split(id, "/")
+	for _, part := range parts {
+		if part == "" {
+			resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("the ID (%s) contains an empty part", id))
+			return
+		}
+	}
+	if len(counts) == 1 && int64(len(parts)) != counts[0] {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("the ID (%s) is expected to have %d parts, "+
+			"but got %d", id, counts[0], len(parts)))
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, parts)
+}
@@ -0,0 +1,81 @@
+package framework
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &ParseURNFunction{}
+
+// urnAttributeTypes are the attributes of the object returned by the parse_urn function.
+var urnAttributeTypes = map[string]attr.Type{
+	"service":       types.StringType,
+	"region":        types.StringType,
+	"account_id":    types.StringType,
+	"resource_type": types.StringType,
+	"resource_path": types.StringType,
+}
+
+type urnModel struct {
+	Service      string `tfsdk:"service"`
+	Region       string `tfsdk:"region"`
+	AccountID    string `tfsdk:"account_id"`
+	ResourceType string `tfsdk:"resource_type"`
+	ResourcePath string `tfsdk:"resource_path"`
+}
+
+// ParseURNFunction parses the URN of a resource, which is in the format of
+// {service}:{region}:{account_id}:{resource_type}:{resource_path}, e.g. obs:*:*:object:my-bucket/my-object.
+type ParseURNFunction struct{}
+
+func NewParseURNFunction() function.Function {
+	return &ParseURNFunction{}
+}
+
+func (f *ParseURNFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_urn"
+}
+
+func (f *ParseURNFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parse a resource URN into its parts",
+		Description: "Parses a resource URN in the format of service:region:account_id:resource_type:resource_path " +
+			"into an object. The resource path may contain colons.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "urn",
+				Description: "The URN of the resource, e.g. obs:*:*:object:my-bucket/my-object.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: urnAttributeTypes,
+		},
+	}
+}
+
+func (f *ParseURNFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var urn string
+	resp.Error = req.Arguments.Get(ctx, &urn)
+	if resp.Error != nil {
+		return
+	}
+
+	parts := strings.SplitN(urn, ":", 5)
+	if len(parts) != 5 || parts[0] == "" || parts[3] == "" || parts[4] == "" {
+		resp.Error = function.NewArgumentFuncError(0, "the URN must be in the format of "+
+			"service:region:account_id:resource_type:resource_path, got "+urn)
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, urnModel{
+		Service:      parts[0],
+		Region:       parts[1],
+		AccountID:    parts[2],
+		ResourceType: parts[3],
+		ResourcePath: parts[4],
+	})
+}
@@ -0,0 +1,288 @@
+package framework
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+// runFunction runs the function with the arguments, the variadic arguments are passed as a tuple in the last one.
+func runFunction(t *testing.T, f function.Function, args ...attr.Value) (attr.Value, *function.FuncError) {
+	ctx := context.Background()
+	var definition function.DefinitionResponse
+	f.Definition(ctx, function.DefinitionRequest{}, &definition)
+
+	result, funcErr := definition.Definition.Return.NewResultData(ctx)
+	if funcErr != nil {
+		t.Fatalf("failed to create the result data: %s", funcErr)
+	}
+
+	resp := function.RunResponse{Result: result}
+	f.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData(args)}, &resp)
+	return resp.Result.Value(), resp.Error
+}
+
+func variadicStrings(values ...string) attr.Value {
+	elemTypes := make([]attr.Type, len(values))
+	elems := make([]attr.Value, len(values))
+	for i, v := range values {
+		elemTypes[i] = types.StringType
+		elems[i] = types.StringValue(v)
+	}
+	return types.TupleValueMust(elemTypes, elems)
+}
+
+func variadicInt64s(values ...int64) attr.Value {
+	elemTypes := make([]attr.Type, len(values))
+	elems := make([]attr.Value, len(values))
+	for i, v := range values {
+		elemTypes[i] = types.Int64Type
+		elems[i] = types.Int64Value(v)
+	}
+	return types.TupleValueMust(elemTypes, elems)
+}
+
+func objectAttributes(t *testing.T, v attr.Value) map[string]attr.Value {
+	object, ok := v.(types.Object)
+	if !ok {
+		t.Fatalf("expect an object, but got %T", v)
+	}
+	return object.Attributes()
+}
+
+func TestParseURNFunction(t *testing.T) {
+	result, funcErr := runFunction(t, NewParseURNFunction(), types.StringValue("obs:*:*:object:my-bucket/dir:a"))
+	if funcErr != nil {
+		t.Fatalf("unexpected error: %s", funcErr)
+	}
+
+	expected := map[string]string{
+		"service":       "obs",
+		"region":        "*",
+		"account_id":    "*",
+		"resource_type": "object",
+		"resource_path": "my-bucket/dir:a",
+	}
+	attrs := objectAttributes(t, result)
+	for k, v := range expected {
+		if !attrs[k].Equal(types.StringValue(v)) {
+			t.Errorf("expect %s to be %q, but got %s", k, v, attrs[k])
+		}
+	}
+
+	for _, urn := range []string{"", "obs:*:*:object", ":cn-north-4:*:vpc:id", "vpc:cn-north-4:*::id"} {
+		if _, funcErr := runFunction(t, NewParseURNFunction(), types.StringValue(urn)); funcErr == nil {
+			t.Errorf("expect an error for the URN %q", urn)
+		}
+	}
+}
+
+func TestParseResourceIDFunction(t *testing.T) {
+	result, funcErr := runFunction(t, NewParseResourceIDFunction(), types.StringValue("instance-id/db-id"),
+		variadicInt64s())
+	if funcErr != nil {
+		t.Fatalf("unexpected error: %s", funcErr)
+	}
+	expected := types.ListValueMust(types.StringType,
+		[]attr.Value{types.StringValue("instance-id"), types.StringValue("db-id")})
+	if !result.Equal(expected) {
+		t.Errorf("expect %s, but got %s", expected, result)
+	}
+
+	if _, funcErr := runFunction(t, NewParseResourceIDFunction(), types.StringValue("instance-id/db-id"),
+		variadicInt64s(2)); funcErr != nil {
+		t.Errorf("unexpected error: %s", funcErr)
+	}
+
+	errorCases := []attr.Value{
+		variadicInt64s(3),
+		variadicInt64s(2, 2),
+	}
+	for _, counts := range errorCases {
+		if _, funcErr := runFunction(t, NewParseResourceIDFunction(), types.StringValue("instance-id/db-id"),
+			counts); funcErr == nil {
+			t.Errorf("expect an error with the parts count %s", counts)
+		}
+	}
+	if _, funcErr := runFunction(t, NewParseResourceIDFunction(), types.StringValue("instance-id//db-id"),
+		variadicInt64s()); funcErr == nil {
+		t.Error("expect an error for the ID with an empty part")
+	}
+}
+
+func TestServiceEndpointFunction(t *testing.T) {
+	cases := []struct {
+		service, region string
+		clouds          []string
+		expected        string
+	}{
+		{"vpc", "cn-north-4", nil, "https://vpc.cn-north-4.myhuaweicloud.com/"},
+		{"vpc", "eu-west-101", []string{"myhuaweicloud.eu"}, "https://vpc.eu-west-101.myhuaweicloud.eu/"},
+		{"mkt", "cn-north-4", nil, "https://mkt.myhuaweicloud.com/"},
+	}
+	for _, c := range cases {
+		result, funcErr := runFunction(t, NewServiceEndpointFunction(), types.StringValue(c.service),
+			types.StringValue(c.region), variadicStrings(c.clouds...))
+		if funcErr != nil {
+			t.Fatalf("unexpected error: %s", funcErr)
+		}
+		if !result.Equal(types.StringValue(c.expected)) {
+			t.Errorf("expect the endpoint of %s to be %s, but got %s", c.service, c.expected, result)
+		}
+	}
+
+	if _, funcErr := runFunction(t, NewServiceEndpointFunction(), types.StringValue("unknown"),
+		types.StringValue("cn-north-4"), variadicStrings()); funcErr == nil {
+		t.Error("expect an error for the unknown service")
+	}
+	if _, funcErr := runFunction(t, NewServiceEndpointFunction(), types.StringValue("vpc"),
+		types.StringValue("cn-north-4"), variadicStrings("a.com", "b.com")); funcErr == nil {
+		t.Error("expect an error for more than one cloud")
+	}
+}
+
+func TestEndpointFunctionsWithConfig(t *testing.T) {
+	cfg := &config.Config{
+		Region: "cn-north-4",
+		Cloud:  "myhuaweicloud.com",
+		Endpoints: map[string]string{
+			"vpc": "https://vpc.custom.com/",
+		},
+	}
+	meta := func() *config.Config {
+		return cfg
+	}
+
+	result, funcErr := runFunction(t, &ServiceEndpointFunction{meta: meta}, types.StringValue("vpc"),
+		types.StringValue("cn-north-4"), variadicStrings())
+	if funcErr != nil {
+		t.Fatalf("unexpected error: %s", funcErr)
+	}
+	if !result.Equal(types.StringValue("https://vpc.custom.com/")) {
+		t.Errorf("expect the customizing endpoint, but got %s", result)
+	}
+
+	result, funcErr = runFunction(t, &ProjectEndpointFunction{meta: meta}, types.StringValue("vpc"),
+		types.StringValue("cn-north-4"), types.StringValue("0123456789abcdef"), variadicStrings("myhuaweicloud.com"))
+	if funcErr != nil {
+		t.Fatalf("unexpected error: %s", funcErr)
+	}
+	if !result.Equal(types.StringValue("https://vpc.custom.com/v1/")) {
+		t.Errorf("expect the customizing resource base, but got %s", result)
+	}
+
+	// the config is not used for another cloud
+	result, funcErr = runFunction(t, &ServiceEndpointFunction{meta: meta}, types.StringValue("vpc"),
+		types.StringValue("eu-west-101"), variadicStrings("myhuaweicloud.eu"))
+	if funcErr != nil {
+		t.Fatalf("unexpected error: %s", funcErr)
+	}
+	if !result.Equal(types.StringValue("https://vpc.eu-west-101.myhuaweicloud.eu/")) {
+		t.Errorf("expect the endpoint from the build-in catalog, but got %s", result)
+	}
+}
+
+func TestProjectEndpointFunction(t *testing.T) {
+	cases := map[string]string{
+		"ecs": "https://ecs.cn-north-4.myhuaweicloud.com/v1/0123456789abcdef/",
+		// the project ID is ignored for the services without project ID
+		"vpc": "https://vpc.cn-north-4.myhuaweicloud.com/v1/",
+	}
+	for service, expected := range cases {
+		result, funcErr := runFunction(t, NewProjectEndpointFunction(), types.StringValue(service),
+			types.StringValue("cn-north-4"), types.StringValue("0123456789abcdef"), variadicStrings())
+		if funcErr != nil {
+			t.Fatalf("unexpected error: %s", funcErr)
+		}
+		if !result.Equal(types.StringValue(expected)) {
+			t.Errorf("expect the endpoint of %s to be %s, but got %s", service, expected, result)
+		}
+	}
+
+	if _, funcErr := runFunction(t, NewProjectEndpointFunction(), types.StringValue("ecs"),
+		types.StringValue("cn-north-4"), types.StringValue(""), variadicStrings()); funcErr == nil {
+		t.Error("expect an error without the project ID")
+	}
+}
+
+func TestValidateCIDRFunction(t *testing.T) {
+	cases := map[string]bool{
+		"192.168.0.0/16":  true,
+		"192.168.1.0/16":  false,
+		"2001:db8::/32":   true,
+		"192.168.0.0":     false,
+		"192.168.0.0/33":  false,
+		"not-a-cidr/24":   false,
+		"10.0.0.0/8":      true,
+		"172.16.0.255/32": true,
+	}
+	for cidr, expected := range cases {
+		result, funcErr := runFunction(t, NewValidateCIDRFunction(), types.StringValue(cidr))
+		if funcErr != nil {
+			t.Fatalf("unexpected error: %s", funcErr)
+		}
+		if !result.Equal(types.BoolValue(expected)) {
+			t.Errorf("expect the validation result of %s to be %t, but got %s", cidr, expected, result)
+		}
+	}
+}
+
+func TestExpandCIDRFunction(t *testing.T) {
+	result, funcErr := runFunction(t, NewExpandCIDRFunction(), types.StringValue("192.168.0.0/24"))
+	if funcErr != nil {
+		t.Fatalf("unexpected error: %s", funcErr)
+	}
+
+	attrs := objectAttributes(t, result)
+	expected := map[string]attr.Value{
+		"network_address": types.StringValue("192.168.0.0"),
+		"netmask":         types.StringValue("255.255.255.0"),
+		"prefix_length":   types.Int64Value(24),
+		"gateway_ip":      types.StringValue("192.168.0.1"),
+		"last_address":    types.StringValue("192.168.0.255"),
+		"address_count":   types.NumberValue(big.NewFloat(256)),
+	}
+	for k, v := range expected {
+		if !attrs[k].Equal(v) {
+			t.Errorf("expect %s to be %s, but got %s", k, v, attrs[k])
+		}
+	}
+
+	result, funcErr = runFunction(t, NewExpandCIDRFunction(), types.StringValue("2001:db8::/64"))
+	if funcErr != nil {
+		t.Fatalf("unexpected error: %s", funcErr)
+	}
+	attrs = objectAttributes(t, result)
+	if !attrs["last_address"].Equal(types.StringValue("2001:db8::ffff:ffff:ffff:ffff")) {
+		t.Errorf("unexpected last address of the IPv6 CIDR: %s", attrs["last_address"])
+	}
+
+	_, funcErr = runFunction(t, NewExpandCIDRFunction(), types.StringValue("192.168.0.1/24"))
+	if funcErr == nil || !strings.Contains(funcErr.Error(), "valid network CIDR") {
+		t.Errorf("expect an error for the CIDR with host bits, but got %v", funcErr)
+	}
+}
+
+func TestOBSBucketDomainFunction(t *testing.T) {
+	result, funcErr := runFunction(t, NewOBSBucketDomainFunction(), types.StringValue("my-bucket"),
+		types.StringValue("cn-north-4"), variadicStrings())
+	if funcErr != nil {
+		t.Fatalf("unexpected error: %s", funcErr)
+	}
+	expected := types.StringValue("my-bucket.obs.cn-north-4.myhuaweicloud.com")
+	if !result.Equal(expected) {
+		t.Errorf("expect %s, but got %s", expected, result)
+	}
+
+	if _, funcErr := runFunction(t, NewOBSBucketDomainFunction(), types.StringValue(""),
+		types.StringValue("cn-north-4"), variadicStrings()); funcErr == nil {
+		t.Error("expect an error for the empty bucket name")
+	}
+}
@@ -0,0 +1,115 @@
+package framework
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/dew"
+)
+
+// ProviderTypeName is the type name of the provider, it's the prefix of the resources and the namespace of the
+// provider-defined functions.
+const ProviderTypeName = "huaweicloud"
+
+var (
+	_ provider.ProviderWithFunctions          = &Provider{}
+	_ provider.ProviderWithEphemeralResources = &Provider{}
+)
+
+// Provider is the plugin-framework provider which is muxed with the SDKv2 provider. The schema of the provider is
+// converted from the SDKv2 one, and the config.Config configured by the SDKv2 provider is shared with the resources,
+// data sources and ephemeral resources of this provider.
+type Provider struct {
+	sdkProvider *schema.Provider
+
+	schemaOnce sync.Once
+	schema     providerschema.Schema
+	schemaErr  error
+}
+
+// New returns a function which creates the plugin-framework provider, the sdkProvider must be served before this
+// provider in the mux server, so that it has been configured when this provider is configured.
+func New(sdkProvider *schema.Provider) func() provider.Provider {
+	p := &Provider{sdkProvider: sdkProvider}
+	return func() provider.Provider {
+		return p
+	}
+}
+
+func (p *Provider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = ProviderTypeName
+}
+
+func (p *Provider) Schema(ctx context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	// the schemas of the muxed providers must be the same
+	p.schemaOnce.Do(func() {
+		p.schema, p.schemaErr = convertProviderSchema(ctx, p.sdkProvider)
+	})
+	if p.schemaErr != nil {
+		resp.Diagnostics.AddError("Unable to convert the provider schema", p.schemaErr.Error())
+		return
+	}
+	resp.Schema = p.schema
+}
+
+// meta returns the config.Config configured by the SDKv2 provider, or nil if it has not been configured, e.g. the
+// functions are called before the provider is configured.
+func (p *Provider) meta() *config.Config {
+	cfg, _ := p.sdkProvider.Meta().(*config.Config)
+	return cfg
+}
+
+func (p *Provider) Configure(_ context.Context, _ provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	cfg := p.meta()
+	if cfg == nil {
+		resp.Diagnostics.AddError("Unable to configure the provider",
+			"The SDKv2 provider has not been configured, it must be served before the plugin-framework provider.")
+		return
+	}
+
+	resp.DataSourceData = cfg
+	resp.ResourceData = cfg
+	resp.EphemeralResourceData = cfg
+}
+
+func (p *Provider) Resources(_ context.Context) []func() resource.Resource {
+	return nil
+}
+
+func (p *Provider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+// EphemeralResources returns the ephemeral resources, which are only served by the plugin-framework provider because
+// the SDKv2 does not support them. The values of the ephemeral resources are never persisted to the plan or state.
+func (p *Provider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		dew.NewCsmsSecretVersionEphemeralResource,
+		dew.NewKmsDataKeyEphemeralResource,
+	}
+}
+
+func (p *Provider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewParseURNFunction,
+		NewParseResourceIDFunction,
+		func() function.Function {
+			return &ServiceEndpointFunction{meta: p.meta}
+		},
+		func() function.Function {
+			return &ProjectEndpointFunction{meta: p.meta}
+		},
+		NewValidateCIDRFunction,
+		NewExpandCIDRFunction,
+		NewOBSBucketDomainFunction,
+	}
+}
@@ -0,0 +1,255 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// deprecationMessage is used for the deprecated attributes and blocks, the message of the SDKv2 schema is not sent
+// to terraform, and only whether it's deprecated is compared by the mux server.
+const deprecationMessage = "Deprecated"
+
+// convertProviderSchema converts the schema of the SDKv2 provider to the plugin-framework one. The conversion is
+// based on the schema which is sent to terraform by the SDKv2 provider, so the two schemas are always the same.
+func convertProviderSchema(ctx context.Context, sdkProvider *schema.Provider) (providerschema.Schema, error) {
+	// only the provider schema is required, so the resources and data sources are not converted
+	server := schema.NewGRPCProviderServer(&schema.Provider{Schema: sdkProvider.Schema})
+	resp, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		return providerschema.Schema{}, err
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			return providerschema.Schema{}, fmt.Errorf("%s: %s", d.Summary, d.Detail)
+		}
+	}
+	if resp.Provider == nil || resp.Provider.Block == nil {
+		return providerschema.Schema{}, nil
+	}
+
+	block := resp.Provider.Block
+	attributes, blocks, err := convertBlock(block)
+	if err != nil {
+		return providerschema.Schema{}, err
+	}
+
+	result := providerschema.Schema{
+		Attributes: attributes,
+		Blocks:     blocks,
+	}
+	result.Description, result.MarkdownDescription = description(block.Description, block.DescriptionKind)
+	if block.Deprecated {
+		result.DeprecationMessage = deprecationMessage
+	}
+	return result, nil
+}
+
+func convertBlock(block *tfprotov5.SchemaBlock) (map[string]providerschema.Attribute,
+	map[string]providerschema.Block, error) {
+	attributes := make(map[string]providerschema.Attribute, len(block.Attributes))
+	for _, a := range block.Attributes {
+		attribute, err := convertAttribute(a)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error converting the attribute %s: %s", a.Name, err)
+		}
+		attributes[a.Name] = attribute
+	}
+
+	blocks := make(map[string]providerschema.Block, len(block.BlockTypes))
+	for _, b := range block.BlockTypes {
+		nested, err := convertNestedBlock(b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error converting the block %s: %s", b.TypeName, err)
+		}
+		blocks[b.TypeName] = nested
+	}
+	return attributes, blocks, nil
+}
+
+func convertNestedBlock(b *tfprotov5.SchemaNestedBlock) (providerschema.Block, error) {
+	attributes, blocks, err := convertBlock(b.Block)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, markdownDesc := description(b.Block.Description, b.Block.DescriptionKind)
+	var deprecation string
+	if b.Block.Deprecated {
+		deprecation = deprecationMessage
+	}
+
+	object := providerschema.NestedBlockObject{
+		Attributes: attributes,
+		Blocks:     blocks,
+	}
+	switch b.Nesting {
+	case tfprotov5.SchemaNestedBlockNestingModeList:
+		return providerschema.ListNestedBlock{
+			NestedObject:        object,
+			Description:         desc,
+			MarkdownDescription: markdownDesc,
+			DeprecationMessage:  deprecation,
+		}, nil
+	case tfprotov5.SchemaNestedBlockNestingModeSet:
+		return providerschema.SetNestedBlock{
+			NestedObject:        object,
+			Description:         desc,
+			MarkdownDescription: markdownDesc,
+			DeprecationMessage:  deprecation,
+		}, nil
+	case tfprotov5.SchemaNestedBlockNestingModeSingle:
+		return providerschema.SingleNestedBlock{
+			Attributes:          attributes,
+			Blocks:              blocks,
+			Description:         desc,
+			MarkdownDescription: markdownDesc,
+			DeprecationMessage:  deprecation,
+		}, nil
+	default:
+		return nil, fmt.Errorf("the nesting mode %s is not supported", b.Nesting)
+	}
+}
+
+func convertAttribute(a *tfprotov5.SchemaAttribute) (providerschema.Attribute, error) {
+	desc, markdownDesc := description(a.Description, a.DescriptionKind)
+	var deprecation string
+	if a.Deprecated {
+		deprecation = deprecationMessage
+	}
+
+	switch t := a.Type.(type) {
+	case tftypes.List:
+		elemType, err := convertType(t.ElementType)
+		if err != nil {
+			return nil, err
+		}
+		return providerschema.ListAttribute{
+			ElementType:         elemType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Sensitive:           a.Sensitive,
+			Description:         desc,
+			MarkdownDescription: markdownDesc,
+			DeprecationMessage:  deprecation,
+		}, nil
+	case tftypes.Set:
+		elemType, err := convertType(t.ElementType)
+		if err != nil {
+			return nil, err
+		}
+		return providerschema.SetAttribute{
+			ElementType:         elemType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Sensitive:           a.Sensitive,
+			Description:         desc,
+			MarkdownDescription: markdownDesc,
+			DeprecationMessage:  deprecation,
+		}, nil
+	case tftypes.Map:
+		elemType, err := convertType(t.ElementType)
+		if err != nil {
+			return nil, err
+		}
+		return providerschema.MapAttribute{
+			ElementType:         elemType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Sensitive:           a.Sensitive,
+			Description:         desc,
+			MarkdownDescription: markdownDesc,
+			DeprecationMessage:  deprecation,
+		}, nil
+	}
+
+	switch {
+	case a.Type.Is(tftypes.String):
+		return providerschema.StringAttribute{
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Sensitive:           a.Sensitive,
+			Description:         desc,
+			MarkdownDescription: markdownDesc,
+			DeprecationMessage:  deprecation,
+		}, nil
+	case a.Type.Is(tftypes.Bool):
+		return providerschema.BoolAttribute{
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Sensitive:           a.Sensitive,
+			Description:         desc,
+			MarkdownDescription: markdownDesc,
+			DeprecationMessage:  deprecation,
+		}, nil
+	case a.Type.Is(tftypes.Number):
+		return providerschema.NumberAttribute{
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Sensitive:           a.Sensitive,
+			Description:         desc,
+			MarkdownDescription: markdownDesc,
+			DeprecationMessage:  deprecation,
+		}, nil
+	default:
+		return nil, fmt.Errorf("the type %s is not supported", a.Type)
+	}
+}
+
+func convertType(t tftypes.Type) (attr.Type, error) {
+	switch v := t.(type) {
+	case tftypes.List:
+		elemType, err := convertType(v.ElementType)
+		if err != nil {
+			return nil, err
+		}
+		return types.ListType{ElemType: elemType}, nil
+	case tftypes.Set:
+		elemType, err := convertType(v.ElementType)
+		if err != nil {
+			return nil, err
+		}
+		return types.SetType{ElemType: elemType}, nil
+	case tftypes.Map:
+		elemType, err := convertType(v.ElementType)
+		if err != nil {
+			return nil, err
+		}
+		return types.MapType{ElemType: elemType}, nil
+	case tftypes.Object:
+		attrTypes := make(map[string]attr.Type, len(v.AttributeTypes))
+		for name, attrType := range v.AttributeTypes {
+			converted, err := convertType(attrType)
+			if err != nil {
+				return nil, err
+			}
+			attrTypes[name] = converted
+		}
+		return types.ObjectType{AttrTypes: attrTypes}, nil
+	}
+
+	switch {
+	case t.Is(tftypes.String):
+		return types.StringType, nil
+	case t.Is(tftypes.Bool):
+		return types.BoolType, nil
+	case t.Is(tftypes.Number):
+		return types.NumberType, nil
+	default:
+		return nil, fmt.Errorf("the type %s is not supported", t)
+	}
+}
+
+// description returns the plain text and markdown descriptions according to the kind.
+func description(desc string, kind tfprotov5.StringKind) (plain, markdown string) {
+	if kind == tfprotov5.StringKindMarkdown {
+		return "", desc
+	}
+	return desc, ""
+}
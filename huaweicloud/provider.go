@@ -8,9 +8,12 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/mitchellh/go-homedir"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
@@ -25,6 +28,7 @@ import (
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/asm"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/bcs"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/bms"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/bss"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/cae"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/cbh"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/cbr"
@@ -109,6 +113,7 @@ import (
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/vpn"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/waf"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/workspace"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
 )
 
 const (
@@ -222,7 +227,6 @@ func Provider() *schema.Provider {
 			"assume_role": {
 				Type:     schema.TypeList,
 				Optional: true,
-				MaxItems: 1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"agency_name": {
@@ -243,6 +247,31 @@ func Provider() *schema.Provider {
 							Description: descriptions["assume_role_domain_id"],
 							DefaultFunc: schema.EnvDefaultFunc("HW_ASSUME_ROLE_DOMAIN_ID", nil),
 						},
+						"duration": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Description:      descriptions["assume_role_duration"],
+							DefaultFunc:      schema.EnvDefaultFunc("HW_ASSUME_ROLE_DURATION", nil),
+							ValidateDiagFunc: validateAssumeRoleDuration,
+						},
+						"duration_seconds": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Description:  descriptions["assume_role_duration_seconds"],
+							ValidateFunc: validation.IntBetween(900, 86400),
+						},
+						"session_policy": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  descriptions["assume_role_session_policy"],
+							ValidateFunc: validation.StringIsJSON,
+						},
+						"session_tags": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Description: descriptions["assume_role_session_tags"],
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
 					},
 				},
 			},
@@ -369,6 +398,13 @@ func Provider() *schema.Provider {
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
 
+			"enable_endpoint_discovery": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: descriptions["enable_endpoint_discovery"],
+				DefaultFunc: schema.EnvDefaultFunc("HW_ENABLE_ENDPOINT_DISCOVERY", false),
+			},
+
 			"regional": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -389,6 +425,29 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("HW_PROFILE", ""),
 			},
 
+			"credential_process": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: descriptions["credential_process"],
+				DefaultFunc: schema.EnvDefaultFunc("HW_CREDENTIAL_PROCESS", ""),
+			},
+
+			"web_identity_token_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  descriptions["web_identity_token_file"],
+				RequiredWith: []string{"web_identity_provider"},
+				DefaultFunc:  schema.EnvDefaultFunc("HW_WEB_IDENTITY_TOKEN_FILE", ""),
+			},
+
+			"web_identity_provider": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  descriptions["web_identity_provider"],
+				RequiredWith: []string{"web_identity_token_file"},
+				DefaultFunc:  schema.EnvDefaultFunc("HW_WEB_IDENTITY_PROVIDER", ""),
+			},
+
 			"enterprise_project_id": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -403,12 +462,134 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("HW_MAX_RETRIES", 5),
 			},
 
+			"cache_dir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: descriptions["cache_dir"],
+				DefaultFunc: schema.EnvDefaultFunc("HW_CACHE_DIR", nil),
+			},
+
+			"cache_ttl": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      descriptions["cache_ttl"],
+				DefaultFunc:      schema.EnvDefaultFunc("HW_CACHE_TTL", nil),
+				ValidateDiagFunc: validateCacheTTL,
+			},
+
 			"enable_force_new": {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Description: descriptions["enable_force_new"],
 				DefaultFunc: schema.EnvDefaultFunc("HW_ENABLE_FORCE_NEW", false),
 			},
+
+			"preflight_quota_checks": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: descriptions["preflight_quota_checks"],
+				DefaultFunc: schema.EnvDefaultFunc("HW_PREFLIGHT_QUOTA_CHECKS", false),
+			},
+
+			"default_tags": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: descriptions["default_tags"],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tags": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Description: descriptions["default_tags_tags"],
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+
+			"ignore_tags": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: descriptions["ignore_tags"],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"keys": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Description: descriptions["ignore_tags_keys"],
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"key_prefixes": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Description: descriptions["ignore_tags_key_prefixes"],
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+
+			"rate_limits": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: descriptions["rate_limits"],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"service": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: descriptions["rate_limits_service"],
+						},
+						"rps": {
+							Type:         schema.TypeFloat,
+							Required:     true,
+							Description:  descriptions["rate_limits_rps"],
+							ValidateFunc: validation.FloatAtLeast(0.1),
+						},
+					},
+				},
+			},
+
+			"default_timeouts": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: descriptions["default_timeouts"],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"resource_type": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: descriptions["default_timeouts_resource_type"],
+						},
+						"create": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Description:      descriptions["default_timeouts_create"],
+							ValidateDiagFunc: validateDefaultTimeout,
+						},
+						"read": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Description:      descriptions["default_timeouts_read"],
+							ValidateDiagFunc: validateDefaultTimeout,
+						},
+						"update": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Description:      descriptions["default_timeouts_update"],
+							ValidateDiagFunc: validateDefaultTimeout,
+						},
+						"delete": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Description:      descriptions["default_timeouts_delete"],
+							ValidateDiagFunc: validateDefaultTimeout,
+						},
+					},
+				},
+			},
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
@@ -457,6 +638,8 @@ func Provider() *schema.Provider {
 			"huaweicloud_bms_flavors":   bms.DataSourceBmsFlavors(),
 			"huaweicloud_bms_instances": bms.DataSourceBmsInstances(),
 
+			"huaweicloud_bss_price": bss.DataSourcePrice(),
+
 			"huaweicloud_cae_environments": cae.DataSourceEnvironments(),
 			"huaweicloud_cae_applications": cae.DataSourceApplications(),
 
@@ -1630,12 +1813,14 @@ func Provider() *schema.Provider {
 			"huaweicloud_networking_vip":           vpc.ResourceNetworkingVip(),
 			"huaweicloud_networking_vip_associate": vpc.ResourceNetworkingVIPAssociateV2(),
 
-			"huaweicloud_obs_bucket":             obs.ResourceObsBucket(),
-			"huaweicloud_obs_bucket_acl":         obs.ResourceOBSBucketAcl(),
-			"huaweicloud_obs_bucket_object":      obs.ResourceObsBucketObject(),
-			"huaweicloud_obs_bucket_object_acl":  obs.ResourceOBSBucketObjectAcl(),
-			"huaweicloud_obs_bucket_policy":      obs.ResourceObsBucketPolicy(),
-			"huaweicloud_obs_bucket_replication": obs.ResourceObsBucketReplication(),
+			"huaweicloud_obs_bucket":              obs.ResourceObsBucket(),
+			"huaweicloud_obs_bucket_acl":          obs.ResourceOBSBucketAcl(),
+			"huaweicloud_obs_bucket_inventory":    obs.ResourceObsBucketInventory(),
+			"huaweicloud_obs_bucket_notification": obs.ResourceObsBucketNotification(),
+			"huaweicloud_obs_bucket_object":       obs.ResourceObsBucketObject(),
+			"huaweicloud_obs_bucket_object_acl":   obs.ResourceOBSBucketObjectAcl(),
+			"huaweicloud_obs_bucket_policy":       obs.ResourceObsBucketPolicy(),
+			"huaweicloud_obs_bucket_replication":  obs.ResourceObsBucketReplication(),
 
 			"huaweicloud_oms_migration_sync_task":  oms.ResourceMigrationSyncTask(),
 			"huaweicloud_oms_migration_task":       oms.ResourceMigrationTask(),
@@ -2021,6 +2206,16 @@ func Provider() *schema.Provider {
 		},
 	}
 
+	// make the supported resources support the provider-level default tags, and associate the API requests with the
+	// resources and data sources which send them in the trace log
+	for name, r := range provider.ResourcesMap {
+		config.EnableDefaultTags(name, r)
+		config.EnableAPITrace(name, r)
+	}
+	for name, r := range provider.DataSourcesMap {
+		config.EnableAPITrace("data."+name, r)
+	}
+
 	provider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		terraformVersion := provider.TerraformVersion
 		if terraformVersion == "" {
@@ -2029,6 +2224,15 @@ func Provider() *schema.Provider {
 			terraformVersion = "0.11+compatible"
 		}
 
+		// the default timeouts are applied to the resources before the plan
+		defaultTimeouts, err := flattenProviderDefaultTimeouts(d)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		if err := config.ApplyDefaultTimeouts(provider.ResourcesMap, defaultTimeouts); err != nil {
+			return nil, diag.FromErr(err)
+		}
+
 		return configureProvider(ctx, d, terraformVersion)
 	}
 
@@ -2087,28 +2291,88 @@ func init() {
 
 		"assume_role_domain_id": "The id of domain for v5 assume role.",
 
+		"assume_role_duration": "The validity period of the temporary credentials of the assumed agency, " +
+			"e.g. 1h or 90m.",
+
+		"assume_role_duration_seconds": "The validity period of the temporary credentials of the assumed agency, " +
+			"in seconds.",
+
+		"assume_role_session_policy": "The policy in JSON format to further restrict the permissions of the " +
+			"assumed agency session.",
+
+		"assume_role_session_tags": "The tags of the assumed agency session.",
+
 		"cloud": "The endpoint of cloud provider, defaults to myhuaweicloud.com",
 
 		"endpoints": "The custom endpoints used to override the default endpoint URL.",
 
+		"enable_endpoint_discovery": "Whether to discover the service endpoints from the IAM service catalog.",
+
 		"regional": "Whether the service endpoints are regional",
 
 		"shared_config_file": "The path to the shared config file. If not set, the default is ~/.hcloud/config.json.",
 
 		"profile": "The profile name as set in the shared config file.",
 
+		"credential_process": "The external command which outputs the temporary AK/SK in JSON format.",
+
+		"web_identity_token_file": "The path to the file which contains the OpenID Connect ID token.",
+
+		"web_identity_provider": "The name of the IAM identity provider to exchange the ID token with.",
+
 		"max_retries": "How many times HTTP connection should be retried until giving up.",
 
+		"cache_dir": "The directory to cache the domain ID, user ID, project IDs and website type across the " +
+			"terraform processes, e.g. ~/.huaweicloud/cache.",
+
+		"cache_ttl": "The validity period of the cached entries, e.g. 30m or 2h, defaults to 1h.",
+
 		"enterprise_project_id": "enterprise project id",
 
 		"enable_force_new": "Whether to enable ForceNew",
+
+		"preflight_quota_checks": "Whether to check the quotas of the compute and network resources being created " +
+			"during the plan.",
+
+		"default_tags": "Configuration block with settings to default resource tags across all resources.",
+
+		"default_tags_tags": "Resource tags to default across all resources.",
+
+		"ignore_tags": "Configuration block with settings to ignore resource tags across all resources.",
+
+		"ignore_tags_keys": "Resource tag keys to ignore across all resources.",
+
+		"ignore_tags_key_prefixes": "Resource tag key prefixes to ignore across all resources.",
+
+		"rate_limits": "Configuration blocks of the client-side rate limits of the services.",
+
+		"rate_limits_service": "The service name to limit the request rate, e.g. ecs, vpc.",
+
+		"rate_limits_rps": "The maximum number of requests per second sent to the service in each region.",
+
+		"default_timeouts": "Configuration blocks of the default timeouts of the resource types, which are used " +
+			"if they are not specified in the timeouts block of the resources.",
+
+		"default_timeouts_resource_type": "The resource type to apply the default timeouts, " +
+			"e.g. huaweicloud_cce_cluster.",
+
+		"default_timeouts_create": "The default timeout of the create operation, e.g. 30m or 2h.",
+
+		"default_timeouts_read": "The default timeout of the read operation, e.g. 30m or 2h.",
+
+		"default_timeouts_update": "The default timeout of the update operation, e.g. 30m or 2h.",
+
+		"default_timeouts_delete": "The default timeout of the delete operation, e.g. 30m or 2h.",
 	}
 }
 
-func configureProvider(_ context.Context, d *schema.ResourceData, terraformVersion string) (interface{},
+func configureProvider(ctx context.Context, d *schema.ResourceData, terraformVersion string) (interface{},
 	diag.Diagnostics) {
 	var tenantName, tenantID, delegatedProject, identityEndpoint string
 
+	// the OpenTelemetry tracing is enabled by the OTEL_EXPORTER_OTLP_* environment variables
+	config.InitTelemetry(ctx)
+
 	conf := config.Config{
 		AccessKey:           d.Get("access_key").(string),
 		SecretKey:           d.Get("secret_key").(string),
@@ -2129,11 +2393,18 @@ func configureProvider(_ context.Context, d *schema.ResourceData, terraformVersi
 		EnterpriseProjectID: d.Get("enterprise_project_id").(string),
 		SharedConfigFile:    d.Get("shared_config_file").(string),
 		Profile:             d.Get("profile").(string),
+		CredentialProcess:   d.Get("credential_process").(string),
 		TerraformVersion:    terraformVersion,
 		RegionProjectIDMap:  make(map[string]string),
 		RPLock:              new(sync.Mutex),
 		SecurityKeyLock:     new(sync.Mutex),
 		EnableForceNew:      d.Get("enable_force_new").(bool),
+		EndpointDiscovery:   d.Get("enable_endpoint_discovery").(bool),
+
+		PreflightQuotaChecks: d.Get("preflight_quota_checks").(bool),
+
+		WebIdentityTokenFile: d.Get("web_identity_token_file").(string),
+		WebIdentityProvider:  d.Get("web_identity_provider").(string),
 	}
 
 	// get assume role
@@ -2144,15 +2415,26 @@ func configureProvider(_ context.Context, d *schema.ResourceData, terraformVersi
 		delegatedDomianName := os.Getenv("HW_ASSUME_ROLE_DOMAIN_NAME")
 		delegatedDomianID := os.Getenv("HW_ASSUME_ROLE_DOMAIN_ID")
 		if delegatedAgencyName != "" && delegatedDomianName != "" {
-			conf.AssumeRoleAgency = delegatedAgencyName
-			conf.AssumeRoleDomain = delegatedDomianName
-			conf.AssumeRoleDomainID = delegatedDomianID
+			// the duration has been validated by the schema if the assume_role block is specified
+			duration, err := parseAssumeRoleDuration(os.Getenv("HW_ASSUME_ROLE_DURATION"))
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+			conf.AssumeRoles = []config.AssumeRole{
+				{
+					AgencyName: delegatedAgencyName,
+					DomainName: delegatedDomianName,
+					DomainID:   delegatedDomianID,
+					Duration:   duration,
+				},
+			}
 		}
 	} else {
-		assumeRole := assumeRoleList[0].(map[string]interface{})
-		conf.AssumeRoleAgency = assumeRole["agency_name"].(string)
-		conf.AssumeRoleDomain = assumeRole["domain_name"].(string)
-		conf.AssumeRoleDomainID = assumeRole["domain_id"].(string)
+		assumeRoles, err := flattenProviderAssumeRoles(assumeRoleList)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		conf.AssumeRoles = assumeRoles
 	}
 
 	conf.Region = d.Get("region").(string)
@@ -2223,6 +2505,43 @@ func configureProvider(_ context.Context, d *schema.ResourceData, terraformVersi
 	}
 	conf.Endpoints = endpoints
 
+	// get default tags
+	conf.DefaultTags = flattenProviderDefaultTags(d)
+
+	// get ignored tags
+	if ignoreTagsList := d.Get("ignore_tags").([]interface{}); len(ignoreTagsList) > 0 && ignoreTagsList[0] != nil {
+		ignoreTags := ignoreTagsList[0].(map[string]interface{})
+		conf.IgnoreTagKeys = utils.ExpandToStringListBySet(ignoreTags["keys"].(*schema.Set))
+		conf.IgnoreTagKeyPrefixes = utils.ExpandToStringListBySet(ignoreTags["key_prefixes"].(*schema.Set))
+	}
+
+	// get rate limits
+	for _, raw := range d.Get("rate_limits").([]interface{}) {
+		if rateLimit, ok := raw.(map[string]interface{}); ok {
+			conf.RateLimits = append(conf.RateLimits, config.RateLimit{
+				Service: rateLimit["service"].(string),
+				RPS:     rateLimit["rps"].(float64),
+			})
+		}
+	}
+
+	// get the cache directory
+	if v := d.Get("cache_dir").(string); v != "" {
+		cacheDir, err := homedir.Expand(v)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		cacheTTL, err := parseCacheTTL(d.Get("cache_ttl").(string))
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		conf.CacheDir = cacheDir
+		conf.CacheTTL = cacheTTL
+	}
+
+	// the API interactions are recorded or replayed in the acceptance tests if HW_RECORDER_MODE is specified
+	conf.Recorder = config.ActiveRecorder()
+
 	if err := conf.LoadAndValidate(); err != nil {
 		return nil, diag.FromErr(err)
 	}
@@ -2288,6 +2607,56 @@ func flattenProviderEndpoints(d *schema.ResourceData) (map[string]string, error)
 	return epMap, nil
 }
 
+// flattenProviderAssumeRoles flattens the assume_role blocks, the agencies will be assumed in order.
+func flattenProviderAssumeRoles(assumeRoleList []interface{}) ([]config.AssumeRole, error) {
+	assumeRoles := make([]config.AssumeRole, 0, len(assumeRoleList))
+	for i, raw := range assumeRoleList {
+		assumeRole, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		role := config.AssumeRole{
+			AgencyName:    assumeRole["agency_name"].(string),
+			DomainName:    assumeRole["domain_name"].(string),
+			DomainID:      assumeRole["domain_id"].(string),
+			SessionPolicy: assumeRole["session_policy"].(string),
+			SessionTags:   utils.ExpandToStringMap(assumeRole["session_tags"].(map[string]interface{})),
+		}
+
+		// the duration_seconds takes precedence over the duration which may be sourced from the environment variable
+		if durationSeconds := assumeRole["duration_seconds"].(int); durationSeconds != 0 {
+			role.Duration = time.Duration(durationSeconds) * time.Second
+		} else {
+			// the duration has been validated by the schema
+			role.Duration, _ = parseAssumeRoleDuration(assumeRole["duration"].(string))
+		}
+
+		if role.DomainID == "" && (role.SessionPolicy != "" || len(role.SessionTags) > 0) {
+			return nil, fmt.Errorf("domain_id is required to specify session_policy or session_tags in "+
+				"assume_role.%d", i)
+		}
+		assumeRoles = append(assumeRoles, role)
+	}
+	return assumeRoles, nil
+}
+
+func flattenProviderDefaultTags(d *schema.ResourceData) map[string]string {
+	defaultTags := make(map[string]string)
+	defaultTagsList := d.Get("default_tags").([]interface{})
+	if len(defaultTagsList) == 0 || defaultTagsList[0] == nil {
+		return defaultTags
+	}
+
+	rawTags := defaultTagsList[0].(map[string]interface{})["tags"].(map[string]interface{})
+	for key, val := range rawTags {
+		defaultTags[key] = val.(string)
+	}
+
+	log.Printf("[DEBUG] default tags: %+v", defaultTags)
+	return defaultTags
+}
+
 func getCloudDomain(cloud, region string) string {
 	// first, use the specified value
 	if cloud != "" {
@@ -2301,6 +2670,97 @@ func getCloudDomain(cloud, region string) string {
 	return defaultCloud
 }
 
+// parseAssumeRoleDuration parses the duration of the assumed agency, the empty value means the default duration.
+func parseAssumeRoleDuration(v string) (time.Duration, error) {
+	if v == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration of assume_role: %s", err)
+	}
+	if duration < 15*time.Minute || duration > 24*time.Hour {
+		return 0, fmt.Errorf("the duration of assume_role must be between 15m and 24h, but got %s", v)
+	}
+	return duration, nil
+}
+
+func validateAssumeRoleDuration(v interface{}, _ cty.Path) diag.Diagnostics {
+	_, err := parseAssumeRoleDuration(v.(string))
+	return diag.FromErr(err)
+}
+
+// flattenProviderDefaultTimeouts flattens the default_timeouts blocks, the durations have been validated by the
+// schema.
+func flattenProviderDefaultTimeouts(d *schema.ResourceData) ([]config.DefaultTimeouts, error) {
+	rawList := d.Get("default_timeouts").([]interface{})
+	result := make([]config.DefaultTimeouts, 0, len(rawList))
+	for _, raw := range rawList {
+		timeouts, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		durations := make(map[string]time.Duration, 4)
+		for _, key := range []string{"create", "read", "update", "delete"} {
+			duration, err := parseDefaultTimeout(timeouts[key].(string))
+			if err != nil {
+				return nil, err
+			}
+			durations[key] = duration
+		}
+		result = append(result, config.DefaultTimeouts{
+			ResourceType: timeouts["resource_type"].(string),
+			Create:       durations["create"],
+			Read:         durations["read"],
+			Update:       durations["update"],
+			Delete:       durations["delete"],
+		})
+	}
+	return result, nil
+}
+
+func parseDefaultTimeout(v string) (time.Duration, error) {
+	if v == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout of default_timeouts: %s", err)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("the timeout of default_timeouts must be positive, but got %s", v)
+	}
+	return timeout, nil
+}
+
+func validateDefaultTimeout(v interface{}, _ cty.Path) diag.Diagnostics {
+	_, err := parseDefaultTimeout(v.(string))
+	return diag.FromErr(err)
+}
+
+func parseCacheTTL(v string) (time.Duration, error) {
+	if v == "" {
+		return config.DefaultCacheTTL, nil
+	}
+
+	ttl, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cache_ttl: %s", err)
+	}
+	if ttl <= 0 {
+		return 0, fmt.Errorf("the cache_ttl must be positive, but got %s", v)
+	}
+	return ttl, nil
+}
+
+func validateCacheTTL(v interface{}, _ cty.Path) diag.Diagnostics {
+	_, err := parseCacheTTL(v.(string))
+	return diag.FromErr(err)
+}
+
 func readConfig(c *config.Config) error {
 	profilePath, err := homedir.Expand(c.SharedConfigFile)
 	if err != nil {
@@ -2358,6 +2818,17 @@ func readConfig(c *config.Config) error {
 		c.SecurityToken = providerConfig.SecurityToken
 	}
 
+	// the credentials are retrieved by the external command or exchanged with the ID token
+	if providerConfig.CredentialProcess != "" {
+		c.CredentialProcess = providerConfig.CredentialProcess
+	}
+	if providerConfig.WebIdentityTokenFile != "" {
+		c.WebIdentityTokenFile = providerConfig.WebIdentityTokenFile
+	}
+	if providerConfig.WebIdentityProvider != "" {
+		c.WebIdentityProvider = providerConfig.WebIdentityProvider
+	}
+
 	// non required fields
 	if providerConfig.Region != "" {
 		c.Region = providerConfig.Region
@@ -2368,12 +2839,15 @@ func readConfig(c *config.Config) error {
 	if providerConfig.ProjectId != "" {
 		c.TenantID = providerConfig.ProjectId
 	}
-	// assume role
+	// assume role, the agency in the shared config file overrides the first one in the provider
 	if providerConfig.AgencyName != "" {
-		c.AssumeRoleAgency = providerConfig.AgencyName
-	}
-	if providerConfig.AgencyDomainName != "" {
-		c.AssumeRoleDomain = providerConfig.AgencyDomainName
+		if len(c.AssumeRoles) == 0 {
+			c.AssumeRoles = []config.AssumeRole{{}}
+		}
+		c.AssumeRoles[0].AgencyName = providerConfig.AgencyName
+		if providerConfig.AgencyDomainName != "" {
+			c.AssumeRoles[0].DomainName = providerConfig.AgencyDomainName
+		}
 	}
 
 	return nil
@@ -0,0 +1,38 @@
+package huaweicloud
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/tf5to6server"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/framework"
+)
+
+// ProviderServerFactory returns the factory of the provider server, which muxes the SDKv2 provider and the
+// plugin-framework provider with the protocol version 6. The resources and data sources of the two providers share
+// the same config.Config, and the provider-defined functions are served by the plugin-framework provider.
+func ProviderServerFactory(ctx context.Context) (func() tfprotov6.ProviderServer, error) {
+	sdkProvider := Provider()
+	// the SDKv2 provider only speaks the protocol version 5, so it's upgraded to be muxed
+	upgradedSdkServer, err := tf5to6server.UpgradeServer(ctx, sdkProvider.GRPCProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	servers := []func() tfprotov6.ProviderServer{
+		// the SDKv2 provider must be configured before the plugin-framework provider, which uses its config
+		func() tfprotov6.ProviderServer {
+			return upgradedSdkServer
+		},
+		providerserver.NewProtocol6(framework.New(sdkProvider)()),
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServer(ctx, servers...)
+	if err != nil {
+		return nil, err
+	}
+	return muxServer.ProviderServer, nil
+}
@@ -0,0 +1,42 @@
+package huaweicloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+func TestProviderServerFactory(t *testing.T) {
+	ctx := context.Background()
+	serverFactory, err := ProviderServerFactory(ctx)
+	if err != nil {
+		t.Fatalf("failed to create the provider server: %s", err)
+	}
+
+	// the mux server reports an error if the schemas of the two providers are different
+	resp, err := serverFactory().GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("failed to get the provider schema: %s", err)
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("unexpected error in the provider schema: %s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	if _, ok := resp.ResourceSchemas["huaweicloud_vpc"]; !ok {
+		t.Error("the resources of the SDKv2 provider are not served")
+	}
+	for _, name := range []string{"huaweicloud_csms_secret_version", "huaweicloud_kms_data_key"} {
+		if _, ok := resp.EphemeralResourceSchemas[name]; !ok {
+			t.Errorf("the ephemeral resource %s is not served", name)
+		}
+	}
+	for _, name := range []string{"parse_urn", "parse_resource_id", "service_endpoint", "project_endpoint",
+		"validate_cidr", "expand_cidr", "obs_bucket_domain"} {
+		if _, ok := resp.Functions[name]; !ok {
+			t.Errorf("the function %s is not served", name)
+		}
+	}
+}
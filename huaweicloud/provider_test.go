@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/helper/pathorcontents"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils/fmtp"
 )
@@ -99,6 +100,20 @@ func TestProvider(t *testing.T) {
 	}
 }
 
+func TestProvider_defaultTagsResources(t *testing.T) {
+	resources := Provider().ResourcesMap
+	for _, name := range config.DefaultTagsResources() {
+		r, ok := resources[name]
+		if !ok {
+			t.Errorf("the resource %s which supports the default tags is not found", name)
+			continue
+		}
+		if _, ok := r.Schema["tags_all"]; !ok {
+			t.Errorf("the resource %s which supports the default tags does not export tags_all", name)
+		}
+	}
+}
+
 // Steps for configuring HuaweiCloud with SSL validation are here:
 // https://github.com/hashicorp/terraform/pull/6279#issuecomment-219020144
 func TestAccProvider_caCertFile(t *testing.T) {
@@ -502,7 +502,7 @@ func resourceClusterV1Create(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	// create tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	if len(tagRaw) > 0 {
 		taglist := utils.ExpandResourceTags(tagRaw)
 		if tagErr := tags.Create(client, "clusters", d.Id(), taglist).ExtractErr(); tagErr != nil {
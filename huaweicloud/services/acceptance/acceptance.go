@@ -2,11 +2,13 @@
 package acceptance
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
@@ -236,6 +238,10 @@ var (
 	HW_CCE_CHART_PATH = os.Getenv("HW_CCE_CHART_PATH")
 	// The cluster name of the CCE
 	HW_CCE_CLUSTER_NAME = os.Getenv("HW_CCE_CLUSTER_NAME")
+	// The cluster version of the CCE before the upgrade, e.g. v1.27
+	HW_CCE_CLUSTER_VERSION = os.Getenv("HW_CCE_CLUSTER_VERSION")
+	// The target cluster version of the CCE upgrade, e.g. v1.28
+	HW_CCE_CLUSTER_TARGET_VERSION = os.Getenv("HW_CCE_CLUSTER_TARGET_VERSION")
 	// The partition az of the CCE
 	HW_CCE_PARTITION_AZ = os.Getenv("HW_CCE_PARTITION_AZ")
 	// The namespace of the workload is located
@@ -442,10 +448,15 @@ var TestAccProviders map[string]*schema.Provider
 // TestAccProviderFactories is a static map containing only the main provider instance
 var TestAccProviderFactories map[string]func() (*schema.Provider, error)
 
+// TestAccProtoV6ProviderFactories is a static map containing the provider server which muxes the SDKv2 provider and
+// the plugin-framework provider, it's required to test the provider-defined functions
+var TestAccProtoV6ProviderFactories map[string]func() (tfprotov6.ProviderServer, error)
+
 // TestAccProvider is the "main" provider instance
 var TestAccProvider *schema.Provider
 
 func init() {
+	initRecorderEnv()
 	TestAccProvider = huaweicloud.Provider()
 
 	TestAccProviders = map[string]*schema.Provider{
@@ -457,6 +468,16 @@ func init() {
 			return TestAccProvider, nil
 		},
 	}
+
+	TestAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+		"huaweicloud": func() (tfprotov6.ProviderServer, error) {
+			serverFactory, err := huaweicloud.ProviderServerFactory(context.Background())
+			if err != nil {
+				return nil, err
+			}
+			return serverFactory(), nil
+		},
+	}
 }
 
 func preCheckRequiredEnvVars(t *testing.T) {
@@ -556,6 +577,7 @@ func TestAccPreCheck(t *testing.T) {
 	}
 
 	preCheckRequiredEnvVars(t)
+	startRecorder(t)
 }
 
 // lintignore:AT003
@@ -699,19 +721,19 @@ func TestAccPreCheckMaas(t *testing.T) {
 }
 
 func RandomAccResourceName() string {
-	return fmt.Sprintf("tf_test_%s", acctest.RandString(5))
+	return fmt.Sprintf("tf_test_%s", randString(5))
 }
 
 func RandomAccResourceNameWithDash() string {
-	return fmt.Sprintf("tf-test-%s", acctest.RandString(5))
+	return fmt.Sprintf("tf-test-%s", randString(5))
 }
 
 func RandomCidr() string {
-	return fmt.Sprintf("172.16.%d.0/24", acctest.RandIntRange(0, 255))
+	return fmt.Sprintf("172.16.%d.0/24", randIntRange(0, 255))
 }
 
 func RandomCidrAndGatewayIp() (string, string) {
-	seed := acctest.RandIntRange(0, 255)
+	seed := randIntRange(0, 255)
 	return fmt.Sprintf("172.16.%d.0/24", seed), fmt.Sprintf("172.16.%d.1", seed)
 }
 
@@ -723,10 +745,10 @@ func RandomPassword(customChars ...string) string {
 		specialChars = customChars[0]
 	}
 	return fmt.Sprintf("%s%s%s%d",
-		acctest.RandStringFromCharSet(2, "ABCDEFGHIJKLMNOPQRSTUVWXZY"),
-		acctest.RandStringFromCharSet(3, acctest.CharSetAlpha),
-		acctest.RandStringFromCharSet(2, specialChars),
-		acctest.RandIntRange(1000, 9999))
+		randStringFromCharSet(2, "ABCDEFGHIJKLMNOPQRSTUVWXZY"),
+		randStringFromCharSet(3, acctest.CharSetAlpha),
+		randStringFromCharSet(2, specialChars),
+		randIntRange(1000, 9999))
 }
 
 // lintignore:AT003
@@ -1366,6 +1388,13 @@ func TestAccPreCheckCceClusterId(t *testing.T) {
 	}
 }
 
+// lintignore:AT003
+func TestAccPreCheckCceClusterUpgrade(t *testing.T) {
+	if HW_CCE_CLUSTER_VERSION == "" || HW_CCE_CLUSTER_TARGET_VERSION == "" {
+		t.Skip("HW_CCE_CLUSTER_VERSION and HW_CCE_CLUSTER_TARGET_VERSION must be set for this acceptance test")
+	}
+}
+
 // lintignore:AT003
 func TestAccPreCheckCceChartPath(t *testing.T) {
 	// HW_CCE_CHART_PATH is the absolute path of the chart package
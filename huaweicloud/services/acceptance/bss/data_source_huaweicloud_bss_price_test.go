@@ -0,0 +1,98 @@
+package bss
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+)
+
+func TestAccDataSourceBssPrice_basic(t *testing.T) {
+	postPaid := "data.huaweicloud_bss_price.postpaid"
+	prePaid := "data.huaweicloud_bss_price.prepaid"
+	dc1 := acceptance.InitDataSourceCheck(postPaid)
+	dc2 := acceptance.InitDataSourceCheck(prePaid)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck: func() {
+			acceptance.TestAccPreCheck(t)
+		},
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceBssPrice_basic,
+				Check: resource.ComposeTestCheckFunc(
+					dc1.CheckResourceExists(),
+					resource.TestCheckResourceAttrSet(postPaid, "currency"),
+					resource.TestCheckResourceAttr(postPaid, "prices.#", "3"),
+					dc2.CheckResourceExists(),
+					resource.TestCheckResourceAttr(prePaid, "prices.#", "4"),
+					resource.TestCheckOutput("is_amount_positive", "true"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceBssPrice_basic = `
+data "huaweicloud_availability_zones" "test" {}
+
+data "huaweicloud_compute_flavors" "test" {
+  availability_zone = data.huaweicloud_availability_zones.test.names[0]
+  performance_type  = "normal"
+  cpu_core_count    = 2
+  memory_size       = 4
+}
+
+data "huaweicloud_bss_price" "postpaid" {
+  charging_mode = "postPaid"
+
+  products {
+    type              = "ecs"
+    spec              = "${data.huaweicloud_compute_flavors.test.ids[0]}.linux"
+    availability_zone = data.huaweicloud_availability_zones.test.names[0]
+  }
+  products {
+    type = "evs"
+    spec = "SSD"
+    size = 40
+  }
+  products {
+    type = "eip_bandwidth"
+    spec = "19_bgp"
+    size = 5
+  }
+}
+
+data "huaweicloud_bss_price" "prepaid" {
+  charging_mode = "prePaid"
+  period_unit   = "month"
+  period        = 1
+
+  products {
+    type              = "ecs"
+    spec              = "${data.huaweicloud_compute_flavors.test.ids[0]}.linux"
+    availability_zone = data.huaweicloud_availability_zones.test.names[0]
+    quantity          = 2
+  }
+  products {
+    type = "evs"
+    spec = "SSD"
+    size = 40
+  }
+  products {
+    type = "eip_bandwidth"
+    spec = "19_bgp"
+    size = 5
+  }
+  products {
+    type = "rds"
+    spec = "rds.mysql.n1.large.2"
+  }
+}
+
+output "is_amount_positive" {
+  value = data.huaweicloud_bss_price.postpaid.amount > 0 && data.huaweicloud_bss_price.prepaid.amount > 0
+}
+`
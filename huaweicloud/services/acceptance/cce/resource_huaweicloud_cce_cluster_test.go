@@ -2,6 +2,7 @@ package cce
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -402,6 +403,42 @@ func TestAccCluster_resizePeriod(t *testing.T) {
 	})
 }
 
+func TestAccCluster_upgrade(t *testing.T) {
+	var cluster clusters.Clusters
+
+	rName := acceptance.RandomAccResourceNameWithDash()
+	resourceName := "huaweicloud_cce_cluster.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck: func() {
+			acceptance.TestAccPreCheck(t)
+			acceptance.TestAccPreCheckCceClusterUpgrade(t)
+		},
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      testAccCheckClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCluster_upgrade(rName, acceptance.HW_CCE_CLUSTER_VERSION),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckClusterExists(resourceName, &cluster),
+					resource.TestCheckResourceAttr(resourceName, "name", rName),
+					resource.TestCheckResourceAttr(resourceName, "status", "Available"),
+					resource.TestMatchResourceAttr(resourceName, "cluster_version",
+						regexp.MustCompile("^"+acceptance.HW_CCE_CLUSTER_VERSION)),
+				),
+			},
+			{
+				Config: testAccCluster_upgrade(rName, acceptance.HW_CCE_CLUSTER_TARGET_VERSION),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "status", "Available"),
+					resource.TestMatchResourceAttr(resourceName, "cluster_version",
+						regexp.MustCompile("^"+acceptance.HW_CCE_CLUSTER_TARGET_VERSION)),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckClusterDestroy(s *terraform.State) error {
 	config := acceptance.TestAccProvider.Meta().(*config.Config)
 	cceClient, err := config.CceV3Client(acceptance.HW_REGION_NAME)
@@ -836,3 +873,27 @@ resource "huaweicloud_cce_cluster" "test" {
 }
 `, common.TestVpc(rName), rName)
 }
+
+func testAccCluster_upgrade(rName, version string) string {
+	return fmt.Sprintf(`
+%[1]s
+
+resource "huaweicloud_cce_cluster" "test" {
+  name                   = "%[2]s"
+  flavor_id              = "cce.s1.small"
+  cluster_version        = "%[3]s"
+  vpc_id                 = huaweicloud_vpc.test.id
+  subnet_id              = huaweicloud_vpc_subnet.test.id
+  container_network_type = "overlay_l2"
+  service_network_cidr   = "10.248.0.0/16"
+
+  upgrade_strategy {
+    user_defined_step = 10
+  }
+
+  timeouts {
+    update = "2h"
+  }
+}
+`, common.TestVpc(rName), rName, version)
+}
@@ -88,6 +88,49 @@ func TestAccNodePool_basic(t *testing.T) {
 	})
 }
 
+func TestAccNodePool_rollingUpdate(t *testing.T) {
+	var (
+		nodePool nodepools.NodePool
+
+		name         = acceptance.RandomAccResourceNameWithDash()
+		resourceName = "huaweicloud_cce_node_pool.test"
+
+		baseConfig = testAccNodePool_base(name)
+
+		rc = acceptance.InitResourceCheck(
+			resourceName,
+			&nodePool,
+			getNodePoolFunc,
+		)
+	)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { acceptance.TestAccPreCheck(t) },
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      rc.CheckResourceDestroy(),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNodePool_rollingUpdate(name, baseConfig, 40),
+				Check: resource.ComposeTestCheckFunc(
+					rc.CheckResourceExists(),
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "current_node_count", "2"),
+					resource.TestCheckResourceAttr(resourceName, "root_volume.0.size", "40"),
+				),
+			},
+			{
+				Config: testAccNodePool_rollingUpdate(name, baseConfig, 50),
+				Check: resource.ComposeTestCheckFunc(
+					rc.CheckResourceExists(),
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "current_node_count", "2"),
+					resource.TestCheckResourceAttr(resourceName, "root_volume.0.size", "50"),
+				),
+			},
+		},
+	})
+}
+
 func testAccNodePool_base(rName string) string {
 	return fmt.Sprintf(`
 %[1]s
@@ -942,3 +985,38 @@ resource "huaweicloud_cce_node_pool" "test" {
 }
 `, testAccNodePool_base(rName), rName)
 }
+
+func testAccNodePool_rollingUpdate(name, baseConfig string, rootVolumeSize int) string {
+	return fmt.Sprintf(`
+%[1]s
+
+resource "huaweicloud_cce_node_pool" "test" {
+  cluster_id         = huaweicloud_cce_cluster.test.id
+  name               = "%[2]s"
+  os                 = "EulerOS 2.9"
+  flavor_id          = data.huaweicloud_compute_flavors.test.ids[0]
+  initial_node_count = 2
+  availability_zone  = data.huaweicloud_availability_zones.test.names[0]
+  key_pair           = huaweicloud_kps_keypair.test.name
+
+  root_volume {
+    size       = %[3]d
+    volumetype = "SSD"
+  }
+  data_volumes {
+    size       = 100
+    volumetype = "SSD"
+  }
+
+  rolling_update {
+    max_surge       = 1
+    max_unavailable = 1
+    drain_timeout   = 300
+  }
+
+  timeouts {
+    update = "1h"
+  }
+}
+`, baseConfig, name, rootVolumeSize)
+}
@@ -0,0 +1,64 @@
+package dew
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+)
+
+// The ephemeral resources are supported by Terraform 1.10 or later, and the values are checked by the postconditions
+// because they are never persisted to the state.
+func TestAccEphemeralCsmsSecretVersion_basic(t *testing.T) {
+	name := acceptance.RandomAccResourceName()
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { acceptance.TestAccPreCheck(t) },
+		ProtoV6ProviderFactories: acceptance.TestAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEphemeralCsmsSecretVersion_basic(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("huaweicloud_csms_secret.secret_1", "name", name),
+					testAccCheckNoSecretTextInState(name),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckNoSecretTextInState(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			for k, v := range rs.Primary.Attributes {
+				if v == "this is a password" && rs.Type != "huaweicloud_csms_secret" {
+					return fmt.Errorf("the secret text of %s is persisted to the state by %s.%s", name, rs.Type, k)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+func testAccEphemeralCsmsSecretVersion_basic(name string) string {
+	return fmt.Sprintf(`
+resource "huaweicloud_csms_secret" "secret_1" {
+  name        = "%[1]s"
+  secret_text = "this is a password"
+}
+
+ephemeral "huaweicloud_csms_secret_version" "version_1" {
+  secret_name = huaweicloud_csms_secret.secret_1.name
+
+  lifecycle {
+    postcondition {
+      condition     = self.secret_text == "this is a password" && self.version != ""
+      error_message = "unexpected secret version"
+    }
+  }
+}
+`, name)
+}
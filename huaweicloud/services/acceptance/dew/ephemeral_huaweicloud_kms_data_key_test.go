@@ -0,0 +1,49 @@
+package dew
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+)
+
+func TestAccEphemeralKmsDataKey_basic(t *testing.T) {
+	name := acceptance.RandomAccResourceName()
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { acceptance.TestAccPreCheck(t) },
+		ProtoV6ProviderFactories: acceptance.TestAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEphemeralKmsDataKey_basic(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("huaweicloud_kms_key.key_1", "key_alias", name),
+				),
+			},
+		},
+	})
+}
+
+func testAccEphemeralKmsDataKey_basic(name string) string {
+	return fmt.Sprintf(`
+resource "huaweicloud_kms_key" "key_1" {
+  key_alias    = "%s"
+  pending_days = "7"
+}
+
+ephemeral "huaweicloud_kms_data_key" "key_1" {
+  key_id         = huaweicloud_kms_key.key_1.id
+  datakey_length = "512"
+
+  lifecycle {
+    postcondition {
+      # the plaintext is expressed in hexadecimal format, two characters indicate one byte
+      condition     = length(self.plain_text) == 128 && self.cipher_text != ""
+      error_message = "unexpected data key"
+    }
+  }
+}
+`, name)
+}
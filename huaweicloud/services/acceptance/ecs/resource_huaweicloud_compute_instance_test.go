@@ -8,9 +8,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/chnsz/golangsdk/openstack/ecs/v1/cloudservers"
+	th "github.com/chnsz/golangsdk/testhelper"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance/mock"
 )
 
 func TestAccComputeInstance_basic(t *testing.T) {
@@ -691,3 +693,179 @@ resource "huaweicloud_compute_instance" "test" {
 }
 `, testAccCompute_data, rName)
 }
+
+func TestAccComputeInstance_mock(t *testing.T) {
+	server := mock.NewServer(t)
+	rName := acceptance.RandomAccResourceName()
+	resourceName := "huaweicloud_compute_instance.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { mock.PreCheck(t) },
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      server.CheckDestroy("huaweicloud_compute_instance", "cloudservers"),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + testAccComputeInstance_mock(rName, "bar"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", rName),
+					resource.TestCheckResourceAttr(resourceName, "status", "ACTIVE"),
+					resource.TestCheckResourceAttr(resourceName, "image_name", mock.ImageName),
+					resource.TestCheckResourceAttr(resourceName, "volume_attached.#", "2"),
+					resource.TestCheckResourceAttrPair(resourceName, "public_ip", "huaweicloud_vpc_eip.test", "address"),
+				),
+			},
+			{
+				Config: server.ProviderConfig() + testAccComputeInstance_mock(rName, "bar_updated"),
+				Check:  resource.TestCheckResourceAttr(resourceName, "tags.foo", "bar_updated"),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"stop_before_destroy", "delete_disks_on_termination", "delete_eip_on_termination", "data_disks",
+					"eip_id", "power_action",
+				},
+			},
+		},
+	})
+}
+
+func TestComputeInstance_mockLifecycle(t *testing.T) {
+	t.Parallel()
+
+	server := mock.NewServer(t)
+	vpc := server.Apply(t, "huaweicloud_vpc", map[string]interface{}{
+		"name": "vpc-mock",
+		"cidr": "192.168.0.0/16",
+	})
+	subnet := server.Apply(t, "huaweicloud_vpc_subnet", map[string]interface{}{
+		"name":       "subnet-mock",
+		"cidr":       "192.168.0.0/24",
+		"gateway_ip": "192.168.0.1",
+		"vpc_id":     vpc.ID,
+	})
+	secgroup := server.Apply(t, "huaweicloud_networking_secgroup", map[string]interface{}{
+		"name": "secgroup-mock",
+	})
+	eip := server.Apply(t, "huaweicloud_vpc_eip", map[string]interface{}{
+		"publicip": []interface{}{
+			map[string]interface{}{"type": "5_bgp"},
+		},
+		"bandwidth": []interface{}{
+			map[string]interface{}{"share_type": "PER", "name": "eip-mock", "size": 5, "charge_mode": "traffic"},
+		},
+	})
+
+	instanceConfig := func(name, description string, tags map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"name":               name,
+			"description":        description,
+			"image_id":           mock.ImageID,
+			"flavor_id":          mock.FlavorID,
+			"security_group_ids": []interface{}{secgroup.ID},
+			"availability_zone":  mock.AvailabilityZone,
+			"system_disk_type":   "SSD",
+			"system_disk_size":   50,
+			"eip_id":             eip.ID,
+			"network": []interface{}{
+				map[string]interface{}{"uuid": subnet.ID},
+			},
+			"data_disks": []interface{}{
+				map[string]interface{}{"type": "SSD", "size": 10},
+			},
+			"tags": tags,
+		}
+	}
+	mock.Lifecycle{
+		ResourceType: "huaweicloud_compute_instance",
+		Steps: []mock.Step{
+			{
+				Config: instanceConfig("ecs-mock", "created by mock test", map[string]interface{}{"foo": "bar"}),
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "ACTIVE", state.Attributes["status"])
+					th.AssertEquals(t, "192.168.0.10", state.Attributes["access_ip_v4"])
+					th.AssertEquals(t, "192.168.0.10", state.Attributes["network.0.fixed_ip_v4"])
+					th.AssertEquals(t, "2", state.Attributes["volume_attached.#"])
+					th.AssertEquals(t, "50", state.Attributes["system_disk_size"])
+					th.AssertEquals(t, mock.ImageName, state.Attributes["image_name"])
+					th.AssertEquals(t, eip.Attributes["address"], state.Attributes["public_ip"])
+					th.AssertEquals(t, 2, server.ResourceCount("cloudvolumes"))
+				},
+			},
+			{
+				Config: instanceConfig("ecs-mock-updated", "updated by mock test", map[string]interface{}{"foo": "bar_updated"}),
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "ecs-mock-updated", state.Attributes["name"])
+					th.AssertEquals(t, "updated by mock test", server.Resource("cloudservers", state.ID)["description"])
+					th.AssertEquals(t, "bar_updated", state.Attributes["tags.foo"])
+				},
+			},
+		},
+		ImportStateVerifyIgnore: []string{
+			"stop_before_destroy", "delete_disks_on_termination", "delete_eip_on_termination", "data_disks",
+			"power_action", "eip_id",
+		},
+	}.Run(t, server)
+
+	// the ports and the system disk are released together with the instance, but the data disk is kept since
+	// delete_disks_on_termination is not enabled
+	th.AssertEquals(t, 0, server.ResourceCount("ports"))
+	th.AssertEquals(t, 1, server.ResourceCount("cloudvolumes"))
+}
+
+func testAccComputeInstance_mock(rName, tagValue string) string {
+	return fmt.Sprintf(`
+resource "huaweicloud_vpc" "test" {
+  name = "%[1]s"
+  cidr = "192.168.0.0/16"
+}
+
+resource "huaweicloud_vpc_subnet" "test" {
+  name       = "%[1]s"
+  cidr       = "192.168.0.0/24"
+  gateway_ip = "192.168.0.1"
+  vpc_id     = huaweicloud_vpc.test.id
+}
+
+resource "huaweicloud_networking_secgroup" "test" {
+  name = "%[1]s"
+}
+
+resource "huaweicloud_vpc_eip" "test" {
+  publicip {
+    type = "5_bgp"
+  }
+
+  bandwidth {
+    share_type  = "PER"
+    name        = "%[1]s"
+    size        = 5
+    charge_mode = "traffic"
+  }
+}
+
+resource "huaweicloud_compute_instance" "test" {
+  name               = "%[1]s"
+  image_id           = "%[2]s"
+  flavor_id          = "%[3]s"
+  security_group_ids = [huaweicloud_networking_secgroup.test.id]
+  availability_zone  = "%[4]s"
+  system_disk_type   = "SSD"
+  eip_id             = huaweicloud_vpc_eip.test.id
+
+  network {
+    uuid = huaweicloud_vpc_subnet.test.id
+  }
+
+  data_disks {
+    type = "SSD"
+    size = 10
+  }
+
+  tags = {
+    foo = "%[5]s"
+  }
+}
+`, rName, mock.ImageID, mock.FlavorID, mock.AvailabilityZone, tagValue)
+}
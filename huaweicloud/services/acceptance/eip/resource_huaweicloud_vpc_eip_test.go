@@ -9,9 +9,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/chnsz/golangsdk/openstack/networking/v1/eips"
+	th "github.com/chnsz/golangsdk/testhelper"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance/mock"
 )
 
 func getEipResourceFunc(conf *config.Config, state *terraform.ResourceState) (interface{}, error) {
@@ -515,3 +517,84 @@ resource "huaweicloud_vpc_eip" "test" {
 }
 `, rName, size)
 }
+
+func TestAccVpcEip_mock(t *testing.T) {
+	var (
+		server       = mock.NewServer(t)
+		resourceName = "huaweicloud_vpc_eip.test"
+		randName     = acceptance.RandomAccResourceName()
+	)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { mock.PreCheck(t) },
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      server.CheckDestroy("huaweicloud_vpc_eip", "publicips"),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + testAccVpcEip_basic(randName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", randName),
+					resource.TestCheckResourceAttr(resourceName, "status", "UNBOUND"),
+					resource.TestCheckResourceAttr(resourceName, "bandwidth.0.size", "5"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "bar"),
+					resource.TestCheckResourceAttrSet(resourceName, "address"),
+				),
+			},
+			{
+				Config: server.ProviderConfig() + testAccVpcEip_update(randName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "bandwidth.0.size", "8"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "bar1"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestVpcEip_mockLifecycle(t *testing.T) {
+	t.Parallel()
+
+	server := mock.NewServer(t)
+	eipConfig := func(name string, size int, tags map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"name": name,
+			"publicip": []interface{}{
+				map[string]interface{}{"type": "5_bgp"},
+			},
+			"bandwidth": []interface{}{
+				map[string]interface{}{
+					"share_type":  "PER",
+					"name":        name,
+					"size":        size,
+					"charge_mode": "traffic",
+				},
+			},
+			"tags": tags,
+		}
+	}
+	mock.Lifecycle{
+		ResourceType: "huaweicloud_vpc_eip",
+		Steps: []mock.Step{
+			{
+				Config: eipConfig("eip-mock", 5, map[string]interface{}{"foo": "bar"}),
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "UNBOUND", state.Attributes["status"])
+					th.AssertEquals(t, server.Resource("publicips", state.ID)["public_ip_address"],
+						state.Attributes["address"])
+				},
+			},
+			{
+				Config: eipConfig("eip-mock-updated", 8, map[string]interface{}{"foo": "bar_updated"}),
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "eip-mock-updated", state.Attributes["bandwidth.0.name"])
+					th.AssertEquals(t, "8", state.Attributes["bandwidth.0.size"])
+				},
+			},
+		},
+	}.Run(t, server)
+}
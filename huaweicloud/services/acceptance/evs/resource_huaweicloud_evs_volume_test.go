@@ -8,9 +8,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/chnsz/golangsdk/openstack/evs/v2/cloudvolumes"
+	th "github.com/chnsz/golangsdk/testhelper"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance/mock"
 )
 
 func getVolumeResourceFunc(conf *config.Config, state *terraform.ResourceState) (interface{}, error) {
@@ -734,3 +736,128 @@ resource "huaweicloud_evs_volume" "test" {
 }
 `, rName, acceptance.HW_EVS_AVAILABILITY_ZONE_ESSD2)
 }
+
+func TestAccEvsVolume_mock(t *testing.T) {
+	server := mock.NewServer(t)
+	rName := acceptance.RandomAccResourceName()
+	resourceName := "huaweicloud_evs_volume.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { mock.PreCheck(t) },
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      server.CheckDestroy("huaweicloud_evs_volume", "cloudvolumes"),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + testAccEvsVolume_mock(rName, 100),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", rName),
+					resource.TestCheckResourceAttr(resourceName, "size", "100"),
+					resource.TestCheckResourceAttrSet(resourceName, "wwn"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "bar"),
+				),
+			},
+			{
+				Config: server.ProviderConfig() + testAccEvsVolume_mock(rName, 200),
+				Check:  resource.TestCheckResourceAttr(resourceName, "size", "200"),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"cascade"},
+			},
+		},
+	})
+}
+
+func TestEvsVolume_mockLifecycle(t *testing.T) {
+	t.Parallel()
+
+	server := mock.NewServer(t)
+	volumeConfig := func(name string, size int) map[string]interface{} {
+		return map[string]interface{}{
+			"name":              name,
+			"availability_zone": mock.AvailabilityZone,
+			"volume_type":       "GPSSD",
+			"size":              size,
+			"description":       "created by mock test",
+			"tags":              map[string]interface{}{"foo": "bar"},
+		}
+	}
+	mock.Lifecycle{
+		ResourceType: "huaweicloud_evs_volume",
+		Steps: []mock.Step{
+			{
+				Config: volumeConfig("volume-mock", 100),
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "100", state.Attributes["size"])
+					th.AssertEquals(t, server.Resource("cloudvolumes", state.ID)["wwn"], state.Attributes["wwn"])
+				},
+			},
+			{
+				Config: volumeConfig("volume-mock-updated", 200),
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "volume-mock-updated", state.Attributes["name"])
+					th.AssertEquals(t, float64(200), server.Resource("cloudvolumes", state.ID)["size"])
+				},
+			},
+		},
+		ImportStateVerifyIgnore: []string{"cascade"},
+	}.Run(t, server)
+}
+
+func testAccEvsVolume_mock(rName string, size int) string {
+	return fmt.Sprintf(`
+resource "huaweicloud_evs_volume" "test" {
+  availability_zone = "%[1]s"
+  name              = "%[2]s"
+  volume_type       = "GPSSD"
+  size              = %[3]d
+  description       = "Created by acc test script."
+
+  tags = {
+    foo = "bar"
+  }
+}
+`, mock.AvailabilityZone, rName, size)
+}
+
+func TestEvsVolume_mockPrePaidLifecycle(t *testing.T) {
+	t.Parallel()
+
+	server := mock.NewServer(t)
+	volumeConfig := func(size int, autoRenew string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":              "volume-mock",
+			"availability_zone": mock.AvailabilityZone,
+			"volume_type":       "SSD",
+			"size":              size,
+			"charging_mode":     "prePaid",
+			"period_unit":       "month",
+			"period":            1,
+			"auto_renew":        autoRenew,
+		}
+	}
+	mock.Lifecycle{
+		ResourceType: "huaweicloud_evs_volume",
+		Steps: []mock.Step{
+			{
+				Config: volumeConfig(100, "false"),
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "prePaid", state.Attributes["charging_mode"])
+				},
+			},
+			{
+				Config: volumeConfig(200, "true"),
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "200", state.Attributes["size"])
+					th.AssertEquals(t, true, server.Resource("cloudvolumes", state.ID)["auto_renew"])
+				},
+			},
+		},
+		ImportStateVerifyIgnore: []string{"cascade", "period_unit", "period", "auto_renew"},
+	}.Run(t, server)
+
+	// the volume is unsubscribed through the BSS API
+	th.AssertEquals(t, 0, server.ResourceCount("cloudvolumes"))
+}
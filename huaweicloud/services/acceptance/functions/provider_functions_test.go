@@ -0,0 +1,73 @@
+package functions
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance/mock"
+)
+
+// The provider-defined functions do not call any API, so the tests run without the credentials, but Terraform 1.8
+// or later is required.
+func TestAccProviderFunctions_basic(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:                 func() { mock.PreCheck(t) },
+		ProtoV6ProviderFactories: acceptance.TestAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderFunctions_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("urn_service", "obs"),
+					resource.TestCheckOutput("urn_resource_path", "my-bucket/my-object"),
+					resource.TestCheckOutput("instance_id", "instance-id"),
+					resource.TestCheckOutput("vpc_endpoint", "https://vpc.cn-north-4.myhuaweicloud.com/"),
+					resource.TestCheckOutput("ecs_api_base",
+						"https://ecs.cn-north-4.myhuaweicloud.com/v1/0123456789abcdef/"),
+					resource.TestCheckOutput("cidr_valid", "true"),
+					resource.TestCheckOutput("gateway_ip", "192.168.0.1"),
+					resource.TestCheckOutput("bucket_domain", "my-bucket.obs.cn-north-4.myhuaweicloud.com"),
+				),
+			},
+		},
+	})
+}
+
+const testAccProviderFunctions_basic = `
+locals {
+  urn = provider::huaweicloud::parse_urn("obs:*:*:object:my-bucket/my-object")
+}
+
+output "urn_service" {
+  value = local.urn.service
+}
+
+output "urn_resource_path" {
+  value = local.urn.resource_path
+}
+
+output "instance_id" {
+  value = provider::huaweicloud::parse_resource_id("instance-id/db-id", 2)[0]
+}
+
+output "vpc_endpoint" {
+  value = provider::huaweicloud::service_endpoint("vpc", "cn-north-4")
+}
+
+output "ecs_api_base" {
+  value = provider::huaweicloud::project_endpoint("ecs", "cn-north-4", "0123456789abcdef")
+}
+
+output "cidr_valid" {
+  value = provider::huaweicloud::validate_cidr("192.168.0.0/16")
+}
+
+output "gateway_ip" {
+  value = provider::huaweicloud::expand_cidr("192.168.0.0/24").gateway_ip
+}
+
+output "bucket_domain" {
+  value = provider::huaweicloud::obs_bucket_domain("my-bucket", "cn-north-4")
+}
+`
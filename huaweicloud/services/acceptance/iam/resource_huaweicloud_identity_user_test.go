@@ -8,9 +8,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/chnsz/golangsdk/openstack/identity/v3.0/users"
+	th "github.com/chnsz/golangsdk/testhelper"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance/mock"
 )
 
 func getIdentityUserResourceFunc(c *config.Config, state *terraform.ResourceState) (interface{}, error) {
@@ -189,3 +191,79 @@ resource "huaweicloud_identity_user" "user_1" {
 }
 `, name, password, xUserID)
 }
+
+func TestAccIdentityUser_mock(t *testing.T) {
+	server := mock.NewServer(t)
+	userName := acceptance.RandomAccResourceName()
+	password := acceptance.RandomPassword()
+	resourceName := "huaweicloud_identity_user.user_1"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { mock.PreCheck(t) },
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      server.CheckDestroy("huaweicloud_identity_user", "users"),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + testAccIdentityUser_basic(userName, password),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", userName),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "true"),
+					resource.TestCheckResourceAttr(resourceName, "login_protect_verification_method", "email"),
+				),
+			},
+			{
+				Config: server.ProviderConfig() + testAccIdentityUser_update(userName, password),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "enabled", "false"),
+					resource.TestCheckResourceAttr(resourceName, "description", "updated by terraform"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+		},
+	})
+}
+
+func TestIdentityUser_mockLifecycle(t *testing.T) {
+	t.Parallel()
+
+	server := mock.NewServer(t)
+	mock.Lifecycle{
+		ResourceType: "huaweicloud_identity_user",
+		Steps: []mock.Step{
+			{
+				Config: map[string]interface{}{
+					"name":                              "user-mock",
+					"password":                          "Mock-Password@123",
+					"email":                             "user-mock@example.com",
+					"description":                       "created by mock test",
+					"login_protect_verification_method": "email",
+				},
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "true", state.Attributes["enabled"])
+					th.AssertEquals(t, "Strong", state.Attributes["password_strength"])
+					th.AssertEquals(t, nil, server.Resource("users", state.ID)["password"])
+				},
+			},
+			{
+				Config: map[string]interface{}{
+					"name":        "user-mock",
+					"password":    "Mock-Password@123",
+					"email":       "user-mock@example.org",
+					"description": "updated by mock test",
+					"enabled":     false,
+				},
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "false", state.Attributes["enabled"])
+					th.AssertEquals(t, "user-mock@example.org", state.Attributes["email"])
+					th.AssertEquals(t, "", state.Attributes["login_protect_verification_method"])
+				},
+			},
+		},
+		ImportStateVerifyIgnore: []string{"password"},
+	}.Run(t, server)
+}
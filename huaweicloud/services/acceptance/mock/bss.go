@@ -0,0 +1,124 @@
+package mock
+
+import (
+	"net/http"
+)
+
+// the order status which means the order is completed
+const orderStatusCompleted = 5
+
+func (s *Server) registerBSS() {
+	s.handle("GET", "/v2/products/service-types", func(r *request) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"service_types": []interface{}{}, "total_count": 0}
+	})
+	s.handle("GET", "/v2/orders/customer-orders/details/{order_id}", s.getOrder)
+	// the typo is in the real path
+	s.handle("POST", "/v2/orders/suscriptions/resources/query", s.listOrderResources)
+	s.handle("POST", "/v2/orders/subscriptions/resources/unsubscribe", s.unsubscribeResources)
+	s.handle("POST", "/v2/orders/subscriptions/resources/autorenew/{resource_id}", s.setAutoRenew(true))
+	s.handle("DELETE", "/v2/orders/subscriptions/resources/autorenew/{resource_id}", s.setAutoRenew(false))
+}
+
+// newOrder creates a completed order of the prePaid resources and returns the order ID, the first resource is the
+// main resource.
+func (s *Server) newOrder(resourceIDs ...string) string {
+	order := s.create("orders", map[string]interface{}{
+		"status":       orderStatusCompleted,
+		"resource_ids": resourceIDs,
+	})
+	return order["id"].(string)
+}
+
+func (s *Server) getOrder(r *request) (int, interface{}) {
+	order := s.Resource("orders", r.Param("order_id"))
+	if order == nil {
+		return notFound("order", r.Param("order_id"))
+	}
+
+	return http.StatusOK, map[string]interface{}{
+		"order_info": map[string]interface{}{
+			"order_id": order["id"],
+			"status":   order["status"],
+		},
+	}
+}
+
+func (s *Server) listOrderResources(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	var ids []interface{}
+	if orderID, _ := body["order_id"].(string); orderID != "" {
+		if order := s.Resource("orders", orderID); order != nil {
+			ids, _ = order["resource_ids"].([]interface{})
+		}
+		if onlyMain, _ := body["only_main_resource"].(float64); onlyMain == 1 && len(ids) > 1 {
+			ids = ids[:1]
+		}
+	} else {
+		ids, _ = body["resource_ids"].([]interface{})
+	}
+
+	data := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		data = append(data, map[string]interface{}{
+			"resource_id": id,
+			"region_code": Region,
+			"status":      2,
+		})
+	}
+	return http.StatusOK, map[string]interface{}{"data": data, "total_count": len(data)}
+}
+
+func (s *Server) unsubscribeResources(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	ids, _ := body["resource_ids"].([]interface{})
+	for _, id := range ids {
+		// the ports and the volumes of the server are released together
+		if !s.deleteServer(id.(string), true) {
+			s.deleteAnyKind(id.(string))
+		}
+	}
+	return http.StatusOK, map[string]interface{}{"order_ids": []string{s.newOrder()}}
+}
+
+func (s *Server) setAutoRenew(enabled bool) handlerFunc {
+	return func(r *request) (int, interface{}) {
+		if !s.setField(r.Param("resource_id"), "auto_renew", enabled) {
+			return notFound("resource", r.Param("resource_id"))
+		}
+		return http.StatusNoContent, nil
+	}
+}
+
+// deleteAnyKind deletes the resource with the ID regardless of its kind, it's used to unsubscribe the prePaid
+// resources.
+func (s *Server) deleteAnyKind(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, objects := range s.resources {
+		delete(objects, id)
+	}
+	delete(s.tags, id)
+}
+
+// setField sets the field of the resource with the ID regardless of its kind.
+func (s *Server) setField(id, key string, value interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, objects := range s.resources {
+		if object, ok := objects[id]; ok {
+			object[key] = value
+			return true
+		}
+	}
+	return false
+}
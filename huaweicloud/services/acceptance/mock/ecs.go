@@ -0,0 +1,470 @@
+package mock
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// ImageID is the ID of the public image which can be used to create the ECS instances
+	ImageID = "67f433d8-ed0e-4321-a8a2-a71838539e09"
+	// ImageName is the name of the public image which can be used to create the ECS instances
+	ImageName = "Ubuntu 22.04 server 64bit"
+	// FlavorID is the flavor which can be used to create the ECS instances, any flavor is accepted by the mock server
+	FlavorID = "s6.small.1"
+)
+
+func (s *Server) registerECS() {
+	s.create("images", map[string]interface{}{
+		"id":                    ImageID,
+		"name":                  ImageName,
+		"status":                "active",
+		"visibility":            "public",
+		"min_disk":              40,
+		"min_ram":               0,
+		"disk_format":           "zvhd2",
+		"__os_type":             "Linux",
+		"__os_version":          "Ubuntu 22.04 server 64bit",
+		"__platform":            "Ubuntu",
+		"__imagetype":           "gold",
+		"__os_bit":              "64",
+		"enterprise_project_id": "0",
+	})
+	s.handle("GET", "/v2/cloudimages", s.listImages)
+
+	s.handle("POST", "/v1.1/{project_id}/cloudservers", s.createServer)
+	s.handle("GET", "/v1/{project_id}/cloudservers/{server_id}", s.getServer)
+	s.handle("PUT", "/v1/{project_id}/cloudservers/{server_id}", s.updateServer)
+	s.handle("POST", "/v1/{project_id}/cloudservers/delete", s.deleteServers)
+	s.handle("POST", "/v1/{project_id}/cloudservers/action", s.serverPowerAction)
+	s.handle("POST", "/v1/{project_id}/cloudservers/{server_id}/metadata", s.updateServerMetadata)
+	s.handle("DELETE", "/v1/{project_id}/cloudservers/{server_id}/metadata/{key}", s.deleteServerMetadata)
+	s.handle("GET", "/v1/{project_id}/cloudservers/{server_id}/block_device/{volume_id}", s.getBlockDevice)
+}
+
+func (s *Server) listImages(r *request) (int, interface{}) {
+	query := r.URL.Query()
+	images := afterMarker(s.list("images", func(image map[string]interface{}) bool {
+		if id := query.Get("id"); id != "" && image["id"] != id {
+			return false
+		}
+		if name := query.Get("name"); name != "" && image["name"] != name {
+			return false
+		}
+		return true
+	}), query.Get("marker"))
+	return http.StatusOK, map[string]interface{}{"images": images}
+}
+
+func (s *Server) createServer(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	opts, _ := body["server"].(map[string]interface{})
+	server, err := s.newServer(opts)
+	if err != nil {
+		return badRequest(err)
+	}
+	id := server["id"].(string)
+
+	if extendParam, _ := opts["extendparam"].(map[string]interface{}); extendParam["chargingMode"] == "prePaid" {
+		resourceIDs := []string{id}
+		for _, volume := range server["os-extended-volumes:volumes_attached"].([]interface{}) {
+			resourceIDs = append(resourceIDs, volume.(map[string]interface{})["id"].(string))
+		}
+		orderID := s.newOrder(resourceIDs...)
+		s.update("cloudservers", id, map[string]interface{}{
+			"metadata": mergeObject(server["metadata"], map[string]interface{}{
+				"charging_mode":     "1",
+				"metering.order_id": orderID,
+			}),
+		})
+		return http.StatusOK, map[string]interface{}{"order_id": orderID, "serverIds": []string{id}}
+	}
+
+	jobID := s.newJob("createServer", map[string]interface{}{
+		"sub_jobs_total": 1,
+		"sub_jobs": []interface{}{
+			map[string]interface{}{
+				"status":   "SUCCESS",
+				"job_type": "createSingleServer",
+				"entities": map[string]interface{}{"server_id": id},
+			},
+		},
+	})
+	return http.StatusOK, map[string]interface{}{"job_id": jobID, "serverIds": []string{id}}
+}
+
+// newServer creates an active server with the options of the create API, the NICs and the volumes are created
+// together.
+func (s *Server) newServer(opts map[string]interface{}) (map[string]interface{}, error) {
+	imageID, _ := opts["imageRef"].(string)
+	if s.Resource("images", imageID) == nil {
+		return nil, fmt.Errorf("the image (%s) does not exist", imageID)
+	}
+	vpcID, _ := opts["vpcid"].(string)
+	if s.Resource("vpcs", vpcID) == nil {
+		return nil, fmt.Errorf("the VPC (%s) does not exist", vpcID)
+	}
+
+	var securityGroups []interface{}
+	groupOpts, _ := opts["security_groups"].([]interface{})
+	for _, raw := range groupOpts {
+		groupID, _ := raw.(map[string]interface{})["id"].(string)
+		group := s.Resource("security-groups", groupID)
+		if group == nil {
+			return nil, fmt.Errorf("the security group (%s) does not exist", groupID)
+		}
+		securityGroups = append(securityGroups, map[string]interface{}{"id": groupID, "name": group["name"]})
+	}
+
+	id := newID()
+	name, _ := opts["name"].(string)
+	az, _ := opts["availability_zone"].(string)
+	if az == "" {
+		az = AvailabilityZone
+	}
+
+	var addresses []interface{}
+	nics, _ := opts["nics"].([]interface{})
+	for _, raw := range nics {
+		nic := raw.(map[string]interface{})
+		subnetID, _ := nic["subnet_id"].(string)
+		ipAddress, _ := nic["ip_address"].(string)
+		port, err := s.newPort(subnetID, ipAddress, id)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, map[string]interface{}{
+			"version":                 "4",
+			"addr":                    port["fixed_ips"].([]interface{})[0].(map[string]interface{})["ip_address"],
+			"OS-EXT-IPS-MAC:mac_addr": port["mac_address"],
+			"OS-EXT-IPS:port_id":      port["id"],
+			"OS-EXT-IPS:type":         "fixed",
+		})
+	}
+
+	if publicIp, _ := opts["publicip"].(map[string]interface{}); publicIp["id"] != nil && len(addresses) > 0 {
+		portID := addresses[0].(map[string]interface{})["OS-EXT-IPS:port_id"]
+		s.update("publicips", publicIp["id"].(string), map[string]interface{}{
+			"port_id":            portID,
+			"private_ip_address": addresses[0].(map[string]interface{})["addr"],
+			"status":             "ACTIVE",
+		})
+	}
+
+	// the system disk is the first volume, then the data disks
+	volumeOpts := []interface{}{opts["root_volume"]}
+	if dataVolumes, ok := opts["data_volumes"].([]interface{}); ok {
+		volumeOpts = append(volumeOpts, dataVolumes...)
+	}
+	epsID := "0"
+	if extendParam, _ := opts["extendparam"].(map[string]interface{}); extendParam["enterprise_project_id"] != nil {
+		epsID = extendParam["enterprise_project_id"].(string)
+	}
+
+	var volumes []interface{}
+	for i, raw := range volumeOpts {
+		volumeOpt, _ := raw.(map[string]interface{})
+		size := volumeOpt["size"]
+		if size == nil {
+			size = float64(40)
+		}
+		createOpts := map[string]interface{}{
+			"name":                  fmt.Sprintf("%s-volume-%04d", name, i),
+			"size":                  size,
+			"volume_type":           volumeOpt["volumetype"],
+			"availability_zone":     az,
+			"iops":                  volumeOpt["iops"],
+			"throughput":            volumeOpt["throughput"],
+			"multiattach":           volumeOpt["multiattach"],
+			"enterprise_project_id": epsID,
+		}
+		if i == 0 {
+			createOpts["imageRef"] = imageID
+		}
+		volume, err := s.newVolume(createOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		device := fmt.Sprintf("/dev/vd%c", 'a'+i)
+		s.update("cloudvolumes", volume["id"].(string), map[string]interface{}{
+			"status": "in-use",
+			"attachments": []interface{}{
+				map[string]interface{}{
+					"attachment_id": newID(),
+					"id":            volume["id"],
+					"volume_id":     volume["id"],
+					"server_id":     id,
+					"device":        device,
+				},
+			},
+		})
+		volumes = append(volumes, map[string]interface{}{
+			"id":                    volume["id"],
+			"bootIndex":             fmt.Sprint(i),
+			"device":                device,
+			"delete_on_termination": fmt.Sprint(i == 0),
+		})
+	}
+
+	metadata := map[string]interface{}{
+		"charging_mode":     "0",
+		"vpc_id":            vpcID,
+		"metering.image_id": imageID,
+		"image_name":        ImageName,
+	}
+	if raw, ok := opts["metadata"].(map[string]interface{}); ok {
+		if v, ok := raw["agency_name"]; ok {
+			metadata["agency_name"] = v
+		}
+		if v, ok := raw["__support_agent_list"]; ok {
+			metadata["__support_agent_list"] = v
+		}
+	}
+
+	schedulerHints := map[string]interface{}{}
+	if hints, ok := opts["os:scheduler_hints"].(map[string]interface{}); ok && hints["group"] != nil {
+		schedulerHints["group"] = []interface{}{hints["group"]}
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	server := s.create("cloudservers", map[string]interface{}{
+		"id":                                   id,
+		"name":                                 name,
+		"description":                          opts["description"],
+		"status":                               "ACTIVE",
+		"created":                              now,
+		"updated":                              now,
+		"addresses":                            map[string]interface{}{vpcID: addresses},
+		"flavor":                               map[string]interface{}{"id": opts["flavorRef"], "name": opts["flavorRef"], "vcpus": "1", "ram": "1024", "disk": "0"},
+		"image":                                map[string]interface{}{"id": imageID},
+		"key_name":                             opts["key_name"],
+		"metadata":                             metadata,
+		"security_groups":                      securityGroups,
+		"enterprise_project_id":                epsID,
+		"tenant_id":                            s.ProjectID,
+		"OS-EXT-AZ:availability_zone":          az,
+		"OS-EXT-SRV-ATTR:hostname":             strings.ToLower(name),
+		"OS-EXT-STS:vm_state":                  "active",
+		"os-extended-volumes:volumes_attached": volumes,
+		"os:scheduler_hints":                   schedulerHints,
+		"auto_terminate_time":                  opts["auto_terminate_time"],
+	})
+	if tags := parseTagList(opts["server_tags"]); len(tags) > 0 {
+		s.setTags(id, tags)
+	}
+	return server, nil
+}
+
+// server returns the server with its tags and the EIPs which are bound to its ports.
+func (s *Server) server(id string) map[string]interface{} {
+	server := s.Resource("cloudservers", id)
+	if server == nil {
+		return nil
+	}
+	server["tags"] = s.tagStrings(id)
+
+	addresses, _ := server["addresses"].(map[string]interface{})
+	for network, raw := range addresses {
+		list, _ := raw.([]interface{})
+		var result []interface{}
+		for _, item := range list {
+			result = append(result, item)
+			portID := item.(map[string]interface{})["OS-EXT-IPS:port_id"]
+			for _, publicIp := range s.list("publicips", func(publicIp map[string]interface{}) bool {
+				return publicIp["port_id"] == portID
+			}) {
+				result = append(result, map[string]interface{}{
+					"version":            "4",
+					"addr":               publicIp.(map[string]interface{})["public_ip_address"],
+					"OS-EXT-IPS:port_id": portID,
+					"OS-EXT-IPS:type":    "floating",
+				})
+			}
+		}
+		addresses[network] = result
+	}
+	return server
+}
+
+func (s *Server) getServer(r *request) (int, interface{}) {
+	server := s.server(r.Param("server_id"))
+	if server == nil {
+		return notFound("server", r.Param("server_id"))
+	}
+	return http.StatusOK, map[string]interface{}{"server": server}
+}
+
+func (s *Server) updateServer(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	opts, _ := body["server"].(map[string]interface{})
+	fields := map[string]interface{}{"updated": time.Now().UTC().Format(time.RFC3339)}
+	for _, key := range []string{"name", "description"} {
+		if v, ok := opts[key]; ok {
+			fields[key] = v
+		}
+	}
+	if v, ok := opts["hostname"]; ok {
+		fields["OS-EXT-SRV-ATTR:hostname"] = v
+	}
+
+	if s.update("cloudservers", r.Param("server_id"), fields) == nil {
+		return notFound("server", r.Param("server_id"))
+	}
+	return http.StatusOK, map[string]interface{}{"server": s.server(r.Param("server_id"))}
+}
+
+func (s *Server) updateServerMetadata(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	server := s.Resource("cloudservers", r.Param("server_id"))
+	if server == nil {
+		return notFound("server", r.Param("server_id"))
+	}
+	metadata := mergeObject(server["metadata"], body["metadata"])
+	s.update("cloudservers", r.Param("server_id"), map[string]interface{}{"metadata": metadata})
+	return http.StatusOK, map[string]interface{}{"metadata": metadata}
+}
+
+func (s *Server) deleteServerMetadata(r *request) (int, interface{}) {
+	server := s.Resource("cloudservers", r.Param("server_id"))
+	if server == nil {
+		return notFound("server", r.Param("server_id"))
+	}
+	metadata, _ := server["metadata"].(map[string]interface{})
+	delete(metadata, r.Param("key"))
+	s.update("cloudservers", r.Param("server_id"), map[string]interface{}{"metadata": metadata})
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) serverPowerAction(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	statuses := map[string]string{"os-start": "ACTIVE", "os-stop": "SHUTOFF", "reboot": "ACTIVE"}
+	for action, status := range statuses {
+		opts, ok := body[action].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		servers, _ := opts["servers"].([]interface{})
+		for _, raw := range servers {
+			id, _ := raw.(map[string]interface{})["id"].(string)
+			if s.update("cloudservers", id, map[string]interface{}{"status": status}) == nil {
+				return notFound("server", id)
+			}
+		}
+		return http.StatusOK, map[string]interface{}{"job_id": s.newJob(action, map[string]interface{}{})}
+	}
+	return badRequest(fmt.Errorf("unsupported action: %s", r.body))
+}
+
+func (s *Server) deleteServers(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	servers, _ := body["servers"].([]interface{})
+	deleteVolume, _ := body["delete_volume"].(bool)
+	for _, raw := range servers {
+		id, _ := raw.(map[string]interface{})["id"].(string)
+		if !s.deleteServer(id, deleteVolume) {
+			return notFound("server", id)
+		}
+	}
+	return http.StatusOK, map[string]interface{}{"job_id": s.newJob("deleteServer", map[string]interface{}{})}
+}
+
+// deleteServer deletes the server and its ports, the system disk is always deleted, and the data disks are deleted
+// if deleteVolume is true, otherwise they're detached.
+func (s *Server) deleteServer(id string, deleteVolume bool) bool {
+	server := s.Resource("cloudservers", id)
+	if server == nil {
+		return false
+	}
+
+	for _, port := range s.list("ports", func(port map[string]interface{}) bool {
+		return port["device_id"] == id
+	}) {
+		portID := port.(map[string]interface{})["id"]
+		for _, publicIp := range s.list("publicips", func(publicIp map[string]interface{}) bool {
+			return publicIp["port_id"] == portID
+		}) {
+			s.update("publicips", publicIp.(map[string]interface{})["id"].(string),
+				map[string]interface{}{"port_id": "", "private_ip_address": "", "status": "DOWN"})
+		}
+		s.delete("ports", portID.(string))
+	}
+
+	volumes, _ := server["os-extended-volumes:volumes_attached"].([]interface{})
+	for _, raw := range volumes {
+		volume := raw.(map[string]interface{})
+		if deleteVolume || volume["bootIndex"] == "0" {
+			s.delete("cloudvolumes", volume["id"].(string))
+		} else {
+			s.update("cloudvolumes", volume["id"].(string),
+				map[string]interface{}{"status": "available", "attachments": []interface{}{}})
+		}
+	}
+	return s.delete("cloudservers", id)
+}
+
+func (s *Server) getBlockDevice(r *request) (int, interface{}) {
+	server := s.Resource("cloudservers", r.Param("server_id"))
+	if server == nil {
+		return notFound("server", r.Param("server_id"))
+	}
+
+	volumes, _ := server["os-extended-volumes:volumes_attached"].([]interface{})
+	for i, raw := range volumes {
+		volume := raw.(map[string]interface{})
+		if volume["id"] != r.Param("volume_id") {
+			continue
+		}
+		size := float64(0)
+		if v := s.Resource("cloudvolumes", r.Param("volume_id")); v != nil {
+			size, _ = v["size"].(float64)
+		}
+		return http.StatusOK, map[string]interface{}{
+			"volumeAttachment": map[string]interface{}{
+				"id":         volume["id"],
+				"serverId":   server["id"],
+				"volumeId":   volume["id"],
+				"device":     volume["device"],
+				"size":       size,
+				"bootIndex":  i,
+				"pciAddress": fmt.Sprintf("0000:02:%02d.0", i+1),
+				"bus":        "virtio",
+			},
+		}
+	}
+	return notFound("block device", r.Param("volume_id"))
+}
+
+// mergeObject returns a new object with the fields of both objects, the fields of the second one take precedence.
+func mergeObject(first, second interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, raw := range []interface{}{first, second} {
+		object, _ := raw.(map[string]interface{})
+		for k, v := range object {
+			result[k] = v
+		}
+	}
+	return result
+}
@@ -0,0 +1,197 @@
+package mock
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func (s *Server) registerEIP() {
+	s.handle("POST", "/v1/{project_id}/publicips", s.createPublicIp(false))
+	s.handle("POST", "/v2.0/{project_id}/publicips", s.createPublicIp(true))
+	s.handle("GET", "/v1/{project_id}/publicips/{publicip_id}", s.getPublicIp)
+	s.handle("PUT", "/v1/{project_id}/publicips/{publicip_id}", s.updatePublicIp)
+	s.handle("DELETE", "/v1/{project_id}/publicips/{publicip_id}", s.deletePublicIp)
+	s.handle("GET", "/v3/{project_id}/eip/publicips/{publicip_id}", s.getPublicIpV3)
+
+	s.handle("GET", "/v1/{project_id}/bandwidths/{bandwidth_id}", func(r *request) (int, interface{}) {
+		bandwidth := s.Resource("bandwidths", r.Param("bandwidth_id"))
+		if bandwidth == nil {
+			return notFound("bandwidth", r.Param("bandwidth_id"))
+		}
+		return http.StatusOK, map[string]interface{}{"bandwidth": bandwidth}
+	})
+	s.handle("PUT", "/v1/{project_id}/bandwidths/{bandwidth_id}", s.updateBandwidth)
+}
+
+func (s *Server) createPublicIp(prePaid bool) handlerFunc {
+	return func(r *request) (int, interface{}) {
+		body, err := r.decode()
+		if err != nil {
+			return badRequest(err)
+		}
+
+		opts, _ := body["publicip"].(map[string]interface{})
+		bandwidthOpts, _ := body["bandwidth"].(map[string]interface{})
+		epsID, _ := body["enterprise_project_id"].(string)
+		if epsID == "" {
+			epsID = "0"
+		}
+
+		bandwidth := s.Resource("bandwidths", fmt.Sprint(bandwidthOpts["id"]))
+		if bandwidth == nil {
+			chargeMode, _ := bandwidthOpts["charge_mode"].(string)
+			if chargeMode == "" {
+				chargeMode = "bandwidth"
+			}
+			bandwidth = s.create("bandwidths", map[string]interface{}{
+				"name":                  bandwidthOpts["name"],
+				"size":                  bandwidthOpts["size"],
+				"share_type":            bandwidthOpts["share_type"],
+				"charge_mode":           chargeMode,
+				"bandwidth_type":        "bgp",
+				"enterprise_project_id": epsID,
+				"status":                "NORMAL",
+			})
+		}
+
+		ipVersion := opts["ip_version"]
+		if ipVersion == nil {
+			ipVersion = 4
+		}
+		address, _ := opts["ip_address"].(string)
+		if address == "" {
+			address = fmt.Sprintf("100.85.%d.%d", s.ResourceCount("publicips")/250, s.ResourceCount("publicips")%250+2)
+		}
+		publicIp := map[string]interface{}{
+			"status":                "DOWN",
+			"type":                  opts["type"],
+			"alias":                 opts["alias"],
+			"ip_version":            ipVersion,
+			"public_ip_address":     address,
+			"bandwidth_id":          bandwidth["id"],
+			"bandwidth_name":        bandwidth["name"],
+			"bandwidth_size":        bandwidth["size"],
+			"bandwidth_share_type":  bandwidth["share_type"],
+			"enterprise_project_id": epsID,
+			"tenant_id":             s.ProjectID,
+			"create_time":           time.Now().UTC().Format("2006-01-02 15:04:05"),
+		}
+		if prePaid {
+			publicIp["id"] = newID()
+			publicIp["profile"] = map[string]interface{}{"order_id": s.newOrder(publicIp["id"].(string))}
+		}
+		publicIp = s.create("publicips", publicIp)
+
+		if prePaid {
+			profile := publicIp["profile"].(map[string]interface{})
+			return http.StatusOK, map[string]interface{}{"order_id": profile["order_id"], "publicip_id": publicIp["id"]}
+		}
+		return http.StatusOK, map[string]interface{}{"publicip": publicIp}
+	}
+}
+
+func (s *Server) getPublicIp(r *request) (int, interface{}) {
+	publicIp := s.Resource("publicips", r.Param("publicip_id"))
+	if publicIp == nil {
+		return notFound("EIP", r.Param("publicip_id"))
+	}
+	publicIp["tags"] = s.tagStrings(r.Param("publicip_id"))
+	return http.StatusOK, map[string]interface{}{"publicip": publicIp}
+}
+
+func (s *Server) getPublicIpV3(r *request) (int, interface{}) {
+	publicIp := s.Resource("publicips", r.Param("publicip_id"))
+	if publicIp == nil {
+		return notFound("EIP", r.Param("publicip_id"))
+	}
+
+	result := map[string]interface{}{
+		"id":                    publicIp["id"],
+		"status":                publicIp["status"],
+		"alias":                 publicIp["alias"],
+		"ip_version":            publicIp["ip_version"],
+		"public_ip_address":     publicIp["public_ip_address"],
+		"enterprise_project_id": publicIp["enterprise_project_id"],
+		"created_at":            publicIp["create_time"],
+		"updated_at":            publicIp["create_time"],
+		"tags":                  s.tagStrings(r.Param("publicip_id")),
+	}
+	if portID, _ := publicIp["port_id"].(string); portID != "" {
+		result["associate_instance_type"] = "PORT"
+		result["associate_instance_id"] = portID
+		result["vnic"] = map[string]interface{}{
+			"private_ip_address": publicIp["private_ip_address"],
+			"port_id":            portID,
+		}
+	}
+	return http.StatusOK, map[string]interface{}{"publicip": result}
+}
+
+func (s *Server) updatePublicIp(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	id := r.Param("publicip_id")
+	if s.Resource("publicips", id) == nil {
+		return notFound("EIP", id)
+	}
+
+	fields, _ := body["publicip"].(map[string]interface{})
+	if len(fields) == 0 {
+		// the empty request body means unbinding the port
+		fields = map[string]interface{}{"port_id": "", "private_ip_address": "", "status": "DOWN"}
+	} else if portID, ok := fields["port_id"].(string); ok {
+		port := s.Resource("ports", portID)
+		if port == nil {
+			return notFound("port", portID)
+		}
+		fixedIPs, _ := port["fixed_ips"].([]interface{})
+		if len(fixedIPs) > 0 {
+			fields["private_ip_address"] = fixedIPs[0].(map[string]interface{})["ip_address"]
+		}
+		fields["status"] = "ACTIVE"
+	}
+	return http.StatusOK, map[string]interface{}{"publicip": s.update("publicips", id, fields)}
+}
+
+func (s *Server) deletePublicIp(r *request) (int, interface{}) {
+	publicIp := s.Resource("publicips", r.Param("publicip_id"))
+	if publicIp == nil {
+		return notFound("EIP", r.Param("publicip_id"))
+	}
+
+	s.delete("publicips", r.Param("publicip_id"))
+	if publicIp["bandwidth_share_type"] == "PER" {
+		s.delete("bandwidths", publicIp["bandwidth_id"].(string))
+	}
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) updateBandwidth(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	id := r.Param("bandwidth_id")
+	fields, _ := body["bandwidth"].(map[string]interface{})
+	bandwidth := s.update("bandwidths", id, fields)
+	if bandwidth == nil {
+		return notFound("bandwidth", id)
+	}
+
+	// the bandwidth details are also returned in the EIP APIs
+	for _, raw := range s.list("publicips", func(publicIp map[string]interface{}) bool {
+		return publicIp["bandwidth_id"] == id
+	}) {
+		publicIp := raw.(map[string]interface{})
+		s.update("publicips", publicIp["id"].(string), map[string]interface{}{
+			"bandwidth_name": bandwidth["name"],
+			"bandwidth_size": bandwidth["size"],
+		})
+	}
+	return http.StatusOK, map[string]interface{}{"bandwidth": bandwidth}
+}
@@ -0,0 +1,184 @@
+package mock
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func (s *Server) registerEVS() {
+	s.handle("POST", "/v2.1/{project_id}/cloudvolumes", s.createVolume)
+	s.handle("GET", "/v2/{project_id}/cloudvolumes/{volume_id}", s.getVolume)
+	s.handle("PUT", "/v2/{project_id}/cloudvolumes/{volume_id}", s.updateVolume)
+	s.handle("DELETE", "/v2/{project_id}/cloudvolumes/{volume_id}", s.deleteVolume)
+	s.handle("POST", "/v2.1/{project_id}/cloudvolumes/{volume_id}/action", s.extendVolume)
+
+	// the jobs of EVS and ECS are queried by the same API
+	s.handle("GET", "/v1/{project_id}/jobs/{job_id}", func(r *request) (int, interface{}) {
+		job := s.Resource("jobs", r.Param("job_id"))
+		if job == nil {
+			return notFound("job", r.Param("job_id"))
+		}
+		return http.StatusOK, job
+	})
+}
+
+// newJob creates a successful job with the entities.
+func (s *Server) newJob(jobType string, entities map[string]interface{}) string {
+	now := time.Now().UTC().Format(time.RFC3339)
+	job := s.create("jobs", map[string]interface{}{
+		"status":     "SUCCESS",
+		"job_type":   jobType,
+		"entities":   entities,
+		"begin_time": now,
+		"end_time":   now,
+	})
+	s.update("jobs", job["id"].(string), map[string]interface{}{"job_id": job["id"]})
+	return job["id"].(string)
+}
+
+func (s *Server) createVolume(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	opts, _ := body["volume"].(map[string]interface{})
+	volume, err := s.newVolume(opts)
+	if err != nil {
+		return badRequest(err)
+	}
+	id := volume["id"].(string)
+
+	chargeInfo, _ := body["bssParam"].(map[string]interface{})
+	if chargeInfo["chargingMode"] == "prePaid" {
+		orderID := s.newOrder(id)
+		s.update("cloudvolumes", id, map[string]interface{}{
+			"metadata": map[string]interface{}{"orderID": orderID},
+		})
+		return http.StatusOK, map[string]interface{}{"order_id": orderID, "volume_ids": []string{id}}
+	}
+
+	jobID := s.newJob("batchCreateVolumes", map[string]interface{}{
+		"volume_id":   id,
+		"name":        volume["name"],
+		"size":        volume["size"],
+		"volume_type": volume["volume_type"],
+	})
+	return http.StatusAccepted, map[string]interface{}{"job_id": jobID, "volume_ids": []string{id}}
+}
+
+// newVolume creates an available volume with the options of the create API.
+func (s *Server) newVolume(opts map[string]interface{}) (map[string]interface{}, error) {
+	size, _ := opts["size"].(float64)
+	imageID, _ := opts["imageRef"].(string)
+	if size == 0 && imageID == "" {
+		return nil, fmt.Errorf("the size of the volume must be specified")
+	}
+
+	id := newID()
+	metadata, _ := opts["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	imageMetadata := make(map[string]interface{})
+	if imageID != "" {
+		imageMetadata["image_id"] = imageID
+	}
+	epsID, _ := opts["enterprise_project_id"].(string)
+	if epsID == "" {
+		epsID = "0"
+	}
+
+	now := time.Now().UTC().Format("2006-01-02T15:04:05.000000")
+	volume := s.create("cloudvolumes", map[string]interface{}{
+		"id":                    id,
+		"name":                  opts["name"],
+		"status":                "available",
+		"size":                  size,
+		"description":           opts["description"],
+		"volume_type":           opts["volume_type"],
+		"availability_zone":     opts["availability_zone"],
+		"iops":                  map[string]interface{}{"id": newID(), "total_val": opts["iops"], "volume_id": id},
+		"throughput":            map[string]interface{}{"id": newID(), "total_val": opts["throughput"], "volume_id": id},
+		"attachments":           []interface{}{},
+		"volume_image_metadata": imageMetadata,
+		"snapshot_id":           opts["snapshot_id"],
+		"multiattach":           opts["multiattach"] == true,
+		"bootable":              fmt.Sprint(imageID != ""),
+		"wwn":                   fmt.Sprintf("688860300000%s", id[0:8]),
+		"enterprise_project_id": epsID,
+		"metadata":              metadata,
+		"created_at":            now,
+		"updated_at":            now,
+	})
+	if tags := parseTagMap(opts["tags"]); len(tags) > 0 {
+		s.setTags(id, tags)
+	}
+	return volume, nil
+}
+
+// volume returns the volume with its tags.
+func (s *Server) volume(id string) map[string]interface{} {
+	volume := s.Resource("cloudvolumes", id)
+	if volume != nil {
+		volume["tags"] = s.tagMap(id)
+	}
+	return volume
+}
+
+func (s *Server) getVolume(r *request) (int, interface{}) {
+	volume := s.volume(r.Param("volume_id"))
+	if volume == nil {
+		return notFound("volume", r.Param("volume_id"))
+	}
+	return http.StatusOK, map[string]interface{}{"volume": volume}
+}
+
+func (s *Server) updateVolume(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	fields, _ := body["volume"].(map[string]interface{})
+	if s.update("cloudvolumes", r.Param("volume_id"), fields) == nil {
+		return notFound("volume", r.Param("volume_id"))
+	}
+	return http.StatusOK, map[string]interface{}{"volume": s.volume(r.Param("volume_id"))}
+}
+
+func (s *Server) extendVolume(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	opts, _ := body["os-extend"].(map[string]interface{})
+	volume := s.update("cloudvolumes", r.Param("volume_id"), map[string]interface{}{"size": opts["new_size"]})
+	if volume == nil {
+		return notFound("volume", r.Param("volume_id"))
+	}
+
+	// both the job and the order are accepted with 202
+	if metadata, _ := volume["metadata"].(map[string]interface{}); metadata["orderID"] != nil {
+		return http.StatusAccepted, map[string]interface{}{"order_id": s.newOrder(r.Param("volume_id"))}
+	}
+	jobID := s.newJob("extendVolume", map[string]interface{}{"volume_id": r.Param("volume_id")})
+	return http.StatusAccepted, map[string]interface{}{"job_id": jobID}
+}
+
+func (s *Server) deleteVolume(r *request) (int, interface{}) {
+	volume := s.Resource("cloudvolumes", r.Param("volume_id"))
+	if volume == nil {
+		return notFound("volume", r.Param("volume_id"))
+	}
+	if attachments, _ := volume["attachments"].([]interface{}); len(attachments) > 0 {
+		return http.StatusBadRequest, errorBody("EVS.2024",
+			fmt.Sprintf("the volume (%s) is attached to the servers", r.Param("volume_id")))
+	}
+
+	s.delete("cloudvolumes", r.Param("volume_id"))
+	jobID := s.newJob("deleteVolume", map[string]interface{}{"volume_id": r.Param("volume_id")})
+	return http.StatusOK, map[string]interface{}{"job_id": jobID}
+}
@@ -0,0 +1,95 @@
+package mock
+
+import (
+	"net/http"
+	"time"
+)
+
+func (s *Server) registerIAM() {
+	s.handle("POST", "/v3.0/OS-USER/users", s.createUser)
+	s.handle("GET", "/v3.0/OS-USER/users/{user_id}", s.getUser)
+	s.handle("PUT", "/v3.0/OS-USER/users/{user_id}", s.updateUser)
+	s.handle("DELETE", "/v3/users/{user_id}", func(r *request) (int, interface{}) {
+		if !s.delete("users", r.Param("user_id")) {
+			return notFound("user", r.Param("user_id"))
+		}
+		return http.StatusNoContent, nil
+	})
+	s.handle("GET", "/v3.0/OS-USER/users/{user_id}/login-protect", s.getLoginProtect)
+	s.handle("PUT", "/v3.0/OS-USER/users/{user_id}/login-protect", s.updateLoginProtect)
+}
+
+func (s *Server) createUser(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	user, _ := body["user"].(map[string]interface{})
+	// the password is never returned
+	delete(user, "password")
+	user["domain_id"] = s.DomainID
+	user["pwd_strength"] = "Strong"
+	user["create_time"] = time.Now().UTC().Format("2006-01-02 15:04:05.000000")
+	user["last_login_time"] = ""
+	if _, ok := user["enabled"]; !ok {
+		user["enabled"] = true
+	}
+	if _, ok := user["pwd_status"]; !ok {
+		user["pwd_status"] = true
+	}
+	return http.StatusCreated, map[string]interface{}{"user": s.create("users", user)}
+}
+
+func (s *Server) getUser(r *request) (int, interface{}) {
+	user := s.Resource("users", r.Param("user_id"))
+	if user == nil {
+		return notFound("user", r.Param("user_id"))
+	}
+	return http.StatusOK, map[string]interface{}{"user": user}
+}
+
+func (s *Server) updateUser(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	fields, _ := body["user"].(map[string]interface{})
+	delete(fields, "password")
+	user := s.update("users", r.Param("user_id"), fields)
+	if user == nil {
+		return notFound("user", r.Param("user_id"))
+	}
+	return http.StatusOK, map[string]interface{}{"user": user}
+}
+
+func (s *Server) getLoginProtect(r *request) (int, interface{}) {
+	user := s.Resource("users", r.Param("user_id"))
+	if user == nil {
+		return notFound("user", r.Param("user_id"))
+	}
+
+	protect, ok := user["login_protect"]
+	if !ok {
+		protect = map[string]interface{}{"enabled": false, "verification_method": "none"}
+	}
+	return http.StatusOK, map[string]interface{}{"login_protect": protect}
+}
+
+func (s *Server) updateLoginProtect(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	protect, _ := body["login_protect"].(map[string]interface{})
+	if protect["enabled"] == false {
+		// the verification method is none if the login protection is disabled
+		protect["verification_method"] = "none"
+	}
+	if s.update("users", r.Param("user_id"), map[string]interface{}{"login_protect": protect}) == nil {
+		return notFound("user", r.Param("user_id"))
+	}
+	return http.StatusOK, map[string]interface{}{"login_protect": protect}
+}
@@ -0,0 +1,282 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud"
+)
+
+// PreCheck skips the test which runs the terraform CLI against the mock server if the CLI is not available.
+func PreCheck(t *testing.T) {
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") != "" || os.Getenv("TF_ACC_TERRAFORM_VERSION") != "" {
+		return
+	}
+	if _, err := exec.LookPath("terraform"); err != nil {
+		t.Skip("Skip the test because the terraform CLI is not found, set TF_ACC_TERRAFORM_PATH to run it")
+	}
+}
+
+// Meta returns the provider meta, which is configured with the mock server.
+func (s *Server) Meta(t *testing.T) interface{} {
+	provider := huaweicloud.Provider()
+	diags := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(s.ProviderRawConfig()))
+	if diags.HasError() {
+		t.Fatalf("failed to configure the provider with the mock server: %v", diags)
+	}
+	return provider.Meta()
+}
+
+// Step is a configuration of the resource which is applied in the lifecycle test.
+type Step struct {
+	// Config is the resource configuration, the nested blocks are lists of maps
+	Config map[string]interface{}
+	// Check verifies the state after the configuration is applied
+	Check func(t *testing.T, state *terraform.InstanceState)
+}
+
+// Lifecycle describes the CRUD and import cycle of a resource, which is driven by the SDK directly, so it can be run
+// without the terraform CLI.
+type Lifecycle struct {
+	// ResourceType is the type of the resource, e.g. huaweicloud_vpc
+	ResourceType string
+	// Steps are applied in order, the first one creates the resource and the others update it
+	Steps []Step
+	// ImportStateIdFunc returns the ID used to import the resource, the resource ID is used if it's nil
+	ImportStateIdFunc func(state *terraform.InstanceState) string
+	// ImportStateVerifyIgnore are the attribute prefixes which are not verified after importing
+	ImportStateVerifyIgnore []string
+}
+
+// Run applies the steps of the lifecycle, and verifies that:
+//   - there is no difference between the configuration and the refreshed state after each step, which means the
+//     attributes are read and flattened correctly
+//   - the imported state is the same as the applied one
+//   - the resource does not exist after it's destroyed
+func (l Lifecycle) Run(t *testing.T, s *Server) {
+	ctx := context.Background()
+	meta := s.Meta(t)
+	r := resourceSchema(t, l.ResourceType)
+
+	var state *terraform.InstanceState
+	for i, step := range l.Steps {
+		state = s.apply(ctx, t, r, state, step.Config, meta)
+
+		refreshed, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+		if diags.HasError() {
+			t.Fatalf("step %d: failed to refresh %s: %v", i, l.ResourceType, diags)
+		}
+		if refreshed == nil {
+			t.Fatalf("step %d: %s (%s) is not found after it's applied", i, l.ResourceType, state.ID)
+		}
+		state = refreshed
+
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(step.Config), meta)
+		if err != nil {
+			t.Fatalf("step %d: failed to plan %s: %s", i, l.ResourceType, err)
+		}
+		if changes := diffString(diff); changes != "" {
+			t.Fatalf("step %d: the plan of %s is not empty after applying:\n%s", i, l.ResourceType, changes)
+		}
+
+		if step.Check != nil {
+			step.Check(t, state)
+		}
+	}
+
+	if r.Importer != nil && state != nil {
+		l.verifyImport(ctx, t, r, state, meta)
+	}
+
+	if state != nil {
+		_, diags := r.Apply(ctx, state, &terraform.InstanceDiff{Destroy: true}, meta)
+		if diags.HasError() {
+			t.Fatalf("failed to destroy %s (%s): %v", l.ResourceType, state.ID, diags)
+		}
+		refreshed, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+		if diags.HasError() {
+			t.Fatalf("failed to refresh %s (%s) after destroying: %v", l.ResourceType, state.ID, diags)
+		}
+		if refreshed != nil {
+			t.Fatalf("%s (%s) still exists after it's destroyed", l.ResourceType, state.ID)
+		}
+	}
+}
+
+// Apply creates the resource with the configuration and returns its state, it's used to prepare the dependencies,
+// which are released together with the mock server.
+func (s *Server) Apply(t *testing.T, resourceType string, config map[string]interface{}) *terraform.InstanceState {
+	ctx := context.Background()
+	return s.apply(ctx, t, resourceSchema(t, resourceType), nil, config, s.Meta(t))
+}
+
+func (s *Server) apply(ctx context.Context, t *testing.T, r *schema.Resource, state *terraform.InstanceState,
+	config map[string]interface{}, meta interface{}) *terraform.InstanceState {
+	rc := terraform.NewResourceConfigRaw(config)
+	if diags := r.Validate(rc); diags.HasError() {
+		t.Fatalf("invalid configuration: %v", diags)
+	}
+
+	diff, err := r.Diff(ctx, state, rc, meta)
+	if err != nil {
+		t.Fatalf("failed to plan: %s", err)
+	}
+	if diff == nil {
+		return state
+	}
+
+	newState, diags := r.Apply(ctx, state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("failed to apply: %v", diags)
+	}
+	if newState == nil || newState.ID == "" {
+		t.Fatal("the resource ID is empty after applying")
+	}
+	return newState
+}
+
+func (l Lifecycle) verifyImport(ctx context.Context, t *testing.T, r *schema.Resource,
+	state *terraform.InstanceState, meta interface{}) {
+	id := state.ID
+	if l.ImportStateIdFunc != nil {
+		id = l.ImportStateIdFunc(state)
+	}
+
+	results, err := r.Importer.StateContext(ctx, r.Data(&terraform.InstanceState{ID: id}), meta)
+	if err != nil {
+		t.Fatalf("failed to import %s (%s): %s", l.ResourceType, id, err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expect one resource imported from %s (%s), but got %d", l.ResourceType, id, len(results))
+	}
+
+	imported, diags := r.RefreshWithoutUpgrade(ctx, results[0].State(), meta)
+	if diags.HasError() {
+		t.Fatalf("failed to refresh the imported %s (%s): %v", l.ResourceType, id, diags)
+	}
+	if imported == nil {
+		t.Fatalf("the imported %s (%s) is not found", l.ResourceType, id)
+	}
+
+	actual := l.verifiedAttributes(imported)
+	expected := l.verifiedAttributes(state)
+	for _, k := range mergedKeys(actual, expected) {
+		if actual[k] != expected[k] {
+			t.Errorf("the imported attribute %s of %s is %q, but expected %q", k, l.ResourceType, actual[k],
+				expected[k])
+		}
+	}
+}
+
+// verifiedAttributes returns the attributes which are verified after importing, the ignored ones and the timeouts
+// are excluded.
+func (l Lifecycle) verifiedAttributes(state *terraform.InstanceState) map[string]string {
+	result := make(map[string]string)
+	for k, v := range state.Attributes {
+		if k == "%" || k == "id" || strings.HasPrefix(k, "timeouts.") {
+			continue
+		}
+		// the empty collections may be null after importing
+		if v == "0" && (strings.HasSuffix(k, ".#") || strings.HasSuffix(k, ".%")) {
+			continue
+		}
+		if !hasAnyPrefix(k, l.ImportStateVerifyIgnore) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceSchema(t *testing.T, resourceType string) *schema.Resource {
+	r, ok := huaweicloud.Provider().ResourcesMap[resourceType]
+	if !ok {
+		t.Fatalf("the resource %s is not supported by the provider", resourceType)
+	}
+	return r
+}
+
+// diffString returns the changes of the diff, the attributes whose old and new values are the same are not changes,
+// such as an empty map which is not set in the state, they're ignored by terraform when planning.
+func diffString(diff *terraform.InstanceDiff) string {
+	if diff.Empty() {
+		return ""
+	}
+
+	var result strings.Builder
+	if diff.Destroy || diff.DestroyTainted {
+		result.WriteString("  the resource will be destroyed\n")
+	}
+	keys := make([]string, 0, len(diff.Attributes))
+	for k := range diff.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attr := diff.Attributes[k]
+		if attr.Old == attr.New && !attr.NewComputed && !attr.NewRemoved && !attr.RequiresNew {
+			continue
+		}
+		result.WriteString("  " + k + ": " + attr.Old + " => " + attr.New)
+		if attr.RequiresNew {
+			result.WriteString(" (forces new resource)")
+		}
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+func mergedKeys(maps ...map[string]string) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range maps {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CheckDestroy verifies that the resources of the type in the terraform state have been deleted from the mock
+// server, the kind is the collection name of the resources, e.g. vpcs, and it's "buckets" for the OBS buckets.
+func (s *Server) CheckDestroy(resourceType, kind string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		for _, rs := range state.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+			if s.exists(kind, rs.Primary.ID) {
+				return fmt.Errorf("%s (%s) still exists", resourceType, rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+func (s *Server) exists(kind, id string) bool {
+	if kind == "buckets" {
+		return s.Bucket(id)
+	}
+	return s.Resource(kind, id) != nil
+}
@@ -0,0 +1,581 @@
+package mock
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// the subresources which are kept as they're put, e.g. PUT /{bucket}?versioning
+var bucketSubresources = []string{
+	"acl", "cors", "customdomain", "delete", "encryption", "inventory", "lifecycle", "logging", "object-lock",
+	"policy", "quota", "storageinfo", "storagePolicy", "tagging", "versioning", "website",
+}
+
+// the default configurations of the bucket subresources, the other subresources do not exist until they're put
+var bucketDefaults = map[string]string{
+	"versioning": "<VersioningConfiguration></VersioningConfiguration>",
+	"logging":    "<BucketLoggingStatus></BucketLoggingStatus>",
+	"quota":      "<Quota><StorageQuota>0</StorageQuota></Quota>",
+	"acl":        "<AccessControlPolicy><Owner><ID>mock</ID></Owner><AccessControlList></AccessControlList></AccessControlPolicy>",
+}
+
+// the error codes which are returned if the subresources do not exist
+var bucketNotFoundCodes = map[string]string{
+	"cors":        "NoSuchCORSConfiguration",
+	"encryption":  "NoSuchEncryptionConfiguration",
+	"inventory":   "NoSuchInventoryConfiguration",
+	"lifecycle":   "NoSuchLifecycleConfiguration",
+	"object-lock": "ObjectLockConfigurationNotFoundError",
+	"policy":      "NoSuchBucketPolicy",
+	"tagging":     "NoSuchTagSet",
+	"website":     "NoSuchWebsiteConfiguration",
+}
+
+type object struct {
+	data         []byte
+	contentType  string
+	etag         string
+	lastModified time.Time
+}
+
+// multipartUpload is an upload which is initiated but not completed, its parts are kept by their part numbers.
+type multipartUpload struct {
+	contentType string
+	parts       map[int][]byte
+}
+
+type bucket struct {
+	name         string
+	storageClass string
+	epsID        string
+	azRedundancy string
+	fsInterface  bool
+	created      time.Time
+	subresources map[string][]byte
+	domains      map[string]time.Time
+	objects      map[string]*object
+	uploads      map[string]*multipartUpload
+}
+
+// Bucket reports whether the OBS bucket exists in the mock server.
+func (s *Server) Bucket(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.buckets[name]
+	return ok
+}
+
+type obsError struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string   `xml:"Code"`
+	Message   string   `xml:"Message"`
+	Resource  string   `xml:"Resource"`
+	RequestID string   `xml:"RequestId"`
+}
+
+func writeXML(w http.ResponseWriter, status int, body interface{}) {
+	var b []byte
+	switch v := body.(type) {
+	case nil:
+		w.WriteHeader(status)
+		return
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		var err error
+		if b, err = xml.Marshal(v); err != nil {
+			log.Printf("[WARN] mock: failed to encode the response body: %s", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	w.WriteHeader(status)
+	if _, err := w.Write(b); err != nil {
+		log.Printf("[WARN] mock: failed to write the response body: %s", err)
+	}
+}
+
+func writeOBSError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	// the HEAD responses have no body, the error code is returned in the header
+	w.Header().Set("X-Obs-Error-Code", code)
+	if r.Method == http.MethodHead {
+		w.WriteHeader(status)
+		return
+	}
+	writeXML(w, status, obsError{Code: code, Message: message, Resource: r.URL.Path, RequestID: newID()})
+}
+
+// obsHeader returns the value of the request header with the OBS or the S3 prefix.
+func obsHeader(r *http.Request, name string) string {
+	if v := r.Header.Get("x-obs-" + name); v != "" {
+		return v
+	}
+	return r.Header.Get("x-amz-" + name)
+}
+
+// serveOBS serves the OBS APIs in the path style, e.g. /{bucket}?versioning and /{bucket}/{key}.
+func (s *Server) serveOBS(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeOBSError(w, r, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	if path == "" {
+		s.listBuckets(w)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name, key := path, ""
+	if i := strings.Index(path, "/"); i >= 0 {
+		name, key = path[:i], path[i+1:]
+	}
+	b, ok := s.buckets[name]
+	if !ok && !(r.Method == http.MethodPut && key == "" && len(r.URL.Query()) == 0) {
+		writeOBSError(w, r, http.StatusNotFound, "NoSuchBucket", fmt.Sprintf("the bucket (%s) does not exist", name))
+		return
+	}
+
+	if key != "" {
+		s.serveObject(w, r, b, key, body)
+		return
+	}
+
+	for _, subresource := range bucketSubresources {
+		if _, ok := r.URL.Query()[subresource]; ok {
+			s.serveBucketSubresource(w, r, b, subresource, body)
+			return
+		}
+	}
+	if len(r.URL.Query()) > 0 && r.Method != http.MethodGet {
+		writeOBSError(w, r, http.StatusMethodNotAllowed, "MethodNotAllowed",
+			fmt.Sprintf("mock: no handler for %s %s", r.Method, r.URL.String()))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		if ok {
+			writeOBSError(w, r, http.StatusConflict, "BucketAlreadyOwnedByYou",
+				fmt.Sprintf("the bucket (%s) already exists", name))
+			return
+		}
+		s.createBucket(w, r, name)
+	case http.MethodHead:
+		w.Header().Set("X-Obs-Bucket-Location", Region)
+		w.Header().Set("X-Obs-Storage-Class", b.storageClass)
+		w.Header().Set("X-Obs-Epid", b.epsID)
+		w.Header().Set("X-Obs-Version", "3.0")
+		if b.azRedundancy != "" {
+			w.Header().Set("X-Obs-Az-Redundancy", b.azRedundancy)
+		}
+		if b.fsInterface {
+			w.Header().Set("X-Obs-Fs-File-Interface", "Enabled")
+		}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		s.listObjects(w, r, b)
+	case http.MethodDelete:
+		if len(b.objects) > 0 {
+			writeOBSError(w, r, http.StatusConflict, "BucketNotEmpty",
+				fmt.Sprintf("the bucket (%s) is not empty", name))
+			return
+		}
+		delete(s.buckets, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeOBSError(w, r, http.StatusMethodNotAllowed, "MethodNotAllowed",
+			fmt.Sprintf("mock: no handler for %s %s", r.Method, r.URL.String()))
+	}
+}
+
+func (s *Server) listBuckets(w http.ResponseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.buckets))
+	for name := range s.buckets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result strings.Builder
+	result.WriteString("<ListAllMyBucketsResult><Owner><ID>mock</ID></Owner><Buckets>")
+	for _, name := range names {
+		fmt.Fprintf(&result, "<Bucket><Name>%s</Name><CreationDate>%s</CreationDate><Location>%s</Location></Bucket>",
+			name, s.buckets[name].created.Format(time.RFC3339), Region)
+	}
+	result.WriteString("</Buckets></ListAllMyBucketsResult>")
+	writeXML(w, http.StatusOK, result.String())
+}
+
+func (s *Server) createBucket(w http.ResponseWriter, r *http.Request, name string) {
+	class := r.Header.Get("x-obs-storage-class")
+	if class == "" {
+		class = r.Header.Get("x-default-storage-class")
+	}
+	if class == "" {
+		class = "STANDARD"
+	}
+	epsID := obsHeader(r, "epid")
+	if epsID == "" {
+		epsID = "0"
+	}
+
+	b := &bucket{
+		name:         name,
+		storageClass: class,
+		epsID:        epsID,
+		azRedundancy: obsHeader(r, "az-redundancy"),
+		fsInterface:  r.Header.Get("x-obs-fs-file-interface") == "Enabled",
+		created:      time.Now().UTC(),
+		subresources: make(map[string][]byte),
+		domains:      make(map[string]time.Time),
+		objects:      make(map[string]*object),
+		uploads:      make(map[string]*multipartUpload),
+	}
+	if acl := obsHeader(r, "acl"); acl != "" {
+		b.subresources["acl"] = []byte(bucketDefaults["acl"])
+	}
+	if obsHeader(r, "bucket-object-lock-enabled") == "true" {
+		b.subresources["object-lock"] = []byte(
+			"<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled></ObjectLockConfiguration>")
+	}
+	s.buckets[name] = b
+
+	w.Header().Set("Location", "/"+name)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) serveBucketSubresource(w http.ResponseWriter, r *http.Request, b *bucket, subresource string,
+	body []byte) {
+	switch subresource {
+	case "storageinfo":
+		var size int
+		for _, o := range b.objects {
+			size += len(o.data)
+		}
+		writeXML(w, http.StatusOK, fmt.Sprintf(
+			"<GetBucketStorageInfoResult><Size>%d</Size><ObjectNumber>%d</ObjectNumber></GetBucketStorageInfoResult>",
+			size, len(b.objects)))
+		return
+	case "storagePolicy":
+		if r.Method == http.MethodPut {
+			var policy struct {
+				StorageClass string `xml:"DefaultStorageClass"`
+			}
+			if err := xml.Unmarshal(body, &policy); err != nil {
+				writeOBSError(w, r, http.StatusBadRequest, "MalformedXML", err.Error())
+				return
+			}
+			b.storageClass = policy.StorageClass
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		writeXML(w, http.StatusOK, fmt.Sprintf(
+			"<StoragePolicy><DefaultStorageClass>%s</DefaultStorageClass></StoragePolicy>", b.storageClass))
+		return
+	case "customdomain":
+		s.serveBucketCustomDomain(w, r, b)
+		return
+	case "delete":
+		s.deleteObjects(w, r, b, body)
+		return
+	}
+
+	// the inventory configurations are kept by their IDs, e.g. PUT /{bucket}?inventory&id={id}
+	key := subresource
+	if subresource == "inventory" {
+		key = fmt.Sprintf("%s/%s", subresource, r.URL.Query().Get("id"))
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		b.subresources[key] = body
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		if v, ok := b.subresources[key]; ok {
+			if subresource == "policy" {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(v)
+				return
+			}
+			writeXML(w, http.StatusOK, v)
+			return
+		}
+		if v, ok := bucketDefaults[subresource]; ok {
+			writeXML(w, http.StatusOK, v)
+			return
+		}
+		writeOBSError(w, r, http.StatusNotFound, bucketNotFoundCodes[subresource],
+			fmt.Sprintf("the %s configuration of the bucket (%s) does not exist", subresource, b.name))
+	case http.MethodDelete:
+		if _, ok := b.subresources[key]; !ok && subresource == "inventory" {
+			writeOBSError(w, r, http.StatusNotFound, bucketNotFoundCodes[subresource],
+				fmt.Sprintf("the %s configuration of the bucket (%s) does not exist", key, b.name))
+			return
+		}
+		delete(b.subresources, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeOBSError(w, r, http.StatusMethodNotAllowed, "MethodNotAllowed",
+			fmt.Sprintf("mock: no handler for %s %s", r.Method, r.URL.String()))
+	}
+}
+
+func (s *Server) serveBucketCustomDomain(w http.ResponseWriter, r *http.Request, b *bucket) {
+	domain := r.URL.Query().Get("customdomain")
+	switch r.Method {
+	case http.MethodPut:
+		b.domains[domain] = time.Now().UTC()
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		delete(b.domains, domain)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		names := make([]string, 0, len(b.domains))
+		for name := range b.domains {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var result strings.Builder
+		result.WriteString("<ListBucketCustomDomainsResult>")
+		for _, name := range names {
+			fmt.Fprintf(&result, "<Domains><DomainName>%s</DomainName><CreateTime>%s</CreateTime></Domains>",
+				name, b.domains[name].Format(time.RFC3339))
+		}
+		result.WriteString("</ListBucketCustomDomainsResult>")
+		writeXML(w, http.StatusOK, result.String())
+	}
+}
+
+func (s *Server) listObjects(w http.ResponseWriter, r *http.Request, b *bucket) {
+	prefix := r.URL.Query().Get("prefix")
+	keys := make([]string, 0, len(b.objects))
+	for key := range b.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var result strings.Builder
+	fmt.Fprintf(&result, "<ListBucketResult><Name>%s</Name><Prefix>%s</Prefix><IsTruncated>false</IsTruncated>",
+		b.name, prefix)
+	for _, key := range keys {
+		o := b.objects[key]
+		fmt.Fprintf(&result, "<Contents><Key>%s</Key><LastModified>%s</LastModified><ETag>\"%s\"</ETag>"+
+			"<Size>%d</Size><StorageClass>%s</StorageClass></Contents>",
+			key, o.lastModified.Format(time.RFC3339), o.etag, len(o.data), b.storageClass)
+	}
+	result.WriteString("</ListBucketResult>")
+	writeXML(w, http.StatusOK, result.String())
+}
+
+func (s *Server) deleteObjects(w http.ResponseWriter, r *http.Request, b *bucket, body []byte) {
+	var input struct {
+		Objects []struct {
+			Key string `xml:"Key"`
+		} `xml:"Object"`
+	}
+	if err := xml.Unmarshal(body, &input); err != nil {
+		writeOBSError(w, r, http.StatusBadRequest, "MalformedXML", err.Error())
+		return
+	}
+
+	var result strings.Builder
+	result.WriteString("<DeleteResult>")
+	for _, o := range input.Objects {
+		delete(b.objects, o.Key)
+		fmt.Fprintf(&result, "<Deleted><Key>%s</Key></Deleted>", o.Key)
+	}
+	result.WriteString("</DeleteResult>")
+	writeXML(w, http.StatusOK, result.String())
+}
+
+func (s *Server) serveObject(w http.ResponseWriter, r *http.Request, b *bucket, key string, body []byte) {
+	query := r.URL.Query()
+	if _, ok := query["uploads"]; ok || query.Get("uploadId") != "" {
+		s.serveMultipartUpload(w, r, b, key, body)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		if source := obsHeader(r, "copy-source"); source != "" {
+			s.copyObject(w, r, b, key, source)
+			return
+		}
+		sum := md5.Sum(body)
+		o := &object{
+			data:         body,
+			contentType:  r.Header.Get("Content-Type"),
+			etag:         hex.EncodeToString(sum[:]),
+			lastModified: time.Now().UTC(),
+		}
+		b.objects[key] = o
+		w.Header().Set("ETag", fmt.Sprintf("%q", o.etag))
+		w.WriteHeader(http.StatusOK)
+		return
+	case http.MethodDelete:
+		delete(b.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	o, ok := b.objects[key]
+	if !ok {
+		writeOBSError(w, r, http.StatusNotFound, "NoSuchKey", fmt.Sprintf("the object (%s) does not exist", key))
+		return
+	}
+	switch r.Method {
+	case http.MethodHead, http.MethodGet:
+		contentType := o.contentType
+		if contentType == "" {
+			contentType = "binary/octet-stream"
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(o.data)))
+		w.Header().Set("ETag", fmt.Sprintf("%q", o.etag))
+		w.Header().Set("Last-Modified", o.lastModified.Format(http.TimeFormat))
+		w.Header().Set("X-Obs-Storage-Class", b.storageClass)
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(o.data)
+		}
+	default:
+		writeOBSError(w, r, http.StatusMethodNotAllowed, "MethodNotAllowed",
+			fmt.Sprintf("mock: no handler for %s %s", r.Method, r.URL.String()))
+	}
+}
+
+// copyObject copies the object in the server side, the source is in the format {bucket}/{key}?versionId={id}, and
+// the version is ignored.
+func (s *Server) copyObject(w http.ResponseWriter, r *http.Request, b *bucket, key, source string) {
+	if i := strings.Index(source, "?"); i >= 0 {
+		source = source[:i]
+	}
+	if decoded, err := url.PathUnescape(source); err == nil {
+		source = decoded
+	}
+	sourceBucket, sourceKey := strings.TrimPrefix(source, "/"), ""
+	if i := strings.Index(sourceBucket, "/"); i >= 0 {
+		sourceBucket, sourceKey = sourceBucket[:i], sourceBucket[i+1:]
+	}
+
+	var src *object
+	if sb, ok := s.buckets[sourceBucket]; ok {
+		src = sb.objects[sourceKey]
+	}
+	if src == nil {
+		writeOBSError(w, r, http.StatusNotFound, "NoSuchKey", fmt.Sprintf("the source object (%s) does not exist", source))
+		return
+	}
+
+	o := &object{
+		data:         src.data,
+		contentType:  src.contentType,
+		etag:         src.etag,
+		lastModified: time.Now().UTC(),
+	}
+	if obsHeader(r, "metadata-directive") == "REPLACE" {
+		o.contentType = r.Header.Get("Content-Type")
+	}
+	b.objects[key] = o
+	writeXML(w, http.StatusOK, fmt.Sprintf("<CopyObjectResult><LastModified>%s</LastModified><ETag>\"%s\"</ETag>"+
+		"</CopyObjectResult>", o.lastModified.Format(time.RFC3339), o.etag))
+}
+
+// serveMultipartUpload serves the multipart upload APIs, e.g. POST /{bucket}/{key}?uploads and
+// PUT /{bucket}/{key}?partNumber={number}&uploadId={id}.
+func (s *Server) serveMultipartUpload(w http.ResponseWriter, r *http.Request, b *bucket, key string, body []byte) {
+	uploadID := r.URL.Query().Get("uploadId")
+	if uploadID == "" {
+		uploadID = newID()
+		b.uploads[uploadID] = &multipartUpload{
+			contentType: r.Header.Get("Content-Type"),
+			parts:       make(map[int][]byte),
+		}
+		writeXML(w, http.StatusOK, fmt.Sprintf("<InitiateMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key>"+
+			"<UploadId>%s</UploadId></InitiateMultipartUploadResult>", b.name, key, uploadID))
+		return
+	}
+
+	upload, ok := b.uploads[uploadID]
+	if !ok {
+		writeOBSError(w, r, http.StatusNotFound, "NoSuchUpload", fmt.Sprintf("the upload (%s) does not exist", uploadID))
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		number, err := strconv.Atoi(r.URL.Query().Get("partNumber"))
+		if err != nil {
+			writeOBSError(w, r, http.StatusBadRequest, "InvalidArgument", err.Error())
+			return
+		}
+		upload.parts[number] = body
+		sum := md5.Sum(body)
+		w.Header().Set("ETag", fmt.Sprintf("%q", hex.EncodeToString(sum[:])))
+		w.WriteHeader(http.StatusOK)
+	case http.MethodPost:
+		var input struct {
+			Parts []struct {
+				PartNumber int `xml:"PartNumber"`
+			} `xml:"Part"`
+		}
+		if err := xml.Unmarshal(body, &input); err != nil {
+			writeOBSError(w, r, http.StatusBadRequest, "MalformedXML", err.Error())
+			return
+		}
+
+		// the ETag of the multipart object is the MD5 of the part MD5s with the number of parts
+		var data, sums []byte
+		for _, part := range input.Parts {
+			v, ok := upload.parts[part.PartNumber]
+			if !ok {
+				writeOBSError(w, r, http.StatusBadRequest, "InvalidPart",
+					fmt.Sprintf("the part (%d) is not uploaded", part.PartNumber))
+				return
+			}
+			sum := md5.Sum(v)
+			data = append(data, v...)
+			sums = append(sums, sum[:]...)
+		}
+		sum := md5.Sum(sums)
+		o := &object{
+			data:         data,
+			contentType:  upload.contentType,
+			etag:         fmt.Sprintf("%s-%d", hex.EncodeToString(sum[:]), len(input.Parts)),
+			lastModified: time.Now().UTC(),
+		}
+		b.objects[key] = o
+		delete(b.uploads, uploadID)
+		writeXML(w, http.StatusOK, fmt.Sprintf("<CompleteMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key>"+
+			"<ETag>\"%s\"</ETag></CompleteMultipartUploadResult>", b.name, key, o.etag))
+	case http.MethodDelete:
+		delete(b.uploads, uploadID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeOBSError(w, r, http.StatusMethodNotAllowed, "MethodNotAllowed",
+			fmt.Sprintf("mock: no handler for %s %s", r.Method, r.URL.String()))
+	}
+}
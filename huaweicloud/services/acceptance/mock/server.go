@@ -0,0 +1,373 @@
+// Package mock provides an in-process fake of the most used Huawei Cloud APIs, such as VPC, ECS, EVS, EIP, OBS,
+// IAM, tags and BSS orders. The provider can be pointed at it by the custom endpoints, so the CRUD and import cycles
+// of the resources can be tested without a cloud account.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-uuid"
+)
+
+const (
+	// Region is the region of the resources managed by the mock server
+	Region = "cn-north-4"
+	// AvailabilityZone is the availability zone of the resources managed by the mock server
+	AvailabilityZone = "cn-north-4a"
+
+	accessKey = "MOCKACCESSKEY"
+	secretKey = "mock-secret-key"
+)
+
+// handlerFunc handles the request and returns the status code and the response body, the body is encoded in JSON
+// unless it's nil.
+type handlerFunc func(r *request) (int, interface{})
+
+type route struct {
+	method   string
+	segments []string
+	handler  handlerFunc
+}
+
+// request is the request which is dispatched to the handler.
+type request struct {
+	*http.Request
+	server *Server
+	params map[string]string
+	body   []byte
+}
+
+// Param returns the value of the path parameter, e.g. {vpc_id}.
+func (r *request) Param(name string) string {
+	return r.params[name]
+}
+
+// decode decodes the JSON request body into a map.
+func (r *request) decode() (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	if len(r.body) == 0 {
+		return result, nil
+	}
+	err := json.Unmarshal(r.body, &result)
+	return result, err
+}
+
+// Server is an in-process fake of the cloud APIs, the resources are kept in memory and deleted when the test is
+// finished.
+type Server struct {
+	ProjectID string
+	DomainID  string
+
+	api    *httptest.Server
+	obs    *httptest.Server
+	routes []route
+
+	mu        sync.Mutex
+	resources map[string]map[string]map[string]interface{}
+	tags      map[string]map[string]string
+	buckets   map[string]*bucket
+}
+
+// NewServer starts the mock server, which is closed in the cleanup of the test.
+// The test is skipped unless HW_MOCK_TEST is set, because the resources wait for their status with the same delays
+// as against the cloud, which makes the mock tests too slow for the unit test suite.
+func NewServer(t *testing.T) *Server {
+	if os.Getenv("HW_MOCK_TEST") == "" {
+		t.Skip("Skip the mock test, set HW_MOCK_TEST to run it")
+	}
+
+	s := &Server{
+		ProjectID: strings.ReplaceAll(newID(), "-", ""),
+		DomainID:  strings.ReplaceAll(newID(), "-", ""),
+		resources: make(map[string]map[string]map[string]interface{}),
+		tags:      make(map[string]map[string]string),
+		buckets:   make(map[string]*bucket),
+	}
+
+	s.registerTags()
+	s.registerIAM()
+	s.registerBSS()
+	s.registerVPC()
+	s.registerEIP()
+	s.registerEVS()
+	s.registerECS()
+
+	s.api = httptest.NewServer(http.HandlerFunc(s.serveAPI))
+	s.obs = httptest.NewServer(http.HandlerFunc(s.serveOBS))
+	t.Cleanup(func() {
+		s.api.Close()
+		s.obs.Close()
+	})
+	return s
+}
+
+// Endpoints returns the custom endpoints of the services which are faked by the mock server.
+func (s *Server) Endpoints() map[string]string {
+	endpoints := make(map[string]string)
+	for _, service := range []string{"iam", "bss", "vpc", "ecs", "evs", "ims", "eps", "tms"} {
+		endpoints[service] = s.api.URL + "/"
+	}
+	endpoints["obs"] = s.obs.URL + "/"
+	return endpoints
+}
+
+// ProviderConfig returns the provider block in HCL which points to the mock server.
+func (s *Server) ProviderConfig() string {
+	var endpoints strings.Builder
+	for service, endpoint := range s.Endpoints() {
+		endpoints.WriteString(fmt.Sprintf("    %-4s = \"%s\"\n", service, endpoint))
+	}
+
+	return fmt.Sprintf(`
+provider "huaweicloud" {
+  region     = "%s"
+  access_key = "%s"
+  secret_key = "%s"
+  project_id = "%s"
+  domain_id  = "%s"
+  auth_url   = "%s/v3"
+
+  endpoints = {
+%s  }
+}
+`, Region, accessKey, secretKey, s.ProjectID, s.DomainID, s.api.URL, endpoints.String())
+}
+
+// ProviderRawConfig returns the provider configuration which points to the mock server.
+func (s *Server) ProviderRawConfig() map[string]interface{} {
+	endpoints := make(map[string]interface{})
+	for service, endpoint := range s.Endpoints() {
+		endpoints[service] = endpoint
+	}
+
+	return map[string]interface{}{
+		"region":     Region,
+		"access_key": accessKey,
+		"secret_key": secretKey,
+		"project_id": s.ProjectID,
+		"domain_id":  s.DomainID,
+		"auth_url":   s.api.URL + "/v3",
+		"endpoints":  endpoints,
+	}
+}
+
+// Resource returns a copy of the resource kept in the mock server, or nil if it does not exist.
+// The kind is the collection name in the API path, e.g. vpcs, subnets and cloudservers.
+func (s *Server) Resource(kind, id string) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyObject(s.resources[kind][id])
+}
+
+// ResourceCount returns the number of the resources of the kind kept in the mock server.
+func (s *Server) ResourceCount(kind string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.resources[kind])
+}
+
+// handle registers the handler of the API, the path parameters are wrapped in braces, e.g. /v1/{project_id}/vpcs.
+func (s *Server) handle(method, pattern string, handler handlerFunc) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+		handler:  handler,
+	})
+}
+
+// match returns the route which matches the request path, the route with more literal segments takes precedence.
+func (s *Server) match(method, path string) (*route, map[string]string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	var matched *route
+	var params map[string]string
+	best := -1
+	for i := range s.routes {
+		rt := &s.routes[i]
+		if rt.method != method || len(rt.segments) != len(segments) {
+			continue
+		}
+
+		values := make(map[string]string)
+		literals := 0
+		ok := true
+		for j, segment := range rt.segments {
+			if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+				values[strings.Trim(segment, "{}")] = segments[j]
+				continue
+			}
+			if segment != segments[j] {
+				ok = false
+				break
+			}
+			literals++
+		}
+		if ok && literals > best {
+			matched, params, best = rt, values, literals
+		}
+	}
+	return matched, params
+}
+
+func (s *Server) serveAPI(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorBody("Mock.0400", err.Error()))
+		return
+	}
+
+	rt, params := s.match(r.Method, r.URL.Path)
+	if rt == nil {
+		log.Printf("[WARN] mock: no handler for %s %s", r.Method, r.URL.Path)
+		writeJSON(w, http.StatusNotImplemented,
+			errorBody("Mock.0501", fmt.Sprintf("mock: no handler for %s %s", r.Method, r.URL.Path)))
+		return
+	}
+
+	status, result := rt.handler(&request{Request: r, server: s, params: params, body: body})
+	writeJSON(w, status, result)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("[WARN] mock: failed to encode the response body: %s", err)
+	}
+}
+
+func errorBody(code, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"error_code": code,
+		"error_msg":  message,
+	}
+}
+
+func badRequest(err error) (int, interface{}) {
+	return http.StatusBadRequest, errorBody("Mock.0400", err.Error())
+}
+
+func notFound(kind, id string) (int, interface{}) {
+	return http.StatusNotFound, errorBody("Mock.0404", fmt.Sprintf("the %s (%s) does not exist", kind, id))
+}
+
+func newID() string {
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// create keeps the resource in memory, the ID is generated if it's not specified.
+func (s *Server) create(kind string, object map[string]interface{}) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, _ := object["id"].(string)
+	if id == "" {
+		id = newID()
+		object["id"] = id
+	}
+	if s.resources[kind] == nil {
+		s.resources[kind] = make(map[string]map[string]interface{})
+	}
+	s.resources[kind][id] = object
+	return copyObject(object)
+}
+
+// update merges the fields into the resource and returns the updated copy, or nil if it does not exist.
+func (s *Server) update(kind, id string, fields map[string]interface{}) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	object, ok := s.resources[kind][id]
+	if !ok {
+		return nil
+	}
+	for k, v := range fields {
+		object[k] = v
+	}
+	return copyObject(object)
+}
+
+// delete removes the resource and its tags, and returns whether it exists.
+func (s *Server) delete(kind, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.resources[kind][id]; !ok {
+		return false
+	}
+	delete(s.resources[kind], id)
+	delete(s.tags, id)
+	return true
+}
+
+// list returns the copies of the resources of the kind which match the filter, they're sorted by ID.
+func (s *Server) list(kind string, filter func(map[string]interface{}) bool) []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.resources[kind]))
+	for id := range s.resources[kind] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	result := make([]interface{}, 0)
+	for _, id := range ids {
+		object := s.resources[kind][id]
+		if filter == nil || filter(object) {
+			result = append(result, copyObject(object))
+		}
+	}
+	return result
+}
+
+// afterMarker returns the resources after the marker, which is the ID of the last resource in the previous page.
+func afterMarker(objects []interface{}, marker string) []interface{} {
+	if marker == "" {
+		return objects
+	}
+
+	result := make([]interface{}, 0)
+	for _, object := range objects {
+		if id, _ := object.(map[string]interface{})["id"].(string); id > marker {
+			result = append(result, object)
+		}
+	}
+	return result
+}
+
+func copyObject(object map[string]interface{}) map[string]interface{} {
+	if object == nil {
+		return nil
+	}
+
+	// the objects are decoded from JSON, so they can be copied by encoding them again
+	b, err := json.Marshal(object)
+	if err != nil {
+		panic(err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(b, &result); err != nil {
+		panic(err)
+	}
+	return result
+}
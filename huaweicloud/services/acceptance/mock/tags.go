@@ -0,0 +1,126 @@
+package mock
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// setTags creates or updates the tags of the resource.
+func (s *Server) setTags(id string, tags map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tags[id] == nil {
+		s.tags[id] = make(map[string]string)
+	}
+	for k, v := range tags {
+		s.tags[id][k] = v
+	}
+}
+
+// removeTags removes the tags with the keys from the resource.
+func (s *Server) removeTags(id string, keys []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, k := range keys {
+		delete(s.tags[id], k)
+	}
+}
+
+// tagMap returns the tags of the resource in the format of {"key": "value"}.
+func (s *Server) tagMap(id string) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[string]interface{}, len(s.tags[id]))
+	for k, v := range s.tags[id] {
+		result[k] = v
+	}
+	return result
+}
+
+// tagList returns the tags of the resource in the format of [{"key": "k", "value": "v"}].
+func (s *Server) tagList(id string) []interface{} {
+	tags := s.tagMap(id)
+	result := make([]interface{}, 0, len(tags))
+	for _, k := range sortedKeys(tags) {
+		result = append(result, map[string]interface{}{"key": k, "value": tags[k]})
+	}
+	return result
+}
+
+// tagStrings returns the tags of the resource in the format of ["k=v"].
+func (s *Server) tagStrings(id string) []interface{} {
+	tags := s.tagMap(id)
+	result := make([]interface{}, 0, len(tags))
+	for _, k := range sortedKeys(tags) {
+		result = append(result, fmt.Sprintf("%s=%s", k, tags[k]))
+	}
+	return result
+}
+
+// parseTagList parses the tags in the format of [{"key": "k", "value": "v"}].
+func parseTagList(raw interface{}) map[string]string {
+	result := make(map[string]string)
+	list, _ := raw.([]interface{})
+	for _, item := range list {
+		tag, _ := item.(map[string]interface{})
+		key, _ := tag["key"].(string)
+		value, _ := tag["value"].(string)
+		if key != "" {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// parseTagMap parses the tags in the format of {"key": "value"}.
+func parseTagMap(raw interface{}) map[string]string {
+	result := make(map[string]string)
+	tags, _ := raw.(map[string]interface{})
+	for k, v := range tags {
+		result[k], _ = v.(string)
+	}
+	return result
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// registerTags registers the common tag APIs of the resources, e.g. /v2.0/{project_id}/vpcs/{id}/tags/action.
+func (s *Server) registerTags() {
+	s.handle("POST", "/{version}/{project_id}/{kind}/{id}/tags/action", s.batchTags)
+	s.handle("GET", "/{version}/{project_id}/{kind}/{id}/tags", func(r *request) (int, interface{}) {
+		return http.StatusOK, map[string]interface{}{"tags": s.tagList(r.Param("id"))}
+	})
+}
+
+func (s *Server) batchTags(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	tags := parseTagList(body["tags"])
+	switch body["action"] {
+	case "create":
+		s.setTags(r.Param("id"), tags)
+	case "delete":
+		keys := make([]string, 0, len(tags))
+		for k := range tags {
+			keys = append(keys, k)
+		}
+		s.removeTags(r.Param("id"), keys)
+	default:
+		return badRequest(fmt.Errorf("invalid action: %v", body["action"]))
+	}
+	return http.StatusNoContent, nil
+}
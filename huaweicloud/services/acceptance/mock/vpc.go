@@ -0,0 +1,375 @@
+package mock
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+func (s *Server) registerVPC() {
+	s.handle("POST", "/v1/{project_id}/vpcs", s.createVpc)
+	s.handle("GET", "/v1/{project_id}/vpcs/{vpc_id}", s.getVpc)
+	s.handle("PUT", "/v1/{project_id}/vpcs/{vpc_id}", s.updateVpc)
+	s.handle("DELETE", "/v1/{project_id}/vpcs/{vpc_id}", s.deleteVpc)
+	s.handle("GET", "/v3/{project_id}/vpc/vpcs/{vpc_id}", s.getVpcV3)
+	s.handle("PUT", "/v3/{project_id}/vpc/vpcs/{vpc_id}/add-extend-cidr", s.updateVpcExtendCidrs(true))
+	s.handle("PUT", "/v3/{project_id}/vpc/vpcs/{vpc_id}/remove-extend-cidr", s.updateVpcExtendCidrs(false))
+
+	s.handle("POST", "/v1/{project_id}/subnets", s.createSubnet)
+	s.handle("GET", "/v1/{project_id}/subnets/{subnet_id}", s.getSubnet)
+	s.handle("PUT", "/v1/{project_id}/vpcs/{vpc_id}/subnets/{subnet_id}", s.updateSubnet)
+	s.handle("DELETE", "/v1/{project_id}/vpcs/{vpc_id}/subnets/{subnet_id}", s.deleteSubnet)
+
+	s.handle("POST", "/v1/{project_id}/security-groups", s.createSecurityGroup)
+	s.handle("GET", "/v1/{project_id}/security-groups", s.listSecurityGroups)
+	s.handle("GET", "/v1/{project_id}/security-groups/{security_group_id}", s.getSecurityGroup(false))
+	s.handle("DELETE", "/v1/{project_id}/security-groups/{security_group_id}", s.deleteSecurityGroup)
+	s.handle("DELETE", "/v1/{project_id}/security-group-rules/{rule_id}", s.deleteSecurityGroupRule)
+	s.handle("POST", "/v3/{project_id}/vpc/security-groups", s.createSecurityGroup)
+	s.handle("GET", "/v3/{project_id}/vpc/security-groups/{security_group_id}", s.getSecurityGroup(true))
+	s.handle("PUT", "/v3/{project_id}/vpc/security-groups/{security_group_id}", s.updateSecurityGroup)
+	s.handle("DELETE", "/v3/{project_id}/vpc/security-group-rules/{rule_id}", s.deleteSecurityGroupRule)
+
+	s.handle("GET", "/v1/{project_id}/ports/{port_id}", func(r *request) (int, interface{}) {
+		port := s.Resource("ports", r.Param("port_id"))
+		if port == nil {
+			return notFound("port", r.Param("port_id"))
+		}
+		return http.StatusOK, map[string]interface{}{"port": port}
+	})
+}
+
+func (s *Server) createVpc(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	vpc, _ := body["vpc"].(map[string]interface{})
+	vpc["status"] = "OK"
+	vpc["routes"] = []interface{}{}
+	vpc["extend_cidrs"] = []interface{}{}
+	if _, ok := vpc["enterprise_project_id"]; !ok {
+		vpc["enterprise_project_id"] = "0"
+	}
+	return http.StatusOK, map[string]interface{}{"vpc": s.create("vpcs", vpc)}
+}
+
+func (s *Server) getVpc(r *request) (int, interface{}) {
+	vpc := s.Resource("vpcs", r.Param("vpc_id"))
+	if vpc == nil {
+		return notFound("VPC", r.Param("vpc_id"))
+	}
+	delete(vpc, "extend_cidrs")
+	return http.StatusOK, map[string]interface{}{"vpc": vpc}
+}
+
+func (s *Server) getVpcV3(r *request) (int, interface{}) {
+	vpc := s.Resource("vpcs", r.Param("vpc_id"))
+	if vpc == nil {
+		return notFound("VPC", r.Param("vpc_id"))
+	}
+	delete(vpc, "routes")
+	vpc["project_id"] = s.ProjectID
+	vpc["tags"] = s.tagList(r.Param("vpc_id"))
+	vpc["cloud_resources"] = []interface{}{}
+	return http.StatusOK, map[string]interface{}{"vpc": vpc}
+}
+
+func (s *Server) updateVpc(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	fields, _ := body["vpc"].(map[string]interface{})
+	vpc := s.update("vpcs", r.Param("vpc_id"), fields)
+	if vpc == nil {
+		return notFound("VPC", r.Param("vpc_id"))
+	}
+	return http.StatusOK, map[string]interface{}{"vpc": vpc}
+}
+
+func (s *Server) deleteVpc(r *request) (int, interface{}) {
+	vpcID := r.Param("vpc_id")
+	subnets := s.list("subnets", func(subnet map[string]interface{}) bool {
+		return subnet["vpc_id"] == vpcID
+	})
+	if len(subnets) > 0 {
+		return http.StatusConflict, errorBody("VPC.0015", fmt.Sprintf("the VPC (%s) still has subnets", vpcID))
+	}
+
+	if !s.delete("vpcs", vpcID) {
+		return notFound("VPC", vpcID)
+	}
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) updateVpcExtendCidrs(add bool) handlerFunc {
+	return func(r *request) (int, interface{}) {
+		body, err := r.decode()
+		if err != nil {
+			return badRequest(err)
+		}
+
+		vpc := s.Resource("vpcs", r.Param("vpc_id"))
+		if vpc == nil {
+			return notFound("VPC", r.Param("vpc_id"))
+		}
+
+		option, _ := body["vpc"].(map[string]interface{})
+		changes, _ := option["extend_cidrs"].([]interface{})
+		current, _ := vpc["extend_cidrs"].([]interface{})
+		result := make([]interface{}, 0, len(current)+len(changes))
+		for _, cidr := range current {
+			if !add && containsValue(changes, cidr) {
+				continue
+			}
+			result = append(result, cidr)
+		}
+		if add {
+			result = append(result, changes...)
+		}
+
+		vpc = s.update("vpcs", r.Param("vpc_id"), map[string]interface{}{"extend_cidrs": result})
+		return http.StatusOK, map[string]interface{}{"vpc": vpc}
+	}
+}
+
+func (s *Server) createSubnet(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	subnet, _ := body["subnet"].(map[string]interface{})
+	vpcID, _ := subnet["vpc_id"].(string)
+	if s.Resource("vpcs", vpcID) == nil {
+		return notFound("VPC", vpcID)
+	}
+
+	id := newID()
+	subnet["id"] = id
+	subnet["status"] = "ACTIVE"
+	subnet["neutron_network_id"] = id
+	subnet["neutron_subnet_id"] = newID()
+	if _, ok := subnet["dhcp_enable"]; !ok {
+		subnet["dhcp_enable"] = true
+	}
+	if dnsList, ok := subnet["dnsList"].([]interface{}); ok {
+		if _, ok := subnet["primary_dns"]; !ok && len(dnsList) > 0 {
+			subnet["primary_dns"] = dnsList[0]
+		}
+		if _, ok := subnet["secondary_dns"]; !ok && len(dnsList) > 1 {
+			subnet["secondary_dns"] = dnsList[1]
+		}
+	}
+
+	return http.StatusOK, map[string]interface{}{"subnet": s.create("subnets", subnet)}
+}
+
+func (s *Server) getSubnet(r *request) (int, interface{}) {
+	subnet := s.Resource("subnets", r.Param("subnet_id"))
+	if subnet == nil {
+		return notFound("subnet", r.Param("subnet_id"))
+	}
+	return http.StatusOK, map[string]interface{}{"subnet": subnet}
+}
+
+func (s *Server) updateSubnet(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	fields, _ := body["subnet"].(map[string]interface{})
+	subnet := s.update("subnets", r.Param("subnet_id"), fields)
+	if subnet == nil {
+		return notFound("subnet", r.Param("subnet_id"))
+	}
+	return http.StatusOK, map[string]interface{}{"subnet": map[string]interface{}{"id": subnet["id"], "status": "ACTIVE"}}
+}
+
+func (s *Server) deleteSubnet(r *request) (int, interface{}) {
+	subnetID := r.Param("subnet_id")
+	ports := s.list("ports", func(port map[string]interface{}) bool {
+		return port["network_id"] == subnetID
+	})
+	if len(ports) > 0 {
+		return http.StatusConflict, errorBody("VPC.0022", fmt.Sprintf("the subnet (%s) still has ports", subnetID))
+	}
+
+	if !s.delete("subnets", subnetID) {
+		return notFound("subnet", subnetID)
+	}
+	return http.StatusNoContent, nil
+}
+
+// newPort creates the port in the subnet, the IP address is allocated if it's not specified.
+func (s *Server) newPort(subnetID, ipAddress, deviceID string) (map[string]interface{}, error) {
+	subnet := s.Resource("subnets", subnetID)
+	if subnet == nil {
+		return nil, fmt.Errorf("the subnet (%s) does not exist", subnetID)
+	}
+
+	if ipAddress == "" {
+		_, network, err := net.ParseCIDR(subnet["cidr"].(string))
+		if err != nil {
+			return nil, err
+		}
+		ip := network.IP.To4()
+		// the first addresses are reserved by the gateway and the system services
+		ip[3] += byte(10 + s.ResourceCount("ports"))
+		ipAddress = ip.String()
+	}
+
+	id := newID()
+	return s.create("ports", map[string]interface{}{
+		"id":                    id,
+		"name":                  "",
+		"network_id":            subnetID,
+		"mac_address":           fmt.Sprintf("fa:16:3e:%s:%s:%s", id[0:2], id[2:4], id[4:6]),
+		"fixed_ips":             []interface{}{map[string]interface{}{"subnet_id": subnet["neutron_subnet_id"], "ip_address": ipAddress}},
+		"device_id":             deviceID,
+		"device_owner":          "compute:" + AvailabilityZone,
+		"status":                "ACTIVE",
+		"admin_state_up":        true,
+		"allowed_address_pairs": []interface{}{},
+		"security_groups":       []interface{}{},
+	}), nil
+}
+
+func (s *Server) createSecurityGroup(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	group, _ := body["security_group"].(map[string]interface{})
+	now := time.Now().UTC().Format(time.RFC3339)
+	group["id"] = newID()
+	group["project_id"] = s.ProjectID
+	group["created_at"] = now
+	group["updated_at"] = now
+	if _, ok := group["description"]; !ok {
+		group["description"] = ""
+	}
+	if _, ok := group["enterprise_project_id"]; !ok {
+		group["enterprise_project_id"] = "0"
+	}
+	group = s.create("security-groups", group)
+
+	// the default rules allow all the outbound traffic and the inbound traffic from the same security group
+	for _, ethertype := range []string{"IPv4", "IPv6"} {
+		s.newSecurityGroupRule(group["id"].(string), "egress", ethertype, "")
+		s.newSecurityGroupRule(group["id"].(string), "ingress", ethertype, group["id"].(string))
+	}
+	return http.StatusCreated, map[string]interface{}{"security_group": s.securityGroup(group["id"].(string), true)}
+}
+
+func (s *Server) newSecurityGroupRule(groupID, direction, ethertype, remoteGroupID string) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	s.create("security-group-rules", map[string]interface{}{
+		"security_group_id": groupID,
+		"direction":         direction,
+		"ethertype":         ethertype,
+		"protocol":          "",
+		"multiport":         "",
+		"action":            "allow",
+		"priority":          100,
+		"remote_ip_prefix":  "",
+		"remote_group_id":   remoteGroupID,
+		"description":       "",
+		"project_id":        s.ProjectID,
+		"created_at":        now,
+		"updated_at":        now,
+	})
+}
+
+// securityGroup returns the security group with its rules in the format of v1 or v3 API.
+func (s *Server) securityGroup(id string, v3 bool) map[string]interface{} {
+	group := s.Resource("security-groups", id)
+	if group == nil {
+		return nil
+	}
+
+	rules := s.list("security-group-rules", func(rule map[string]interface{}) bool {
+		return rule["security_group_id"] == id
+	})
+	group["security_group_rules"] = rules
+	if v3 {
+		return group
+	}
+
+	// the v1 API does not return the fields which are introduced in v3 API
+	for _, rule := range rules {
+		for _, key := range []string{"multiport", "action", "priority", "created_at", "updated_at"} {
+			delete(rule.(map[string]interface{}), key)
+		}
+	}
+	delete(group, "created_at")
+	delete(group, "updated_at")
+	return group
+}
+
+func (s *Server) getSecurityGroup(v3 bool) handlerFunc {
+	return func(r *request) (int, interface{}) {
+		group := s.securityGroup(r.Param("security_group_id"), v3)
+		if group == nil {
+			return notFound("security group", r.Param("security_group_id"))
+		}
+		return http.StatusOK, map[string]interface{}{"security_group": group}
+	}
+}
+
+func (s *Server) listSecurityGroups(r *request) (int, interface{}) {
+	groups := afterMarker(s.list("security-groups", nil), r.URL.Query().Get("marker"))
+	for i, group := range groups {
+		groups[i] = s.securityGroup(group.(map[string]interface{})["id"].(string), false)
+	}
+	return http.StatusOK, map[string]interface{}{"security_groups": groups}
+}
+
+func (s *Server) updateSecurityGroup(r *request) (int, interface{}) {
+	body, err := r.decode()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	fields, _ := body["security_group"].(map[string]interface{})
+	fields["updated_at"] = time.Now().UTC().Format(time.RFC3339)
+	if s.update("security-groups", r.Param("security_group_id"), fields) == nil {
+		return notFound("security group", r.Param("security_group_id"))
+	}
+	return http.StatusOK, map[string]interface{}{"security_group": s.securityGroup(r.Param("security_group_id"), true)}
+}
+
+func (s *Server) deleteSecurityGroup(r *request) (int, interface{}) {
+	groupID := r.Param("security_group_id")
+	if !s.delete("security-groups", groupID) {
+		return notFound("security group", groupID)
+	}
+	for _, rule := range s.list("security-group-rules", func(rule map[string]interface{}) bool {
+		return rule["security_group_id"] == groupID
+	}) {
+		s.delete("security-group-rules", rule.(map[string]interface{})["id"].(string))
+	}
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) deleteSecurityGroupRule(r *request) (int, interface{}) {
+	if !s.delete("security-group-rules", r.Param("rule_id")) {
+		return notFound("security group rule", r.Param("rule_id"))
+	}
+	return http.StatusNoContent, nil
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package obs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	th "github.com/chnsz/golangsdk/testhelper"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance/mock"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/obs"
+)
+
+func getOBSBucketInventoryResourceFunc(cfg *config.Config, state *terraform.ResourceState) (interface{}, error) {
+	return obs.GetBucketInventory(cfg, acceptance.HW_REGION_NAME, state.Primary.Attributes["bucket"],
+		state.Primary.Attributes["configuration_id"])
+}
+
+func TestAccObsBucketInventory_basic(t *testing.T) {
+	var obj interface{}
+
+	name := acceptance.RandomAccResourceNameWithDash()
+	rName := "huaweicloud_obs_bucket_inventory.test"
+	rc := acceptance.InitResourceCheck(
+		rName,
+		&obj,
+		getOBSBucketInventoryResourceFunc,
+	)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck: func() {
+			acceptance.TestAccPreCheck(t)
+			acceptance.TestAccPreCheckOBS(t)
+		},
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      rc.CheckResourceDestroy(),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccObsBucketInventory_basic(name),
+				Check: resource.ComposeTestCheckFunc(
+					rc.CheckResourceExists(),
+					resource.TestCheckResourceAttr(rName, "bucket", name),
+					resource.TestCheckResourceAttr(rName, "configuration_id", "daily"),
+					resource.TestCheckResourceAttr(rName, "frequency", "Daily"),
+					resource.TestCheckResourceAttr(rName, "format", "CSV"),
+					resource.TestCheckResourceAttr(rName, "included_object_versions", "Current"),
+					resource.TestCheckResourceAttr(rName, "included_fields.#", "2"),
+					resource.TestCheckResourceAttr(rName, "filter_prefix", "logs/"),
+					resource.TestCheckResourceAttr(rName, "enabled", "true"),
+					resource.TestCheckResourceAttrPair(rName, "destination_bucket",
+						"huaweicloud_obs_bucket.destination", "bucket"),
+				),
+			},
+			{
+				Config: testAccObsBucketInventory_update(name),
+				Check: resource.ComposeTestCheckFunc(
+					rc.CheckResourceExists(),
+					resource.TestCheckResourceAttr(rName, "frequency", "Weekly"),
+					resource.TestCheckResourceAttr(rName, "included_object_versions", "All"),
+					resource.TestCheckResourceAttr(rName, "included_fields.#", "0"),
+					resource.TestCheckResourceAttr(rName, "filter_prefix", ""),
+					resource.TestCheckResourceAttr(rName, "destination_prefix", "inventory/"),
+					resource.TestCheckResourceAttr(rName, "enabled", "false"),
+				),
+			},
+			{
+				ResourceName:      rName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccObsBucketInventory_base(name string) string {
+	return fmt.Sprintf(`
+resource "huaweicloud_obs_bucket" "test" {
+  bucket        = "%[1]s"
+  acl           = "private"
+  force_destroy = true
+}
+
+resource "huaweicloud_obs_bucket" "destination" {
+  bucket        = "%[1]s-inventory"
+  acl           = "private"
+  force_destroy = true
+}
+`, name)
+}
+
+func testAccObsBucketInventory_basic(name string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "huaweicloud_obs_bucket_inventory" "test" {
+  bucket             = huaweicloud_obs_bucket.test.bucket
+  configuration_id   = "daily"
+  destination_bucket = huaweicloud_obs_bucket.destination.bucket
+  frequency          = "Daily"
+  included_fields    = ["Size", "ETag"]
+  filter_prefix      = "logs/"
+}
+`, testAccObsBucketInventory_base(name))
+}
+
+func testAccObsBucketInventory_update(name string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "huaweicloud_obs_bucket_inventory" "test" {
+  bucket                   = huaweicloud_obs_bucket.test.bucket
+  configuration_id         = "daily"
+  destination_bucket       = huaweicloud_obs_bucket.destination.bucket
+  destination_prefix       = "inventory/"
+  frequency                = "Weekly"
+  included_object_versions = "All"
+  enabled                  = false
+}
+`, testAccObsBucketInventory_base(name))
+}
+
+func TestObsBucketInventory_mockLifecycle(t *testing.T) {
+	t.Parallel()
+
+	server := mock.NewServer(t)
+	server.Apply(t, "huaweicloud_obs_bucket", map[string]interface{}{"bucket": "tf-mock-bucket", "acl": "private"})
+	mock.Lifecycle{
+		ResourceType: "huaweicloud_obs_bucket_inventory",
+		Steps: []mock.Step{
+			{
+				Config: map[string]interface{}{
+					"bucket":             "tf-mock-bucket",
+					"configuration_id":   "daily",
+					"destination_bucket": "tf-mock-bucket",
+					"frequency":          "Daily",
+					"included_fields":    []interface{}{"Size", "ETag"},
+					"filter_prefix":      "logs/",
+				},
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "tf-mock-bucket/daily", state.ID)
+					th.AssertEquals(t, "CSV", state.Attributes["format"])
+					th.AssertEquals(t, "2", state.Attributes["included_fields.#"])
+				},
+			},
+			{
+				Config: map[string]interface{}{
+					"bucket":                   "tf-mock-bucket",
+					"configuration_id":         "daily",
+					"destination_bucket":       "tf-mock-bucket",
+					"destination_prefix":       "inventory/",
+					"frequency":                "Weekly",
+					"included_object_versions": "All",
+					"enabled":                  false,
+				},
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "Weekly", state.Attributes["frequency"])
+					th.AssertEquals(t, "false", state.Attributes["enabled"])
+					th.AssertEquals(t, "", state.Attributes["filter_prefix"])
+				},
+			},
+		},
+	}.Run(t, server)
+}
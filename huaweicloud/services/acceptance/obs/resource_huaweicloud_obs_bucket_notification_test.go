@@ -0,0 +1,123 @@
+package obs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+)
+
+func getOBSBucketNotificationResourceFunc(cfg *config.Config, state *terraform.ResourceState) (interface{}, error) {
+	obsClient, err := cfg.ObjectStorageClientWithSignature(acceptance.HW_REGION_NAME)
+	if err != nil {
+		return nil, fmt.Errorf("error creating OBS Client: %s", err)
+	}
+
+	output, err := obsClient.GetBucketNotification(state.Primary.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(output.TopicConfigurations) == 0 {
+		return nil, fmt.Errorf("the notification configuration of OBS bucket %s not found", state.Primary.ID)
+	}
+	return output, nil
+}
+
+func TestAccObsBucketNotification_basic(t *testing.T) {
+	var obj interface{}
+
+	name := acceptance.RandomAccResourceNameWithDash()
+	rName := "huaweicloud_obs_bucket_notification.test"
+	rc := acceptance.InitResourceCheck(
+		rName,
+		&obj,
+		getOBSBucketNotificationResourceFunc,
+	)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() { acceptance.TestAccPreCheck(t) },
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      rc.CheckResourceDestroy(),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccObsBucketNotification_basic(name),
+				Check: resource.ComposeTestCheckFunc(
+					rc.CheckResourceExists(),
+					resource.TestCheckResourceAttr(rName, "bucket", name),
+					resource.TestCheckResourceAttr(rName, "topic_configurations.#", "1"),
+					resource.TestCheckResourceAttrPair(rName, "topic_configurations.0.topic",
+						"huaweicloud_smn_topic.test", "topic_urn"),
+					resource.TestCheckResourceAttr(rName, "topic_configurations.0.events.0", "ObjectCreated:*"),
+					resource.TestCheckResourceAttr(rName, "topic_configurations.0.filter_prefix", "input/"),
+					resource.TestCheckResourceAttrSet(rName, "topic_configurations.0.id"),
+				),
+			},
+			{
+				Config: testAccObsBucketNotification_update(name),
+				Check: resource.ComposeTestCheckFunc(
+					rc.CheckResourceExists(),
+					resource.TestCheckResourceAttr(rName, "topic_configurations.0.id", "ingestion"),
+					resource.TestCheckResourceAttr(rName, "topic_configurations.0.events.#", "2"),
+					resource.TestCheckResourceAttr(rName, "topic_configurations.0.filter_prefix", ""),
+					resource.TestCheckResourceAttr(rName, "topic_configurations.0.filter_suffix", ".csv"),
+				),
+			},
+			{
+				ResourceName:      rName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccObsBucketNotification_base(name string) string {
+	return fmt.Sprintf(`
+resource "huaweicloud_obs_bucket" "test" {
+  bucket = "%[1]s"
+  acl    = "private"
+}
+
+resource "huaweicloud_smn_topic" "test" {
+  name                     = "%[1]s"
+  services_publish_allowed = "obs"
+}
+`, name)
+}
+
+func testAccObsBucketNotification_basic(name string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "huaweicloud_obs_bucket_notification" "test" {
+  bucket = huaweicloud_obs_bucket.test.bucket
+
+  topic_configurations {
+    topic         = huaweicloud_smn_topic.test.topic_urn
+    events        = ["ObjectCreated:*"]
+    filter_prefix = "input/"
+  }
+}
+`, testAccObsBucketNotification_base(name))
+}
+
+func testAccObsBucketNotification_update(name string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "huaweicloud_obs_bucket_notification" "test" {
+  bucket = huaweicloud_obs_bucket.test.bucket
+
+  topic_configurations {
+    id            = "ingestion"
+    topic         = huaweicloud_smn_topic.test.topic_urn
+    events        = ["ObjectCreated:Put", "ObjectCreated:CompleteMultipartUpload"]
+    filter_suffix = ".csv"
+  }
+}
+`, testAccObsBucketNotification_base(name))
+}
@@ -1,8 +1,12 @@
 package obs
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -10,9 +14,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/chnsz/golangsdk/openstack/obs"
+	th "github.com/chnsz/golangsdk/testhelper"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance/mock"
 )
 
 func TestAccObsBucketObject_source(t *testing.T) {
@@ -93,6 +99,55 @@ func TestAccObsBucketObject_content(t *testing.T) {
 	})
 }
 
+func TestAccObsBucketObject_multipart(t *testing.T) {
+	rInt := acctest.RandInt()
+	resourceName := "huaweicloud_obs_bucket_object.object"
+	copyResourceName := "huaweicloud_obs_bucket_object.copy"
+
+	tmpFile, err := os.CreateTemp("", "tf-acc-obs-obj-multipart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	// write 12 MB data to the tempfile, which is uploaded in 3 parts
+	err = os.WriteFile(tmpFile.Name(), bytes.Repeat([]byte("a"), 12*1024*1024), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkpointFile := tmpFile.Name() + ".checkpoint"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck: func() {
+			acceptance.TestAccPreCheck(t)
+			acceptance.TestAccPreCheckOBS(t)
+		},
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      testAccCheckObsBucketObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccObsBucketObjectConfig_multipart(rInt, tmpFile.Name(), checkpointFile, 2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckObsBucketObjectExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "size", "12582912"),
+					resource.TestMatchResourceAttr(resourceName, "etag", regexp.MustCompile(`-3$`)),
+					testAccCheckObsBucketObjectExists(copyResourceName),
+					resource.TestCheckResourceAttr(copyResourceName, "size", "12582912"),
+					resource.TestCheckResourceAttr(copyResourceName, "content_type", "application/octet-stream"),
+				),
+			},
+			{
+				// changing the upload arguments does not upload the object again
+				Config: testAccObsBucketObjectConfig_multipart(rInt, tmpFile.Name(), checkpointFile, 4),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "concurrency", "4"),
+					resource.TestMatchResourceAttr(resourceName, "etag", regexp.MustCompile(`-3$`)),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckObsBucketObjectDestroy(s *terraform.State) error {
 	conf := acceptance.TestAccProvider.Meta().(*config.Config)
 	obsClient, err := conf.ObjectStorageClient(acceptance.HW_REGION_NAME)
@@ -238,3 +293,144 @@ encryption   = true
 }
 `, randInt, source)
 }
+
+func testAccObsBucketObjectConfig_multipart(randInt int, source, checkpointFile string, concurrency int) string {
+	return fmt.Sprintf(`
+resource "huaweicloud_obs_bucket" "object_bucket" {
+  bucket        = "tf-acc-test-bucket-%[1]d"
+  force_destroy = true
+}
+
+resource "huaweicloud_obs_bucket_object" "object" {
+  bucket              = huaweicloud_obs_bucket.object_bucket.bucket
+  key                 = "test-key"
+  source              = "%[2]s"
+  source_hash         = filemd5("%[2]s")
+  multipart_threshold = 5
+  part_size           = 5
+  concurrency         = %[4]d
+  checkpoint_file     = "%[3]s"
+}
+
+resource "huaweicloud_obs_bucket_object" "copy" {
+  bucket       = huaweicloud_obs_bucket.object_bucket.bucket
+  key          = "test-key-copy"
+  copy_source  = "${huaweicloud_obs_bucket.object_bucket.bucket}/${huaweicloud_obs_bucket_object.object.key}"
+  content_type = "application/octet-stream"
+}
+`, randInt, source, checkpointFile, concurrency)
+}
+
+func TestObsBucketObject_mockMultipart(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source")
+	checkpointFile := filepath.Join(dir, "source.checkpoint")
+	// the file of 3 MB is uploaded in 3 parts
+	if err := os.WriteFile(source, bytes.Repeat([]byte("a"), 3*1024*1024), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	server := mock.NewServer(t)
+	server.Apply(t, "huaweicloud_obs_bucket", map[string]interface{}{"bucket": "tf-mock-bucket", "acl": "private"})
+	config := map[string]interface{}{
+		"bucket":              "tf-mock-bucket",
+		"key":                 "test-key",
+		"source":              source,
+		"source_hash":         "v1",
+		"multipart_threshold": 1,
+		"part_size":           1,
+		"concurrency":         2,
+		"checkpoint_file":     checkpointFile,
+	}
+	// the file is put in a single request if it's not larger than the default threshold
+	singlePutConfig := mergeConfig(config, map[string]interface{}{"source_hash": "v4"})
+	delete(singlePutConfig, "multipart_threshold")
+	mock.Lifecycle{
+		ResourceType: "huaweicloud_obs_bucket_object",
+		Steps: []mock.Step{
+			{
+				Config: config,
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "3145728", state.Attributes["size"])
+					th.AssertEquals(t, true, strings.HasSuffix(state.Attributes["etag"], "-3"))
+					// the checkpoint file is removed after the upload is completed
+					_, err := os.Stat(checkpointFile)
+					th.AssertEquals(t, true, os.IsNotExist(err))
+				},
+			},
+			{
+				Config: mergeConfig(config, map[string]interface{}{"source_hash": "v2", "concurrency": 4}),
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "v2", state.Attributes["source_hash"])
+					th.AssertEquals(t, true, strings.HasSuffix(state.Attributes["etag"], "-3"))
+				},
+			},
+			{
+				// the file is put in a single request if its size is not greater than the threshold
+				Config: mergeConfig(config, map[string]interface{}{"source_hash": "v3", "multipart_threshold": 3}),
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, false, strings.Contains(state.Attributes["etag"], "-"))
+				},
+			},
+			{
+				Config: singlePutConfig,
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "v4", state.Attributes["source_hash"])
+					th.AssertEquals(t, false, strings.Contains(state.Attributes["etag"], "-"))
+				},
+			},
+		},
+		ImportStateIdFunc: func(state *terraform.InstanceState) string {
+			return "tf-mock-bucket/" + state.ID
+		},
+		ImportStateVerifyIgnore: []string{
+			"source", "source_hash", "multipart_threshold", "part_size", "concurrency", "checkpoint_file",
+		},
+	}.Run(t, server)
+}
+
+func TestObsBucketObject_mockCopy(t *testing.T) {
+	t.Parallel()
+
+	server := mock.NewServer(t)
+	server.Apply(t, "huaweicloud_obs_bucket", map[string]interface{}{"bucket": "tf-mock-bucket", "acl": "private"})
+	server.Apply(t, "huaweicloud_obs_bucket_object", map[string]interface{}{
+		"bucket":       "tf-mock-bucket",
+		"key":          "source-key",
+		"content":      "some_bucket_content",
+		"content_type": "text/plain",
+	})
+	mock.Lifecycle{
+		ResourceType: "huaweicloud_obs_bucket_object",
+		Steps: []mock.Step{
+			{
+				Config: map[string]interface{}{
+					"bucket":      "tf-mock-bucket",
+					"key":         "test-key",
+					"copy_source": "tf-mock-bucket/source-key",
+				},
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "19", state.Attributes["size"])
+					th.AssertEquals(t, "text/plain", state.Attributes["content_type"])
+				},
+			},
+		},
+		ImportStateIdFunc: func(state *terraform.InstanceState) string {
+			return "tf-mock-bucket/" + state.ID
+		},
+		ImportStateVerifyIgnore: []string{"copy_source"},
+	}.Run(t, server)
+}
+
+func mergeConfig(base, overrides map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		result[k] = v
+	}
+	for k, v := range overrides {
+		result[k] = v
+	}
+	return result
+}
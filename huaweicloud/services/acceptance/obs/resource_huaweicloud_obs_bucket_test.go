@@ -8,8 +8,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
+	th "github.com/chnsz/golangsdk/testhelper"
+
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance/mock"
 )
 
 func TestAccObsBucket_basic(t *testing.T) {
@@ -270,6 +273,46 @@ func TestAccObsBucket_versioning(t *testing.T) {
 	})
 }
 
+func TestAccObsBucket_worm(t *testing.T) {
+	rInt := acctest.RandInt()
+	resourceName := "huaweicloud_obs_bucket.bucket"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck: func() {
+			acceptance.TestAccPreCheck(t)
+			acceptance.TestAccPreCheckOBS(t)
+		},
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      testAccCheckObsBucketDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccObsBucketConfigWithWorm(rInt, "days = 1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckObsBucketExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "object_lock_enabled", "true"),
+					resource.TestCheckResourceAttr(resourceName, "versioning", "true"),
+					resource.TestCheckResourceAttr(resourceName, "worm_policy.0.mode", "COMPLIANCE"),
+					resource.TestCheckResourceAttr(resourceName, "worm_policy.0.days", "1"),
+				),
+			},
+			{
+				Config: testAccObsBucketConfigWithWorm(rInt, "years = 1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckObsBucketExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "worm_policy.0.days", "0"),
+					resource.TestCheckResourceAttr(resourceName, "worm_policy.0.years", "1"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"acl", "force_destroy"},
+			},
+		},
+	})
+}
+
 func TestAccObsBucket_logging(t *testing.T) {
 	rInt := acctest.RandInt()
 	targetBucket := fmt.Sprintf("tf-test-log-bucket-%d", rInt)
@@ -711,6 +754,21 @@ resource "huaweicloud_obs_bucket" "bucket" {
 `, randInt)
 }
 
+func testAccObsBucketConfigWithWorm(randInt int, retention string) string {
+	return fmt.Sprintf(`
+resource "huaweicloud_obs_bucket" "bucket" {
+  bucket              = "tf-test-bucket-%d"
+  acl                 = "private"
+  versioning          = true
+  object_lock_enabled = true
+
+  worm_policy {
+    %s
+  }
+}
+`, randInt, retention)
+}
+
 func testAccObsBucketConfigWithDisableVersioning(randInt int) string {
 	return fmt.Sprintf(`
 resource "huaweicloud_obs_bucket" "bucket" {
@@ -885,3 +943,144 @@ resource "huaweicloud_obs_bucket" "bucket" {
 }
 `, randInt)
 }
+
+func TestAccObsBucket_mock(t *testing.T) {
+	server := mock.NewServer(t)
+	rInt := acctest.RandInt()
+	resourceName := "huaweicloud_obs_bucket.bucket"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { mock.PreCheck(t) },
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      server.CheckDestroy("huaweicloud_obs_bucket", "buckets"),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + testAccObsBucket_basic(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "bucket", testAccObsBucketName(rInt)),
+					resource.TestCheckResourceAttr(resourceName, "storage_class", "STANDARD"),
+					resource.TestCheckResourceAttr(resourceName, "bucket_version", "3.0"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "bar"),
+					resource.TestCheckResourceAttr(resourceName, "storage_info.0.object_number", "0"),
+				),
+			},
+			{
+				Config: server.ProviderConfig() + testAccObsBucket_basic_update(rInt),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "storage_class", "WARM"),
+					resource.TestCheckResourceAttr(resourceName, "tags.key", "value1"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"acl", "force_destroy"},
+			},
+		},
+	})
+}
+
+func TestObsBucket_mockLifecycle(t *testing.T) {
+	t.Parallel()
+
+	server := mock.NewServer(t)
+	mock.Lifecycle{
+		ResourceType: "huaweicloud_obs_bucket",
+		Steps: []mock.Step{
+			{
+				Config: map[string]interface{}{
+					"bucket":        "tf-mock-bucket",
+					"storage_class": "STANDARD",
+					"acl":           "private",
+					"tags":          map[string]interface{}{"foo": "bar"},
+				},
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "false", state.Attributes["versioning"])
+					th.AssertEquals(t, "0", state.Attributes["lifecycle_rule.#"])
+				},
+			},
+			{
+				Config: map[string]interface{}{
+					"bucket":        "tf-mock-bucket",
+					"storage_class": "WARM",
+					"acl":           "private",
+					"versioning":    true,
+					"quota":         1000000000,
+					"tags":          map[string]interface{}{"foo": "bar_updated"},
+					"lifecycle_rule": []interface{}{
+						map[string]interface{}{
+							"name":    "rule1",
+							"prefix":  "path1/",
+							"enabled": true,
+							"expiration": []interface{}{
+								map[string]interface{}{"days": 365},
+							},
+						},
+					},
+					"cors_rule": []interface{}{
+						map[string]interface{}{
+							"allowed_origins": []interface{}{"https://www.example.com"},
+							"allowed_methods": []interface{}{"PUT", "POST"},
+							"max_age_seconds": 3000,
+						},
+					},
+					"website": []interface{}{
+						map[string]interface{}{
+							"index_document": "index.html",
+							"error_document": "error.html",
+						},
+					},
+				},
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "WARM", state.Attributes["storage_class"])
+					th.AssertEquals(t, "true", state.Attributes["versioning"])
+					th.AssertEquals(t, "1000000000", state.Attributes["quota"])
+					th.AssertEquals(t, "rule1", state.Attributes["lifecycle_rule.0.name"])
+					th.AssertEquals(t, "index.html", state.Attributes["website.0.index_document"])
+				},
+			},
+		},
+		ImportStateVerifyIgnore: []string{"acl", "force_destroy"},
+	}.Run(t, server)
+}
+
+func TestObsBucket_mockWorm(t *testing.T) {
+	t.Parallel()
+
+	server := mock.NewServer(t)
+	mock.Lifecycle{
+		ResourceType: "huaweicloud_obs_bucket",
+		Steps: []mock.Step{
+			{
+				Config: map[string]interface{}{
+					"bucket":              "tf-mock-worm-bucket",
+					"acl":                 "private",
+					"versioning":          true,
+					"object_lock_enabled": true,
+					"worm_policy": []interface{}{
+						map[string]interface{}{"days": 30},
+					},
+				},
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "true", state.Attributes["object_lock_enabled"])
+					th.AssertEquals(t, "COMPLIANCE", state.Attributes["worm_policy.0.mode"])
+					th.AssertEquals(t, "30", state.Attributes["worm_policy.0.days"])
+				},
+			},
+			{
+				Config: map[string]interface{}{
+					"bucket":              "tf-mock-worm-bucket",
+					"acl":                 "private",
+					"versioning":          true,
+					"object_lock_enabled": true,
+				},
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "true", state.Attributes["object_lock_enabled"])
+					th.AssertEquals(t, "0", state.Attributes["worm_policy.#"])
+				},
+			},
+		},
+		ImportStateVerifyIgnore: []string{"acl", "force_destroy"},
+	}.Run(t, server)
+}
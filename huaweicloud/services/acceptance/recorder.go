@@ -0,0 +1,144 @@
+package acceptance
+
+import (
+	"errors"
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+var (
+	// testRandLock and testRands are the random generators of the tests in record or replay mode, which are seeded
+	// with the test names, so the random names and CIDRs are the same as the recorded ones
+	testRandLock sync.Mutex
+	testRands    = make(map[string]*rand.Rand)
+)
+
+// initRecorderEnv prepares the environment variables of the provider in record or replay mode:
+//   - the project ID and domain ID are queried through the recorded IAM APIs, instead of the real ones
+//     in HW_PROJECT_ID and HW_DOMAIN_ID
+//   - the fake credentials are used in replay mode if they're not specified, since the requests are not sent
+func initRecorderEnv() {
+	mode := config.RecorderMode()
+	if mode == "" {
+		return
+	}
+
+	os.Unsetenv("HW_PROJECT_ID")
+	os.Unsetenv("HW_DOMAIN_ID")
+	if mode == config.RecorderModeReplay {
+		if HW_ACCESS_KEY == "" || HW_SECRET_KEY == "" {
+			HW_ACCESS_KEY, HW_SECRET_KEY = "RECORDEDACCESSKEY", "recorded-secret-key"
+			os.Setenv("HW_ACCESS_KEY", HW_ACCESS_KEY)
+			os.Setenv("HW_SECRET_KEY", HW_SECRET_KEY)
+		}
+	}
+}
+
+// startRecorder records or replays the API interactions of the test in the cassette named with the test name, the
+// test is skipped in replay mode if the cassette has not been recorded.
+func startRecorder(t *testing.T) {
+	if recorder := config.ActiveRecorder(); recorder != nil && recorder.Name() == t.Name() {
+		// the pre-check is called more than once in the test
+		return
+	}
+
+	recorder, err := config.StartRecorder(t.Name())
+	if errors.Is(err, config.ErrCassetteNotFound) {
+		t.Skipf("Skip the test in replay mode: %s", err)
+	}
+	if err != nil {
+		t.Fatalf("failed to start the recorder: %s", err)
+	}
+	if recorder == nil {
+		return
+	}
+
+	t.Cleanup(func() {
+		if err := recorder.Stop(); err != nil {
+			t.Errorf("failed to stop the recorder: %s", err)
+		}
+	})
+}
+
+// testRand returns the random generator of the calling test in record or replay mode, or nil if the recorder mode
+// is disabled or it's not called in a test.
+func testRand() *rand.Rand {
+	if config.RecorderMode() == "" {
+		return nil
+	}
+
+	name := callerTestName()
+	if name == "" {
+		return nil
+	}
+
+	testRandLock.Lock()
+	defer testRandLock.Unlock()
+	if r, ok := testRands[name]; ok {
+		return r
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	r := rand.New(rand.NewSource(int64(h.Sum64()))) //nolint:gosec
+	testRands[name] = r
+	return r
+}
+
+// callerTestName returns the name of the test function in the call stack, e.g. TestAccVpc_basic.
+func callerTestName() string {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		// the function name is in the format of {package path}.{function name}[.func1]
+		function := frame.Function[strings.LastIndex(frame.Function, "/")+1:]
+		for _, part := range strings.Split(function, ".") {
+			if strings.HasPrefix(part, "Test") {
+				return part
+			}
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+func randString(length int) string {
+	return randStringFromCharSet(length, acctest.CharSetAlphaNum)
+}
+
+func randStringFromCharSet(length int, charSet string) string {
+	r := testRand()
+	if r == nil {
+		return acctest.RandStringFromCharSet(length, charSet)
+	}
+
+	testRandLock.Lock()
+	defer testRandLock.Unlock()
+	result := make([]byte, length)
+	for i := range result {
+		result[i] = charSet[r.Intn(len(charSet))]
+	}
+	return string(result)
+}
+
+func randIntRange(min, max int) int {
+	r := testRand()
+	if r == nil {
+		return acctest.RandIntRange(min, max)
+	}
+
+	testRandLock.Lock()
+	defer testRandLock.Unlock()
+	return r.Intn(max-min) + min
+}
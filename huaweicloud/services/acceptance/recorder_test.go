@@ -0,0 +1,23 @@
+package acceptance
+
+import (
+	"testing"
+)
+
+func TestRecorder_randomNames(t *testing.T) {
+	t.Setenv("HW_RECORDER_MODE", "replay")
+
+	name := RandomAccResourceName()
+	cidr := RandomCidr()
+	AssertEquals(t, callerTestName(), "TestRecorder_randomNames")
+
+	// the random values are the same in every test run
+	testRandLock.Lock()
+	delete(testRands, "TestRecorder_randomNames")
+	testRandLock.Unlock()
+	AssertEquals(t, RandomAccResourceName(), name)
+	AssertEquals(t, RandomCidr(), cidr)
+	if RandomAccResourceName() == name {
+		t.Fatalf("expected a different name in the second call")
+	}
+}
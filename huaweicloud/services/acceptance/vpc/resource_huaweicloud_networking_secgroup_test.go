@@ -8,9 +8,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/chnsz/golangsdk/openstack/networking/v1/security/securitygroups"
+	th "github.com/chnsz/golangsdk/testhelper"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance/mock"
 )
 
 func getNetworkSecGroupResourceFunc(cfg *config.Config, state *terraform.ResourceState) (interface{}, error) {
@@ -170,3 +172,71 @@ resource "huaweicloud_networking_secgroup" "secgroup_1" {
 }
 `, name)
 }
+
+func TestAccNetworkingV3SecGroup_mock(t *testing.T) {
+	server := mock.NewServer(t)
+	name := acceptance.RandomAccResourceName()
+	resourceName := "huaweicloud_networking_secgroup.secgroup_1"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { mock.PreCheck(t) },
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      server.CheckDestroy("huaweicloud_networking_secgroup", "security-groups"),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + testAccSecGroup_basic(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "rules.#", "4"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "bar"),
+				),
+			},
+			{
+				Config: server.ProviderConfig() + testAccSecGroup_update(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "description", "security group acceptance test updated"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "bar_update"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"delete_default_rules"},
+			},
+		},
+	})
+}
+
+func TestNetworkingV3SecGroup_mockLifecycle(t *testing.T) {
+	t.Parallel()
+
+	server := mock.NewServer(t)
+	mock.Lifecycle{
+		ResourceType: "huaweicloud_networking_secgroup",
+		Steps: []mock.Step{
+			{
+				Config: map[string]interface{}{
+					"name":        "secgroup-mock",
+					"description": "created by mock test",
+					"tags":        map[string]interface{}{"foo": "bar"},
+				},
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "4", state.Attributes["rules.#"])
+				},
+			},
+			{
+				Config: map[string]interface{}{
+					"name":        "secgroup-mock-updated",
+					"description": "updated by mock test",
+					"tags":        map[string]interface{}{"foo": "bar_updated"},
+				},
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "secgroup-mock-updated", state.Attributes["name"])
+					th.AssertEquals(t, "bar_updated", state.Attributes["tags.foo"])
+				},
+			},
+		},
+		ImportStateVerifyIgnore: []string{"delete_default_rules"},
+	}.Run(t, server)
+}
@@ -10,9 +10,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/chnsz/golangsdk/openstack/networking/v1/subnets"
+	th "github.com/chnsz/golangsdk/testhelper"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance/mock"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils/fmtp"
 )
 
@@ -302,3 +304,94 @@ resource "huaweicloud_vpc_subnet" "test" {
 }
 `, testAccVpcSubnet_base(rName), rName)
 }
+
+func TestAccVpcSubnetV1_mock(t *testing.T) {
+	server := mock.NewServer(t)
+	rName := fmt.Sprintf("tf-acc-test-%s", acctest.RandString(5))
+	resourceName := "huaweicloud_vpc_subnet.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { mock.PreCheck(t) },
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      server.CheckDestroy("huaweicloud_vpc_subnet", "subnets"),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + testAccVpcSubnetV1_mock(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", rName),
+					resource.TestCheckResourceAttr(resourceName, "dhcp_enable", "true"),
+					resource.TestCheckResourceAttrSet(resourceName, "ipv4_subnet_id"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestVpcSubnetV1_mockLifecycle(t *testing.T) {
+	t.Parallel()
+
+	server := mock.NewServer(t)
+	vpc := server.Apply(t, "huaweicloud_vpc", map[string]interface{}{
+		"name": "vpc-mock",
+		"cidr": "192.168.0.0/16",
+	})
+	mock.Lifecycle{
+		ResourceType: "huaweicloud_vpc_subnet",
+		Steps: []mock.Step{
+			{
+				Config: map[string]interface{}{
+					"name":        "subnet-mock",
+					"cidr":        "192.168.0.0/24",
+					"gateway_ip":  "192.168.0.1",
+					"vpc_id":      vpc.ID,
+					"description": "created by mock test",
+					"tags":        map[string]interface{}{"foo": "bar"},
+				},
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, server.Resource("subnets", state.ID)["neutron_subnet_id"], state.Attributes["subnet_id"])
+					th.AssertEquals(t, "100.125.1.250", state.Attributes["primary_dns"])
+				},
+			},
+			{
+				Config: map[string]interface{}{
+					"name":        "subnet-mock-updated",
+					"cidr":        "192.168.0.0/24",
+					"gateway_ip":  "192.168.0.1",
+					"vpc_id":      vpc.ID,
+					"description": "updated by mock test",
+					"dns_list":    []interface{}{"100.125.1.250", "100.125.129.250"},
+					"tags":        map[string]interface{}{"foo": "bar_updated"},
+				},
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "subnet-mock-updated", state.Attributes["name"])
+					th.AssertEquals(t, "100.125.129.250", state.Attributes["secondary_dns"])
+				},
+			},
+		},
+	}.Run(t, server)
+}
+
+func testAccVpcSubnetV1_mock(rName string) string {
+	return fmt.Sprintf(`
+resource "huaweicloud_vpc" "test" {
+  name = "%[1]s"
+  cidr = "192.168.0.0/16"
+}
+
+resource "huaweicloud_vpc_subnet" "test" {
+  name       = "%[1]s"
+  cidr       = "192.168.0.0/24"
+  gateway_ip = "192.168.0.1"
+  vpc_id     = huaweicloud_vpc.test.id
+
+  tags = {
+    foo = "bar"
+  }
+}
+`, rName)
+}
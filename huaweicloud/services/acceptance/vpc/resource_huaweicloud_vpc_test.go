@@ -9,9 +9,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	"github.com/chnsz/golangsdk/openstack/networking/v1/vpcs"
+	th "github.com/chnsz/golangsdk/testhelper"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/services/acceptance/mock"
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils/fmtp"
 )
 
@@ -456,3 +458,75 @@ resource "huaweicloud_vpc" "test2" {
 }
 `, name1, region, name2)
 }
+
+func TestAccVpcV1_mock(t *testing.T) {
+	server := mock.NewServer(t)
+	rName := acceptance.RandomAccResourceName()
+	resourceName := "huaweicloud_vpc.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { mock.PreCheck(t) },
+		ProviderFactories: acceptance.TestAccProviderFactories,
+		CheckDestroy:      server.CheckDestroy("huaweicloud_vpc", "vpcs"),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + testAccVpcV1_basic(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", rName),
+					resource.TestCheckResourceAttr(resourceName, "status", "OK"),
+					resource.TestCheckResourceAttr(resourceName, "tags.foo", "bar"),
+				),
+			},
+			{
+				Config: server.ProviderConfig() + testAccVpcV1_update(rName+"_updated"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", rName+"_updated"),
+					resource.TestCheckResourceAttr(resourceName, "tags.key", "value_updated"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestVpcV1_mockLifecycle(t *testing.T) {
+	t.Parallel()
+
+	server := mock.NewServer(t)
+	mock.Lifecycle{
+		ResourceType: "huaweicloud_vpc",
+		Steps: []mock.Step{
+			{
+				Config: map[string]interface{}{
+					"name":        "vpc-mock",
+					"cidr":        "192.168.0.0/16",
+					"description": "created by mock test",
+					"tags":        map[string]interface{}{"foo": "bar", "key": "value"},
+				},
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "OK", state.Attributes["status"])
+					th.AssertEquals(t, "192.168.0.0/16", server.Resource("vpcs", state.ID)["cidr"])
+				},
+			},
+			{
+				Config: map[string]interface{}{
+					"name":            "vpc-mock-updated",
+					"cidr":            "192.168.0.0/16",
+					"description":     "updated by mock test",
+					"secondary_cidrs": []interface{}{"168.10.0.0/16"},
+					"tags":            map[string]interface{}{"foo1": "bar", "key": "value_updated"},
+				},
+				Check: func(t *testing.T, state *terraform.InstanceState) {
+					th.AssertEquals(t, "vpc-mock-updated", state.Attributes["name"])
+					th.AssertEquals(t, "1", state.Attributes["secondary_cidrs.#"])
+					th.AssertDeepEquals(t, []interface{}{"168.10.0.0/16"}, server.Resource("vpcs", state.ID)["extend_cidrs"])
+					th.AssertEquals(t, "value_updated", state.Attributes["tags.key"])
+				},
+			},
+		},
+	}.Run(t, server)
+}
@@ -711,15 +711,15 @@ func updateInstanceTags(client *golangsdk.ServiceClient, d *schema.ResourceData)
 	var (
 		err            error
 		oldVal, newVal = d.GetChange("tags")
-		rmTags         = oldVal.(map[string]interface{})
-		addTags        = newVal.(map[string]interface{})
 		instanceId     = d.Id()
 		httpUrl        = "v2/{project_id}/apigw/instances/{instance_id}/instance-tags/action"
 		generalPath    = client.Endpoint + httpUrl
 	)
 	generalPath = strings.ReplaceAll(generalPath, "{project_id}", client.ProjectID)
 	generalPath = strings.ReplaceAll(generalPath, "{instance_id}", instanceId)
+	rmTags, addTags := utils.DiffTags(oldVal.(map[string]interface{}), newVal.(map[string]interface{}))
 
+	// remove the tags which are no longer present
 	if len(rmTags) > 0 {
 		opt := golangsdk.RequestOpts{
 			KeepResponseBody: true,
@@ -734,6 +734,7 @@ func updateInstanceTags(client *golangsdk.ServiceClient, d *schema.ResourceData)
 			return fmt.Errorf("unable to remove the instance tags: %s", err)
 		}
 	}
+	// create the new tags and update the tags whose value is changed
 	if len(addTags) > 0 {
 		opt := golangsdk.RequestOpts{
 			KeepResponseBody: true,
@@ -737,19 +737,19 @@ func resourceASGroupUpdate(ctx context.Context, d *schema.ResourceData, meta int
 
 	// update tags
 	if d.HasChange("tags") {
-		// remove oldTag tags and set newTag tags
+		// remove the tags which are no longer present, then create the new tags and update the tags whose value
+		// is changed
 		oldTag, newTag := d.GetChange("tags")
-		oldRaw := oldTag.(map[string]interface{})
-		if len(oldRaw) > 0 {
-			tagList := expandGroupsTags(oldRaw)
+		removedTags, upsertedTags := utils.DiffTags(oldTag.(map[string]interface{}), newTag.(map[string]interface{}))
+		if len(removedTags) > 0 {
+			tagList := expandGroupsTags(removedTags)
 			if tagErr := tags.Delete(asClient, asgID, tagList).ExtractErr(); tagErr != nil {
 				return diag.Errorf("error deleting tags of AS group %s: %s", asgID, tagErr)
 			}
 		}
 
-		newRaw := newTag.(map[string]interface{})
-		if len(newRaw) > 0 {
-			tagList := expandGroupsTags(newRaw)
+		if len(upsertedTags) > 0 {
+			tagList := expandGroupsTags(upsertedTags)
 			if tagErr := tags.Create(asClient, asgID, tagList).ExtractErr(); tagErr != nil {
 				return diag.Errorf("error setting tags of AS group %s: %s", asgID, tagErr)
 			}
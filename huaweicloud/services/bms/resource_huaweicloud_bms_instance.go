@@ -350,7 +350,7 @@ func resourceBmsInstanceCreate(ctx context.Context, d *schema.ResourceData, meta
 		createOpts.RootVolume = &volRequest
 	}
 
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	if len(tagRaw) > 0 {
 		taglist := utils.ExpandResourceTags(tagRaw)
 		createOpts.ServerTags = taglist
@@ -497,7 +497,7 @@ func resourceBmsInstanceUpdate(ctx context.Context, d *schema.ResourceData, meta
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		err = utils.UpdateResourceTags(bmsClient, d, "baremetalservers", instanceId)
 		if err != nil {
 			return diag.Errorf("error updating tags of bms server: %s", err)
@@ -0,0 +1,189 @@
+package bss
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/common"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+// @API BSS POST /v2/bills/ratings/on-demand-resources
+// @API BSS POST /v2/bills/ratings/period-resources/subscribe-rate
+func DataSourcePrice() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcePriceRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"charging_mode": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"prePaid", "postPaid"}, false),
+			},
+			"period_unit": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"period"},
+				ValidateFunc: validation.StringInSlice([]string{"month", "year"}, false),
+			},
+			"period": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"period_unit"},
+				ValidateFunc: validation.IntBetween(1, 9),
+			},
+			"products": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"ecs", "evs", "eip_bandwidth", "rds"}, false),
+						},
+						"spec": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"availability_zone": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"size": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+						"quantity": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Default:  1,
+						},
+					},
+				},
+			},
+			"currency": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"amount": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"official_website_amount": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"discount_amount": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"prices": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"index": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"amount": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"official_website_amount": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"discount_amount": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func buildPriceProducts(d *schema.ResourceData) []common.PriceProduct {
+	products := d.Get("products").([]interface{})
+	result := make([]common.PriceProduct, len(products))
+	for i, v := range products {
+		product := v.(map[string]interface{})
+		result[i] = common.PriceProduct{
+			Type:             product["type"].(string),
+			Spec:             product["spec"].(string),
+			AvailabilityZone: product["availability_zone"].(string),
+			Size:             product["size"].(int),
+			Quantity:         product["quantity"].(int),
+		}
+	}
+	return result
+}
+
+func flattenProductPrices(prices []common.ProductPrice) []interface{} {
+	result := make([]interface{}, 0, len(prices))
+	for _, p := range prices {
+		// the ID of the product is its index in the inquiry
+		index, err := strconv.Atoi(p.ID)
+		if err != nil {
+			continue
+		}
+		result = append(result, map[string]interface{}{
+			"index":                   index,
+			"amount":                  p.Amount,
+			"official_website_amount": p.OfficialWebsiteAmount,
+			"discount_amount":         p.DiscountAmount,
+		})
+	}
+	return result
+}
+
+func dataSourcePriceRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	products := buildPriceProducts(d)
+
+	var result *common.PriceResult
+	var err error
+	if d.Get("charging_mode").(string) == "prePaid" {
+		periodUnit := d.Get("period_unit").(string)
+		if periodUnit == "" {
+			return diag.Errorf("period_unit and period are required when charging_mode is prePaid")
+		}
+		result, err = common.QueryPeriodPrice(cfg, region, periodUnit, d.Get("period").(int), products)
+	} else {
+		result, err = common.QueryOnDemandPrice(cfg, region, products)
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	uuid, err := uuid.GenerateUUID()
+	if err != nil {
+		return diag.Errorf("unable to generate ID: %s", err)
+	}
+	d.SetId(uuid)
+
+	mErr := multierror.Append(
+		d.Set("region", region),
+		d.Set("currency", result.Currency),
+		d.Set("amount", result.Amount),
+		d.Set("official_website_amount", result.OfficialWebsiteAmount),
+		d.Set("discount_amount", result.DiscountAmount),
+		d.Set("prices", flattenProductPrices(result.Products)),
+	)
+	return diag.FromErr(mErr.ErrorOrNil())
+}
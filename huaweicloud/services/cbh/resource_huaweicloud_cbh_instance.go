@@ -780,15 +780,18 @@ func resourceCBHInstanceUpdate(ctx context.Context, d *schema.ResourceData, meta
 		oRaw, nRaw := d.GetChange("tags")
 		oMap := oRaw.(map[string]interface{})
 		nMap := nRaw.(map[string]interface{})
+		removedTags, upsertedTags := utils.DiffTags(oMap, nMap)
 
-		if len(oMap) > 0 {
-			if err = doActionInstanceTags(resourceId, "delete", client, oMap); err != nil {
+		// remove the tags which are no longer present
+		if len(removedTags) > 0 {
+			if err = doActionInstanceTags(resourceId, "delete", client, removedTags); err != nil {
 				return diag.FromErr(err)
 			}
 		}
 
-		if len(nMap) > 0 {
-			if err := doActionInstanceTags(resourceId, "create", client, nMap); err != nil {
+		// create the new tags and update the tags whose value is changed
+		if len(upsertedTags) > 0 {
+			if err := doActionInstanceTags(resourceId, "create", client, upsertedTags); err != nil {
 				return diag.FromErr(err)
 			}
 		}
@@ -939,7 +939,7 @@ func resourceVaultUpdate(ctx context.Context, d *schema.ResourceData, meta inter
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		if err = utils.UpdateResourceTags(client, d, "vault", vaultId); err != nil {
 			return diag.Errorf("failed to update tags: %s", err)
 		}
@@ -462,11 +462,13 @@ func updateBandwidthPackageTags(client *golangsdk.ServiceClient, d *schema.Resou
 	oMap := oRaw.(map[string]interface{})
 	nMap := nRaw.(map[string]interface{})
 
-	// remove old tags
-	if len(oMap) > 0 {
+	removedTags, upsertedTags := utils.DiffTags(oMap, nMap)
+
+	// remove the tags which are no longer present
+	if len(removedTags) > 0 {
 		updateBandwidthPackageTagsOpt.JSONBody = map[string]interface{}{
 			"action": "delete",
-			"tags":   utils.ExpandResourceTagsMap(oMap),
+			"tags":   utils.ExpandResourceTagsMap(removedTags),
 		}
 		_, err := client.Request("POST", updateBandwidthPackageTagsPath, &updateBandwidthPackageTagsOpt)
 		if err != nil {
@@ -474,11 +476,11 @@ func updateBandwidthPackageTags(client *golangsdk.ServiceClient, d *schema.Resou
 		}
 	}
 
-	// set new tags
-	if len(nMap) > 0 {
+	// create the new tags and update the tags whose value is changed
+	if len(upsertedTags) > 0 {
 		updateBandwidthPackageTagsOpt.JSONBody = map[string]interface{}{
 			"action": "create",
-			"tags":   utils.ExpandResourceTagsMap(nMap),
+			"tags":   utils.ExpandResourceTagsMap(upsertedTags),
 		}
 		_, err := client.Request("POST", updateBandwidthPackageTagsPath, &updateBandwidthPackageTagsOpt)
 		if err != nil {
@@ -347,18 +347,19 @@ func deleteResourceTags(client *golangsdk.ServiceClient, id, domainID string, ta
 
 func updateResourceTags(client *golangsdk.ServiceClient, d *schema.ResourceData, domainID string) error {
 	oRaw, nRaw := d.GetChange("tags")
+	removedTags, upsertedTags := utils.DiffTags(oRaw.(map[string]interface{}), nRaw.(map[string]interface{}))
 
-	// remove old tags
-	if oMap := oRaw.(map[string]interface{}); len(oMap) > 0 {
-		err := deleteResourceTags(client, d.Id(), domainID, oMap)
+	// remove the tags which are no longer present
+	if len(removedTags) > 0 {
+		err := deleteResourceTags(client, d.Id(), domainID, removedTags)
 		if err != nil {
 			return err
 		}
 	}
 
-	// set new tags
-	if nMap := nRaw.(map[string]interface{}); len(nMap) > 0 {
-		err := createResourceTags(client, d.Id(), domainID, nMap)
+	// create the new tags and update the tags whose value is changed
+	if len(upsertedTags) > 0 {
+		err := createResourceTags(client, d.Id(), domainID, upsertedTags)
 		if err != nil {
 			return err
 		}
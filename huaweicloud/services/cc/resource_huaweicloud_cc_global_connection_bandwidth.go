@@ -320,10 +320,12 @@ func updateTags(client *golangsdk.ServiceClient, d *schema.ResourceData) error {
 		},
 	}
 
-	// remove old tags
-	if len(oMap) > 0 {
+	removedTags, upsertedTags := utils.DiffTags(oMap, nMap)
+
+	// remove the tags which are no longer present
+	if len(removedTags) > 0 {
 		manageTagsOpt.JSONBody = map[string]interface{}{
-			"tags": utils.ExpandResourceTags(oMap),
+			"tags": utils.ExpandResourceTags(removedTags),
 		}
 		deleteTagsPath := strings.ReplaceAll(manageTagsPath, "{action}", "delete")
 		_, err := client.Request("POST", deleteTagsPath, &manageTagsOpt)
@@ -332,10 +334,10 @@ func updateTags(client *golangsdk.ServiceClient, d *schema.ResourceData) error {
 		}
 	}
 
-	// set new tags
-	if len(nMap) > 0 {
+	// create the new tags and update the tags whose value is changed
+	if len(upsertedTags) > 0 {
 		manageTagsOpt.JSONBody = map[string]interface{}{
-			"tags": utils.ExpandResourceTags(nMap),
+			"tags": utils.ExpandResourceTags(upsertedTags),
 		}
 		createTagsPath := strings.ReplaceAll(manageTagsPath, "{action}", "create")
 		_, err := client.Request("POST", createTagsPath, &manageTagsOpt)
@@ -0,0 +1,548 @@
+package cce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/cce/v3/addons"
+	"github.com/chnsz/golangsdk/openstack/cce/v3/templates"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+const defaultUpgradeStrategyType = "inPlaceRollingUpdate"
+
+var versionSeparatorRegexp = regexp.MustCompile(`[.\-]+`)
+
+func clusterUpgradeStrategySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"type": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Default:  defaultUpgradeStrategyType,
+				},
+				"user_defined_step": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      20,
+					ValidateFunc: validation.IntBetween(1, 40),
+				},
+				"node_pool_order": {
+					Type:     schema.TypeMap,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeInt},
+				},
+				"skipped_check_items": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"addons": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"version": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"values": {
+								Type:         schema.TypeString,
+								Optional:     true,
+								ValidateFunc: validation.StringIsJSON,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// compareVersions compares the cluster or add-on versions by the numeric parts, e.g. v1.27.3-r0 and v1.28,
+// the missing parts are considered as 0.
+func compareVersions(v1, v2 string) int {
+	parts1 := versionSeparatorRegexp.Split(strings.TrimPrefix(strings.ToLower(v1), "v"), -1)
+	parts2 := versionSeparatorRegexp.Split(strings.TrimPrefix(strings.ToLower(v2), "v"), -1)
+	for i := 0; i < len(parts1) || i < len(parts2); i++ {
+		var p1, p2 int
+		if i < len(parts1) {
+			p1, _ = strconv.Atoi(strings.TrimPrefix(parts1[i], "r"))
+		}
+		if i < len(parts2) {
+			p2, _ = strconv.Atoi(strings.TrimPrefix(parts2[i], "r"))
+		}
+		if p1 != p2 {
+			if p1 < p2 {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// validateClusterVersionChange rejects the downgrade of the cluster version during the plan, which is not supported
+// by CCE.
+func validateClusterVersionChange(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.HasChange("cluster_version") {
+		return nil
+	}
+
+	oldVersion, newVersion := d.GetChange("cluster_version")
+	if oldVersion.(string) != "" && newVersion.(string) != "" &&
+		compareVersions(newVersion.(string), oldVersion.(string)) < 0 {
+		return fmt.Errorf("the cluster version can not be downgraded from %s to %s", oldVersion, newVersion)
+	}
+	return nil
+}
+
+// getClusterUpgradeVersions returns the current version and the target version to upgrade, the target version is
+// the latest available version which matches the version in the configuration, e.g. v1.28.3-r0 for v1.28.
+func getClusterUpgradeVersions(client *golangsdk.ServiceClient, clusterID,
+	version string) (currentVersion, targetVersion string, err error) {
+	requestPath := client.Endpoint + "api/v3/projects/{project_id}/clusters/{cluster_id}/upgradeinfo"
+	requestPath = strings.ReplaceAll(requestPath, "{project_id}", client.ProjectID)
+	requestPath = strings.ReplaceAll(requestPath, "{cluster_id}", clusterID)
+	requestOpt := golangsdk.RequestOpts{
+		KeepResponseBody: true,
+	}
+	resp, err := client.Request("GET", requestPath, &requestOpt)
+	if err != nil {
+		return "", "", fmt.Errorf("error querying the upgrade information of CCE cluster (%s): %s", clusterID, err)
+	}
+	respBody, err := utils.FlattenResponse(resp)
+	if err != nil {
+		return "", "", err
+	}
+
+	currentVersion = utils.PathSearch("spec.versionInfo.release", respBody, "").(string)
+	if patch := utils.PathSearch("spec.versionInfo.patch", respBody, "").(string); patch != "" &&
+		!strings.Contains(currentVersion, "-") {
+		currentVersion = currentVersion + "-" + patch
+	}
+
+	availableVersions := utils.ExpandToStringList(
+		utils.PathSearch("spec.versionInfo.targetVersions", respBody, make([]interface{}, 0)).([]interface{}))
+	for _, v := range availableVersions {
+		if v != version && !strings.HasPrefix(v, version+".") && !strings.HasPrefix(v, version+"-") {
+			continue
+		}
+		if targetVersion == "" || compareVersions(v, targetVersion) > 0 {
+			targetVersion = v
+		}
+	}
+	if targetVersion == "" {
+		return "", "", fmt.Errorf("the cluster version %s is not available for the upgrade of CCE cluster (%s), "+
+			"the available versions are: %s", version, clusterID, strings.Join(availableVersions, ", "))
+	}
+	return currentVersion, targetVersion, nil
+}
+
+func getClusterUpgradeTask(client *golangsdk.ServiceClient, requestPath string) (interface{}, error) {
+	requestOpt := golangsdk.RequestOpts{
+		KeepResponseBody: true,
+	}
+	resp, err := client.Request("GET", requestPath, &requestOpt)
+	if err != nil {
+		return nil, err
+	}
+	return utils.FlattenResponse(resp)
+}
+
+func clusterUpgradeTaskRefreshFunc(client *golangsdk.ServiceClient, requestPath string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		respBody, err := getClusterUpgradeTask(client, requestPath)
+		if err != nil {
+			return nil, "ERROR", err
+		}
+
+		phase := utils.PathSearch("status.phase", respBody, "").(string)
+		switch phase {
+		case "Success":
+			return respBody, "COMPLETED", nil
+		case "Failed", "Error", "Pause":
+			return respBody, phase, nil
+		default:
+			// the phases of the pending tasks include Init, Queuing and Running
+			return respBody, "PENDING", nil
+		}
+	}
+}
+
+// waitForClusterUpgradeTask waits for the pre-check or upgrade task, the task response is returned with the error
+// if the task fails, so that the failure reasons can be reported.
+func waitForClusterUpgradeTask(ctx context.Context, client *golangsdk.ServiceClient, requestPath string,
+	timeout time.Duration) (interface{}, error) {
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"PENDING"},
+		Target:       []string{"COMPLETED"},
+		Refresh:      clusterUpgradeTaskRefreshFunc(client, requestPath),
+		Timeout:      timeout,
+		Delay:        10 * time.Second,
+		PollInterval: 15 * time.Second,
+	}
+	return stateConf.WaitForStateContext(ctx)
+}
+
+// buildPreCheckReport returns the check items which are not passed, the node check items are prefixed with the
+// node name.
+func buildPreCheckReport(respBody interface{}) (failures, warnings []string) {
+	collect := func(prefix string, items []interface{}) {
+		for _, item := range items {
+			phase := utils.PathSearch("phase", item, "").(string)
+			if phase == "" || phase == "Success" {
+				continue
+			}
+			line := fmt.Sprintf("[%s] %s: %s", prefix, utils.PathSearch("name", item, "").(string),
+				utils.PathSearch("message", item, "").(string))
+			if level := utils.PathSearch("level", item, "").(string); level == "Warning" || phase == "Warning" {
+				warnings = append(warnings, line)
+			} else {
+				failures = append(failures, line)
+			}
+		}
+	}
+
+	collect("cluster", utils.PathSearch("status.clusterCheckStatus.itemsStatus", respBody,
+		make([]interface{}, 0)).([]interface{}))
+	collect("add-on", utils.PathSearch("status.addonCheckStatus.itemsStatus", respBody,
+		make([]interface{}, 0)).([]interface{}))
+	nodes := utils.PathSearch("status.nodeCheckStatus.nodeStageStatus", respBody, make([]interface{}, 0)).([]interface{})
+	for _, node := range nodes {
+		collect("node "+utils.PathSearch("nodeInfo.name", node, "").(string),
+			utils.PathSearch("itemsStatus", node, make([]interface{}, 0)).([]interface{}))
+	}
+
+	if len(failures) == 0 {
+		if message := utils.PathSearch("status.message", respBody, "").(string); message != "" {
+			failures = append(failures, message)
+		}
+	}
+	return failures, warnings
+}
+
+// preCheckClusterUpgrade runs the pre-upgrade check, all failed check items are reported if the check fails.
+func preCheckClusterUpgrade(ctx context.Context, client *golangsdk.ServiceClient, d *schema.ResourceData,
+	currentVersion, targetVersion string) error {
+	clusterID := d.Id()
+	skippedItems := make([]map[string]interface{}, 0)
+	for _, name := range utils.ExpandToStringList(d.Get("upgrade_strategy.0.skipped_check_items").([]interface{})) {
+		skippedItems = append(skippedItems, map[string]interface{}{"name": name})
+	}
+
+	requestPath := client.Endpoint + "api/v3/projects/{project_id}/clusters/{cluster_id}/operation/precheck"
+	requestPath = strings.ReplaceAll(requestPath, "{project_id}", client.ProjectID)
+	requestPath = strings.ReplaceAll(requestPath, "{cluster_id}", clusterID)
+	requestOpt := golangsdk.RequestOpts{
+		KeepResponseBody: true,
+		JSONBody: map[string]interface{}{
+			"apiVersion": "v3",
+			"kind":       "PreCheckTask",
+			"spec": map[string]interface{}{
+				"clusterVersion":       currentVersion,
+				"targetVersion":        targetVersion,
+				"skippedCheckItemList": skippedItems,
+			},
+		},
+	}
+	resp, err := client.Request("POST", requestPath, &requestOpt)
+	if err != nil {
+		return fmt.Errorf("error creating the pre-upgrade check task of CCE cluster (%s): %s", clusterID, err)
+	}
+	respBody, err := utils.FlattenResponse(resp)
+	if err != nil {
+		return err
+	}
+	taskID := utils.PathSearch("metadata.uid", respBody, "").(string)
+	if taskID == "" {
+		return fmt.Errorf("unable to find the pre-upgrade check task ID of CCE cluster (%s)", clusterID)
+	}
+
+	log.Printf("[DEBUG] Waiting for the pre-upgrade check task (%s) of CCE cluster (%s) to complete", taskID, clusterID)
+	taskPath := requestPath + "/tasks/" + taskID
+	result, err := waitForClusterUpgradeTask(ctx, client, taskPath, d.Timeout(schema.TimeoutUpdate))
+	failures, warnings := buildPreCheckReport(result)
+	for _, warning := range warnings {
+		log.Printf("[WARN] the pre-upgrade check of CCE cluster (%s): %s", clusterID, warning)
+	}
+	if err != nil {
+		if len(failures) == 0 {
+			return fmt.Errorf("error waiting for the pre-upgrade check of CCE cluster (%s): %s", clusterID, err)
+		}
+		return fmt.Errorf("the pre-upgrade check of CCE cluster (%s) from %s to %s failed, please fix the "+
+			"following items or skip them by upgrade_strategy.skipped_check_items:\n  %s",
+			clusterID, currentVersion, targetVersion, strings.Join(failures, "\n  "))
+	}
+	return nil
+}
+
+func addonVersionSupportsCluster(version addons.Versions, clusterType, clusterVersion string) bool {
+	for _, support := range version.SupportVersions {
+		if support.ClusterType != "" && !strings.EqualFold(support.ClusterType, clusterType) {
+			continue
+		}
+		for _, pattern := range support.ClusterVersion {
+			if matched, err := regexp.MatchString("^"+pattern+"$", clusterVersion); err == nil && matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// buildClusterUpgradeAddons returns the add-ons to upgrade together with the cluster. The add-ons in the upgrade
+// strategy are upgraded to the specified versions, and the other installed add-ons which are not compatible with
+// the target version are upgraded to the latest compatible versions with the current values.
+func buildClusterUpgradeAddons(cfg *config.Config, d *schema.ResourceData,
+	targetVersion string) ([]map[string]interface{}, error) {
+	client, err := cfg.CceAddonV3Client(cfg.GetRegion(d))
+	if err != nil {
+		return nil, fmt.Errorf("error creating CCE add-on client: %s", err)
+	}
+	installed, err := addons.List(client, d.Id(), addons.ListOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("error querying the add-ons of CCE cluster (%s): %s", d.Id(), err)
+	}
+	templateList, err := templates.List(client, d.Id()).Extract()
+	if err != nil {
+		return nil, fmt.Errorf("error querying the add-on templates: %s", err)
+	}
+	templateVersions := make(map[string][]addons.Versions, len(templateList))
+	for _, t := range templateList {
+		templateVersions[t.Metadata.Name] = t.Spec.Versions
+	}
+	installedValues := make(map[string]addons.Values, len(installed))
+	for _, addon := range installed {
+		installedValues[addon.Spec.AddonTemplateName] = addon.Spec.Values
+	}
+
+	result := make([]map[string]interface{}, 0)
+	specified := make(map[string]bool)
+	for _, raw := range d.Get("upgrade_strategy.0.addons").([]interface{}) {
+		addon := raw.(map[string]interface{})
+		name := addon["name"].(string)
+		var values map[string]interface{}
+		if v := addon["values"].(string); v != "" {
+			if err := json.Unmarshal([]byte(v), &values); err != nil {
+				return nil, fmt.Errorf("error parsing the values of the add-on %s: %s", name, err)
+			}
+		} else if current, ok := installedValues[name]; ok {
+			if values, err = buildAddonUpgradeValues(current); err != nil {
+				return nil, fmt.Errorf("error building the values of the add-on %s: %s", name, err)
+			}
+		}
+
+		params := map[string]interface{}{
+			"addonTemplateName": name,
+			"operation":         "patch",
+			"version":           addon["version"].(string),
+		}
+		if values != nil {
+			params["values"] = values
+		}
+		result = append(result, params)
+		specified[name] = true
+	}
+
+	clusterType := d.Get("cluster_type").(string)
+	incompatible := make([]string, 0)
+	for _, addon := range installed {
+		name := addon.Spec.AddonTemplateName
+		if specified[name] {
+			continue
+		}
+
+		var latest *addons.Versions
+		compatible := false
+		versions := templateVersions[name]
+		for i, v := range versions {
+			if !addonVersionSupportsCluster(v, clusterType, targetVersion) {
+				continue
+			}
+			if v.Version == addon.Spec.Version {
+				compatible = true
+				break
+			}
+			if latest == nil || compareVersions(v.Version, latest.Version) > 0 {
+				latest = &versions[i]
+			}
+		}
+		if compatible {
+			continue
+		}
+		if latest == nil {
+			incompatible = append(incompatible, fmt.Sprintf("%s (%s)", name, addon.Spec.Version))
+			continue
+		}
+
+		values, err := buildAddonUpgradeValues(addon.Spec.Values)
+		if err != nil {
+			return nil, fmt.Errorf("error building the values of the add-on %s: %s", name, err)
+		}
+		log.Printf("[DEBUG] the add-on %s will be upgraded from %s to %s with CCE cluster (%s)", name,
+			addon.Spec.Version, latest.Version, d.Id())
+		result = append(result, map[string]interface{}{
+			"addonTemplateName": name,
+			"operation":         "patch",
+			"version":           latest.Version,
+			"values":            values,
+		})
+	}
+
+	if len(incompatible) > 0 {
+		sort.Strings(incompatible)
+		return nil, fmt.Errorf("the following add-ons of CCE cluster (%s) are not compatible with the target "+
+			"version %s, and no compatible versions are found, please specify them in upgrade_strategy.addons: %s",
+			d.Id(), targetVersion, strings.Join(incompatible, ", "))
+	}
+	return result, nil
+}
+
+// buildAddonUpgradeValues converts the current values of the installed add-on to the values of the upgrade request.
+func buildAddonUpgradeValues(current addons.Values) (map[string]interface{}, error) {
+	var values map[string]interface{}
+	valuesBytes, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(valuesBytes, &values)
+	return values, err
+}
+
+func buildClusterUpgradeStrategy(d *schema.ResourceData) (map[string]interface{}, map[string]interface{}) {
+	strategyType := defaultUpgradeStrategyType
+	step := 20
+	if v, ok := d.GetOk("upgrade_strategy.0.type"); ok {
+		strategyType = v.(string)
+	}
+	if v, ok := d.GetOk("upgrade_strategy.0.user_defined_step"); ok {
+		step = v.(int)
+	}
+
+	strategy := map[string]interface{}{
+		"type": strategyType,
+	}
+	if strategyType == defaultUpgradeStrategyType {
+		strategy["inPlaceRollingUpdate"] = map[string]interface{}{
+			"userDefinedStep": step,
+		}
+	}
+
+	nodePoolOrder := make(map[string]interface{})
+	for poolID, priority := range d.Get("upgrade_strategy.0.node_pool_order").(map[string]interface{}) {
+		nodePoolOrder[poolID] = priority
+	}
+	return strategy, nodePoolOrder
+}
+
+// resourceClusterUpgrade upgrades the cluster in place: the target version is checked by the pre-upgrade check,
+// the incompatible add-ons are upgraded together, and the nodes are upgraded in batches by the upgrade strategy.
+func resourceClusterUpgrade(ctx context.Context, cfg *config.Config, d *schema.ResourceData,
+	cceClient *golangsdk.ServiceClient) error {
+	clusterID := d.Id()
+	currentVersion, targetVersion, err := getClusterUpgradeVersions(cceClient, clusterID,
+		d.Get("cluster_version").(string))
+	if err != nil {
+		return err
+	}
+
+	if err := preCheckClusterUpgrade(ctx, cceClient, d, currentVersion, targetVersion); err != nil {
+		return err
+	}
+
+	upgradeAddons, err := buildClusterUpgradeAddons(cfg, d, targetVersion)
+	if err != nil {
+		return err
+	}
+
+	strategy, nodePoolOrder := buildClusterUpgradeStrategy(d)
+	upgradeAction := map[string]interface{}{
+		"targetVersion": targetVersion,
+		"strategy":      strategy,
+		"addons":        upgradeAddons,
+	}
+	if len(nodePoolOrder) > 0 {
+		upgradeAction["nodePoolOrder"] = nodePoolOrder
+	}
+
+	requestPath := cceClient.Endpoint + "api/v3/projects/{project_id}/clusters/{cluster_id}/operation/upgrade"
+	requestPath = strings.ReplaceAll(requestPath, "{project_id}", cceClient.ProjectID)
+	requestPath = strings.ReplaceAll(requestPath, "{cluster_id}", clusterID)
+	requestOpt := golangsdk.RequestOpts{
+		KeepResponseBody: true,
+		JSONBody: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"apiVersion": "v3",
+				"kind":       "UpgradeTask",
+			},
+			"spec": map[string]interface{}{
+				"clusterUpgradeAction": upgradeAction,
+			},
+		},
+	}
+	resp, err := cceClient.Request("POST", requestPath, &requestOpt)
+	if err != nil {
+		return fmt.Errorf("error upgrading CCE cluster (%s) from %s to %s: %s", clusterID, currentVersion,
+			targetVersion, err)
+	}
+	respBody, err := utils.FlattenResponse(resp)
+	if err != nil {
+		return err
+	}
+	taskID := utils.PathSearch("metadata.uid", respBody, "").(string)
+	if taskID == "" {
+		return fmt.Errorf("unable to find the upgrade task ID of CCE cluster (%s)", clusterID)
+	}
+
+	log.Printf("[DEBUG] Waiting for the upgrade task (%s) of CCE cluster (%s) to complete", taskID, clusterID)
+	taskPath := requestPath + "/tasks/" + taskID
+	result, err := waitForClusterUpgradeTask(ctx, cceClient, taskPath, d.Timeout(schema.TimeoutUpdate))
+	if err != nil {
+		phase := utils.PathSearch("status.phase", result, "").(string)
+		if phase == "Pause" {
+			return fmt.Errorf("the upgrade task (%s) of CCE cluster (%s) is paused, please continue or retry it "+
+				"in the CCE console", taskID, clusterID)
+		}
+		message := utils.PathSearch("status.message", result, "").(string)
+		return fmt.Errorf("error upgrading CCE cluster (%s) from %s to %s: %s %s", clusterID, currentVersion,
+			targetVersion, err, message)
+	}
+
+	log.Printf("[DEBUG] Waiting for CCE cluster (%s) to become available", clusterID)
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"PENDING"},
+		Target:       []string{"COMPLETED"},
+		Refresh:      clusterStateRefreshFunc(cceClient, clusterID, []string{"Available"}),
+		Timeout:      d.Timeout(schema.TimeoutUpdate),
+		Delay:        10 * time.Second,
+		PollInterval: 20 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for CCE cluster (%s) to become available after the upgrade: %s",
+			clusterID, err)
+	}
+	return nil
+}
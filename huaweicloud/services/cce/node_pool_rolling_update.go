@@ -0,0 +1,366 @@
+package cce
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/cce/v3/nodepools"
+	"github.com/chnsz/golangsdk/openstack/cce/v3/nodes"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+// nodePoolIdAnnotation is the node annotation which records the ID of the node pool that the node belongs to.
+const nodePoolIdAnnotation = "kubernetes.io/node-pool.id"
+
+// nodePoolRollingUpdateParams are the parameters of the node template, the node pool is recreated when they are
+// changed, unless the rolling_update is specified, then the nodes are replaced in batches.
+var nodePoolRollingUpdateParams = []string{
+	"flavor_id", "type", "availability_zone", "os", "key_pair", "password", "root_volume", "data_volumes",
+	"storage", "runtime", "extend_params", "subnet_id", "security_groups", "pod_security_groups", "ecs_group_id",
+	"max_pods", "preinstall", "postinstall", "extend_param",
+}
+
+func nodePoolRollingUpdateSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"max_surge": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      1,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"max_unavailable": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"drain_timeout": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+			},
+		},
+	}
+}
+
+// withoutForceNew clears the ForceNew of the schema and its nested schemas, the replacement is decided by
+// nodePoolRollingUpdateForceNew instead.
+func withoutForceNew(s *schema.Schema) *schema.Schema {
+	s.ForceNew = false
+	if elem, ok := s.Elem.(*schema.Resource); ok {
+		for _, sub := range elem.Schema {
+			withoutForceNew(sub)
+		}
+	}
+	return s
+}
+
+// nodePoolRollingUpdateForceNew requires the replacement of the node pool if the node template is changed and the
+// rolling_update is not specified.
+func nodePoolRollingUpdateForceNew(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.HasChanges(nodePoolRollingUpdateParams...) {
+		return nil
+	}
+
+	if len(d.Get("rolling_update").([]interface{})) > 0 {
+		if d.Get("rolling_update.0.max_surge").(int)+d.Get("rolling_update.0.max_unavailable").(int) < 1 {
+			return fmt.Errorf("at least one of max_surge and max_unavailable in rolling_update must be greater than 0")
+		}
+		if d.Get("charging_mode").(string) == "prePaid" {
+			return fmt.Errorf("the rolling update is not supported by the node pool in prePaid charging mode")
+		}
+		return nil
+	}
+
+	resourceSchema := ResourceNodePool().Schema
+	for _, key := range nodePoolRollingUpdateParams {
+		forceNewOnChange(d, key, resourceSchema[key])
+	}
+	return nil
+}
+
+// forceNewOnChange requires the replacement for the changed attributes of the key, the nested attributes of the
+// blocks are walked through, because the replacement of a block is only triggered by the change of its length.
+func forceNewOnChange(d *schema.ResourceDiff, key string, s *schema.Schema) {
+	if !d.HasChange(key) {
+		return
+	}
+
+	elem, ok := s.Elem.(*schema.Resource)
+	if s.Type != schema.TypeList || !ok {
+		if err := d.ForceNew(key); err != nil {
+			log.Printf("[WARN] unable to require attribute replacement of %s: %s", key, err)
+		}
+		return
+	}
+
+	oldValue, newValue := d.GetChange(key)
+	oldLen, newLen := len(oldValue.([]interface{})), len(newValue.([]interface{}))
+	if oldLen != newLen {
+		if err := d.ForceNew(key); err != nil {
+			log.Printf("[WARN] unable to require attribute replacement of %s: %s", key, err)
+		}
+	}
+	for i := 0; i < oldLen && i < newLen; i++ {
+		for subKey, sub := range elem.Schema {
+			forceNewOnChange(d, fmt.Sprintf("%s.%d.%s", key, i, subKey), sub)
+		}
+	}
+}
+
+func listNodePoolNodes(client *golangsdk.ServiceClient, clusterId, nodePoolId string) ([]nodes.Nodes, error) {
+	allNodes, err := nodes.List(client, clusterId, nodes.ListOpts{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]nodes.Nodes, 0)
+	for _, node := range allNodes {
+		if node.Metadata.Annotations[nodePoolIdAnnotation] == nodePoolId && node.Status.Phase != "Deleting" {
+			result = append(result, node)
+		}
+	}
+	return result, nil
+}
+
+// scaleRollingNodePool scales the shadow node pool to the specified count, and waits for the new nodes.
+func scaleRollingNodePool(ctx context.Context, client *golangsdk.ServiceClient, d *schema.ResourceData,
+	clusterId, nodePoolId, name string, count int) error {
+	updateOpts, err := buildNodePoolUpdateOpts(d)
+	if err != nil {
+		return err
+	}
+	// the autoscaling is enabled after the rolling update, so that the nodes are not scaled during the update
+	updateOpts.Metadata.Name = name
+	updateOpts.Spec.InitialNodeCount = utils.Int(count)
+	updateOpts.Spec.Autoscaling = nodepools.AutoscalingSpec{}
+	_, err = nodepools.Update(client, clusterId, nodePoolId, updateOpts).Extract()
+	if err != nil {
+		return fmt.Errorf("error scaling CCE node pool (%s) to %d nodes: %s", nodePoolId, count, err)
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"PENDING"},
+		Target:       []string{"COMPLETED"},
+		Refresh:      nodePoolStateRefreshFunc(client, clusterId, nodePoolId, []string{""}),
+		Timeout:      d.Timeout(schema.TimeoutUpdate),
+		Delay:        60 * time.Second,
+		PollInterval: 20 * time.Second,
+	}
+	if _, err = stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for CCE node pool (%s) to be scaled: %s", nodePoolId, err)
+	}
+	return nil
+}
+
+// drainNode cordons the node and evicts the pods, the daemon sets are ignored.
+func drainNode(ctx context.Context, client *golangsdk.ServiceClient, d *schema.ResourceData,
+	clusterId, nodeId string) error {
+	drainSpec := map[string]interface{}{
+		"drainNow":         true,
+		"ignoreDaemonSets": true,
+		"deleteLocalData":  true,
+	}
+	if v, ok := d.GetOk("rolling_update.0.drain_timeout"); ok {
+		drainSpec["timeoutSeconds"] = v.(int)
+	}
+
+	requestPath := client.Endpoint + "api/v3/projects/{project_id}/clusters/{cluster_id}/nodes/{node_id}/drain"
+	requestPath = strings.ReplaceAll(requestPath, "{project_id}", client.ProjectID)
+	requestPath = strings.ReplaceAll(requestPath, "{cluster_id}", clusterId)
+	requestPath = strings.ReplaceAll(requestPath, "{node_id}", nodeId)
+	requestOpt := golangsdk.RequestOpts{
+		KeepResponseBody: true,
+		JSONBody: map[string]interface{}{
+			"apiVersion": "v3",
+			"kind":       "Drain",
+			"spec":       drainSpec,
+		},
+	}
+	resp, err := client.Request("POST", requestPath, &requestOpt)
+	if err != nil {
+		return fmt.Errorf("error draining CCE node (%s): %s", nodeId, err)
+	}
+	respBody, err := utils.FlattenResponse(resp)
+	if err != nil {
+		return err
+	}
+	jobId := utils.PathSearch("status.jobID", respBody, "").(string)
+	if jobId == "" {
+		return nil
+	}
+
+	stateJob := &resource.StateChangeConf{
+		Pending:      []string{"Initializing", "Running"},
+		Target:       []string{"Success"},
+		Refresh:      waitForJobStatus(client, jobId),
+		Timeout:      d.Timeout(schema.TimeoutUpdate),
+		Delay:        10 * time.Second,
+		PollInterval: 10 * time.Second,
+	}
+	if v, err := stateJob.WaitForStateContext(ctx); err != nil {
+		if job, ok := v.(*nodes.Job); ok {
+			return fmt.Errorf("error waiting for CCE node (%s) to be drained: %s, reason: %s", nodeId, err,
+				job.Status.Reason)
+		}
+		return fmt.Errorf("error waiting for CCE node (%s) to be drained: %s", nodeId, err)
+	}
+	return nil
+}
+
+func deleteDrainedNode(ctx context.Context, client *golangsdk.ServiceClient, d *schema.ResourceData,
+	clusterId, nodeId string) error {
+	if err := nodes.Delete(client, clusterId, nodeId).ExtractErr(); err != nil {
+		return fmt.Errorf("error deleting CCE node (%s): %s", nodeId, err)
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"PENDING"},
+		Target:       []string{"COMPLETED"},
+		Refresh:      nodeStateRefreshFunc(client, clusterId, nodeId, nil),
+		Timeout:      d.Timeout(schema.TimeoutUpdate),
+		Delay:        30 * time.Second,
+		PollInterval: 20 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for CCE node (%s) to be deleted: %s", nodeId, err)
+	}
+	return nil
+}
+
+// nextRollingUpdateBatch calculates the next batch of the rolling update, desired is the original node count,
+// newCount and oldCount are the node counts of the shadow node pool and the old node pool. In the batch, the shadow node
+// pool is scaled up by surge nodes, then the removable old nodes are drained and deleted, and the shadow node pool is
+// scaled up by backfill nodes at last. The backfill is only used when max_surge is 0, which replaces the removed nodes
+// after they're deleted, so that the node count never exceeds the original one.
+func nextRollingUpdateBatch(desired, newCount, oldCount, maxSurge, maxUnavailable int) (surge, removable, backfill int) {
+	surge = min(maxSurge, desired-newCount)
+	// the available nodes can not be less than desired - max_unavailable
+	removable = min(newCount+surge+oldCount-desired+maxUnavailable, oldCount)
+	if surge == 0 && removable > 0 {
+		backfill = min(removable, desired-newCount)
+	}
+	return
+}
+
+// replaceNodePoolNodes replaces the old nodes in batches, the nodes of each batch are calculated by
+// nextRollingUpdateBatch, so that the available nodes are never less than the original count minus max_unavailable.
+func replaceNodePoolNodes(ctx context.Context, client *golangsdk.ServiceClient, d *schema.ResourceData, clusterId,
+	newNodePoolId, shadowName string, oldNodes []nodes.Nodes) error {
+	maxSurge := d.Get("rolling_update.0.max_surge").(int)
+	maxUnavailable := d.Get("rolling_update.0.max_unavailable").(int)
+	desired := len(oldNodes)
+
+	newCount := 0
+	for len(oldNodes) > 0 {
+		surge, removable, backfill := nextRollingUpdateBatch(desired, newCount, len(oldNodes), maxSurge, maxUnavailable)
+		if removable < 1 {
+			return fmt.Errorf("unable to make progress in the rolling update of CCE node pool (%s), please check "+
+				"max_surge and max_unavailable", d.Id())
+		}
+
+		if surge > 0 {
+			newCount += surge
+			if err := scaleRollingNodePool(ctx, client, d, clusterId, newNodePoolId, shadowName, newCount); err != nil {
+				return err
+			}
+		}
+
+		for _, node := range oldNodes[:removable] {
+			log.Printf("[DEBUG] Replacing the node (%s) of CCE node pool (%s)", node.Metadata.Id, d.Id())
+			if err := drainNode(ctx, client, d, clusterId, node.Metadata.Id); err != nil {
+				return err
+			}
+			if err := deleteDrainedNode(ctx, client, d, clusterId, node.Metadata.Id); err != nil {
+				return err
+			}
+		}
+		oldNodes = oldNodes[removable:]
+
+		if backfill > 0 {
+			newCount += backfill
+			if err := scaleRollingNodePool(ctx, client, d, clusterId, newNodePoolId, shadowName, newCount); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resourceNodePoolRollingUpdate replaces the nodes of the node pool with a shadow node pool which uses the new node
+// template, the old node pool is deleted at last, and the ID of the resource is changed to the shadow node pool.
+func resourceNodePoolRollingUpdate(ctx context.Context, client *golangsdk.ServiceClient, d *schema.ResourceData) error {
+	clusterId := d.Get("cluster_id").(string)
+	oldNodePoolId := d.Id()
+	oldNodes, err := listNodePoolNodes(client, clusterId, oldNodePoolId)
+	if err != nil {
+		return fmt.Errorf("error querying the nodes of CCE node pool (%s): %s", oldNodePoolId, err)
+	}
+	createOpts, err := buildNodePoolCreateOpts(d)
+	if err != nil {
+		return err
+	}
+	shadowName := fmt.Sprintf("%s-%d", d.Get("name").(string), time.Now().Unix())
+	createOpts.Metadata.Name = shadowName
+	createOpts.Spec.InitialNodeCount = utils.Int(0)
+	createOpts.Spec.Autoscaling = nodepools.AutoscalingSpec{}
+	resp, err := nodepools.Create(client, clusterId, createOpts).Extract()
+	if err != nil {
+		return fmt.Errorf("error creating the shadow node pool of CCE node pool (%s): %s", oldNodePoolId, err)
+	}
+	newNodePoolId := resp.Metadata.Id
+	log.Printf("[DEBUG] The shadow node pool (%s) of CCE node pool (%s) is created, %d nodes will be replaced",
+		newNodePoolId, oldNodePoolId, len(oldNodes))
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"PENDING"},
+		Target:       []string{"COMPLETED"},
+		Refresh:      nodePoolStateRefreshFunc(client, clusterId, newNodePoolId, []string{""}),
+		Timeout:      d.Timeout(schema.TimeoutUpdate),
+		Delay:        30 * time.Second,
+		PollInterval: 20 * time.Second,
+	}
+	if _, err = stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for the shadow node pool (%s) to become available: %s", newNodePoolId, err)
+	}
+
+	err = replaceNodePoolNodes(ctx, client, d, clusterId, newNodePoolId, shadowName, oldNodes)
+	if err != nil {
+		return fmt.Errorf("%s, the shadow node pool (%s) is kept and can be deleted manually", err, newNodePoolId)
+	}
+
+	if err = nodepools.Delete(client, clusterId, oldNodePoolId).ExtractErr(); err != nil {
+		return fmt.Errorf("error deleting the old CCE node pool (%s): %s", oldNodePoolId, err)
+	}
+	stateConf = &resource.StateChangeConf{
+		Pending:      []string{"PENDING"},
+		Target:       []string{"COMPLETED"},
+		Refresh:      nodePoolStateRefreshFunc(client, clusterId, oldNodePoolId, nil),
+		Timeout:      d.Timeout(schema.TimeoutUpdate),
+		Delay:        30 * time.Second,
+		PollInterval: 20 * time.Second,
+	}
+	if _, err = stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for the old CCE node pool (%s) to be deleted: %s", oldNodePoolId, err)
+	}
+
+	// the name, node count and autoscaling of the new node pool are updated by the following update
+	d.SetId(newNodePoolId)
+	return nil
+}
@@ -0,0 +1,51 @@
+package cce
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextRollingUpdateBatch(t *testing.T) {
+	testCases := []struct {
+		name           string
+		desired        int
+		maxSurge       int
+		maxUnavailable int
+		// each batch is [surge, removable, backfill]
+		expected [][3]int
+	}{
+		{"surge only", 3, 1, 0, [][3]int{{1, 1, 0}, {1, 1, 0}, {1, 1, 0}}},
+		{"unavailable only", 3, 0, 1, [][3]int{{0, 1, 1}, {0, 1, 1}, {0, 1, 1}}},
+		{"unavailable only in uneven batches", 5, 0, 2, [][3]int{{0, 2, 2}, {0, 2, 2}, {0, 1, 1}}},
+		{"surge and unavailable", 4, 2, 1, [][3]int{{2, 3, 0}, {2, 1, 0}}},
+		{"surge larger than the node count", 2, 5, 0, [][3]int{{2, 2, 0}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			newCount, oldCount := 0, tc.desired
+			batches := make([][3]int, 0)
+			for oldCount > 0 {
+				surge, removable, backfill := nextRollingUpdateBatch(tc.desired, newCount, oldCount, tc.maxSurge,
+					tc.maxUnavailable)
+				if !assert.Greater(t, removable, 0, "the rolling update can not make progress") {
+					return
+				}
+				batches = append(batches, [3]int{surge, removable, backfill})
+
+				newCount += surge
+				assert.LessOrEqual(t, newCount+oldCount, tc.desired+tc.maxSurge, "too many nodes are created")
+				oldCount -= removable
+				assert.GreaterOrEqual(t, newCount+oldCount, tc.desired-tc.maxUnavailable, "too many nodes are removed")
+				newCount += backfill
+			}
+			assert.Equal(t, tc.expected, batches)
+		})
+	}
+}
+
+func TestNextRollingUpdateBatch_noProgress(t *testing.T) {
+	_, removable, _ := nextRollingUpdateBatch(3, 0, 3, 0, 0)
+	assert.Less(t, removable, 1)
+}
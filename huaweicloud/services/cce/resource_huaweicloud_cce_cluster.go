@@ -37,6 +37,13 @@ import (
 // @API CCE PUT /api/v3/projects/{project_id}/clusters/{id}/mastereip
 // @API CCE POST /api/v3/projects/{project_id}/clusters/{id}/tags/{action}
 // @API CCE POST /api/v3/projects/{project_id}/clusters/{id}/operation/resize
+// @API CCE GET /api/v3/projects/{project_id}/clusters/{id}/upgradeinfo
+// @API CCE POST /api/v3/projects/{project_id}/clusters/{id}/operation/precheck
+// @API CCE GET /api/v3/projects/{project_id}/clusters/{id}/operation/precheck/tasks/{task_id}
+// @API CCE GET /api/v3/addons
+// @API CCE GET /api/v3/addontemplates
+// @API CCE POST /api/v3/projects/{project_id}/clusters/{id}/operation/upgrade
+// @API CCE GET /api/v3/projects/{project_id}/clusters/{id}/operation/upgrade/tasks/{task_id}
 // @API BSS GET /V2/orders/customer-orders/details/{order_id}
 // @API BSS POST /v2/orders/suscriptions/resources/query
 // @API BSS POST /v2/orders/subscriptions/resources/autorenew/{id}
@@ -88,6 +95,8 @@ func ResourceCluster() *schema.Resource {
 			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
 
+		CustomizeDiff: validateClusterVersionChange,
+
 		//request and response parameters
 		Schema: map[string]*schema.Schema{
 			"region": {
@@ -109,9 +118,9 @@ func ResourceCluster() *schema.Resource {
 				Type:             schema.TypeString,
 				Optional:         true,
 				Computed:         true,
-				ForceNew:         true,
 				DiffSuppressFunc: utils.SuppressVersionDiffs,
 			},
+			"upgrade_strategy": clusterUpgradeStrategySchema(),
 			"cluster_type": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -991,6 +1000,13 @@ func resourceClusterUpdate(ctx context.Context, d *schema.ResourceData, meta int
 		}
 	}
 
+	if d.HasChange("cluster_version") {
+		err := resourceClusterUpgrade(ctx, cfg, d, cceClient)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	if d.HasChange("hibernate") {
 		if d.Get("hibernate").(bool) {
 			err = resourceClusterHibernate(ctx, d, cceClient)
@@ -1027,19 +1043,19 @@ func resourceClusterUpdate(ctx context.Context, d *schema.ResourceData, meta int
 	}
 
 	if d.HasChange("tags") {
-		// remove old tags and set new tags
+		// remove the tags which are no longer present, then create the new tags and update the tags whose value
+		// is changed
 		oldTags, newTags := d.GetChange("tags")
-		oldTagsRaw := oldTags.(map[string]interface{})
-		if len(oldTagsRaw) > 0 {
-			taglist := utils.ExpandResourceTags(oldTagsRaw)
+		removedTags, upsertedTags := utils.DiffTags(oldTags.(map[string]interface{}), newTags.(map[string]interface{}))
+		if len(removedTags) > 0 {
+			taglist := utils.ExpandResourceTags(removedTags)
 			if tagErr := clusters.RemoveTags(cceClient, clusterId, taglist).ExtractErr(); tagErr != nil {
 				return diag.Errorf("error deleting tags of CCE cluster %s: %s", clusterId, tagErr)
 			}
 		}
 
-		newTagsRaw := newTags.(map[string]interface{})
-		if len(newTagsRaw) > 0 {
-			taglist := utils.ExpandResourceTags(newTagsRaw)
+		if len(upsertedTags) > 0 {
+			taglist := utils.ExpandResourceTags(upsertedTags)
 			if tagErr := clusters.AddTags(cceClient, clusterId, taglist).ExtractErr(); tagErr != nil {
 				return diag.Errorf("error setting tags of CCE cluster %s: %s", clusterId, tagErr)
 			}
@@ -376,7 +376,7 @@ func buildResourceNodeK8sTags(d *schema.ResourceData) map[string]string {
 }
 
 func buildResourceNodeTags(d *schema.ResourceData) []tags.ResourceTag {
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	return utils.ExpandResourceTags(tagRaw)
 }
 
@@ -701,7 +701,7 @@ func resourceNodeUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 	serverId := d.Get("server_id").(string)
 
 	// update node tags with ECS API
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		tagErr := utils.UpdateResourceTags(computeClient, d, "cloudservers", serverId)
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of cce node %s: %s", d.Id(), tagErr)
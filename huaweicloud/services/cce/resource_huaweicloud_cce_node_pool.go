@@ -27,6 +27,11 @@ import (
 // @API CCE GET /api/v3/projects/{project_id}/clusters/{cluster_id}/nodepools/{nodepool_id}
 // @API CCE PUT /api/v3/projects/{project_id}/clusters/{cluster_id}/nodepools/{nodepool_id}
 // @API CCE DELETE /api/v3/projects/{project_id}/clusters/{cluster_id}/nodepools/{nodepool_id}
+// @API CCE GET /api/v3/projects/{project_id}/clusters/{cluster_id}/nodes
+// @API CCE POST /api/v3/projects/{project_id}/clusters/{cluster_id}/nodes/{node_id}/drain
+// @API CCE GET /api/v3/projects/{project_id}/clusters/{cluster_id}/nodes/{node_id}
+// @API CCE DELETE /api/v3/projects/{project_id}/clusters/{cluster_id}/nodes/{node_id}
+// @API CCE GET /api/v3/projects/{project_id}/jobs/{job_id}
 
 func ResourceNodePool() *schema.Resource {
 	return &schema.Resource{
@@ -41,9 +46,12 @@ func ResourceNodePool() *schema.Resource {
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
 			Delete: schema.DefaultTimeout(20 * time.Minute),
 		},
 
+		CustomizeDiff: nodePoolRollingUpdateForceNew,
+
 		Schema: map[string]*schema.Schema{
 			"region": {
 				Type:     schema.TypeString,
@@ -67,12 +75,10 @@ func ResourceNodePool() *schema.Resource {
 			"flavor_id": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 			"type": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 				Computed: true,
 			},
 			"labels": { // (k8s_tags)
@@ -80,33 +86,29 @@ func ResourceNodePool() *schema.Resource {
 				Optional: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
-			"root_volume":  resourceNodeRootVolume(),
-			"data_volumes": resourceNodeDataVolume(),
+			"root_volume":  withoutForceNew(resourceNodeRootVolume()),
+			"data_volumes": withoutForceNew(resourceNodeDataVolume()),
 			"availability_zone": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 				Default:  "random",
 			},
 			"os": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 				Computed: true,
 			},
 			"key_pair": {
 				Type:         schema.TypeString,
 				Optional:     true,
-				ForceNew:     true,
 				ExactlyOneOf: []string{"password", "key_pair"},
 			},
 			"password": {
 				Type:      schema.TypeString,
 				Optional:  true,
-				ForceNew:  true,
 				Sensitive: true,
 			},
-			"storage": resourceNodeStorageSchema(),
+			"storage": withoutForceNew(resourceNodeStorageSchema()),
 			"taints": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -136,19 +138,17 @@ func ResourceNodePool() *schema.Resource {
 			"runtime": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 				Computed: true,
 				ValidateFunc: validation.StringInSlice([]string{
 					"docker", "containerd",
 				}, false),
 			},
-			"extend_params": resourceNodeExtendParamsSchema([]string{
+			"extend_params": withoutForceNew(resourceNodeExtendParamsSchema([]string{
 				"max_pods", "preinstall", "postinstall", "extend_param",
-			}),
+			})),
 			"subnet_id": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 			},
 			"scall_enable": {
 				Type:     schema.TypeBool,
@@ -173,14 +173,12 @@ func ResourceNodePool() *schema.Resource {
 			"security_groups": {
 				Type:     schema.TypeList,
 				Optional: true,
-				ForceNew: true,
 				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 			"pod_security_groups": {
 				Type:     schema.TypeList,
 				Optional: true,
-				ForceNew: true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -188,7 +186,6 @@ func ResourceNodePool() *schema.Resource {
 			"ecs_group_id": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 			},
 			"initialized_conditions": {
 				Type:     schema.TypeList,
@@ -211,6 +208,7 @@ func ResourceNodePool() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
+			"rolling_update": nodePoolRollingUpdateSchema(),
 			"current_node_count": {
 				Type:     schema.TypeInt,
 				Computed: true,
@@ -228,28 +226,24 @@ func ResourceNodePool() *schema.Resource {
 			"max_pods": {
 				Type:        schema.TypeInt,
 				Optional:    true,
-				ForceNew:    true,
 				Computed:    true,
 				Description: "schema: Deprecated; This parameter can be configured in the 'extend_params' parameter.",
 			},
 			"preinstall": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				ForceNew:    true,
 				StateFunc:   utils.DecodeHashAndHexEncode,
 				Description: "schema: Deprecated; This parameter can be configured in the 'extend_params' parameter.",
 			},
 			"postinstall": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				ForceNew:    true,
 				StateFunc:   utils.DecodeHashAndHexEncode,
 				Description: "schema: Deprecated; This parameter can be configured in the 'extend_params' parameter.",
 			},
 			"extend_param": {
 				Type:        schema.TypeMap,
 				Optional:    true,
-				ForceNew:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Description: "schema: Deprecated; This parameter has been replaced by the 'extend_params' parameter.",
 			},
@@ -497,6 +491,12 @@ func resourceNodePoolUpdate(ctx context.Context, d *schema.ResourceData, meta in
 		return diag.Errorf("error creating CCE v3 client: %s", err)
 	}
 
+	if d.HasChanges(nodePoolRollingUpdateParams...) {
+		if err := resourceNodePoolRollingUpdate(ctx, cceClient, d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	updateOpts, err := buildNodePoolUpdateOpts(d)
 	if err != nil {
 		return diag.FromErr(err)
@@ -723,18 +723,20 @@ func resourcePrivateCAUpdate(ctx context.Context, d *schema.ResourceData, meta i
 		oMap := oRaw.(map[string]interface{})
 		nMap := nRaw.(map[string]interface{})
 
-		// remove old tags
-		if len(oMap) > 0 {
+		removedTags, upsertedTags := utils.DiffTags(oMap, nMap)
+
+		// remove the tags which are no longer present
+		if len(removedTags) > 0 {
 			deleteTagsHttpUrl := "v1/private-certificate-authorities/{ca_id}/tags/delete"
-			if err = deleteTags(d.Id(), client, oMap, deleteTagsHttpUrl, "{ca_id}"); err != nil {
+			if err = deleteTags(d.Id(), client, removedTags, deleteTagsHttpUrl, "{ca_id}"); err != nil {
 				return diag.FromErr(err)
 			}
 		}
 
-		// set new tags
-		if len(nMap) > 0 {
+		// create the new tags and update the tags whose value is changed
+		if len(upsertedTags) > 0 {
 			createTagsHttpUrl := "v1/private-certificate-authorities/{ca_id}/tags/create"
-			if err := createTags(d.Id(), client, nMap, createTagsHttpUrl, "{ca_id}"); err != nil {
+			if err := createTags(d.Id(), client, upsertedTags, createTagsHttpUrl, "{ca_id}"); err != nil {
 				return diag.FromErr(err)
 			}
 		}
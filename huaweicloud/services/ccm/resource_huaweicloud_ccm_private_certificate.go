@@ -368,18 +368,20 @@ func resourceCcmPrivateCertificateUpdate(_ context.Context, d *schema.ResourceDa
 		oMap := oRaw.(map[string]interface{})
 		nMap := nRaw.(map[string]interface{})
 
-		// remove old tags
-		if len(oMap) > 0 {
+		removedTags, upsertedTags := utils.DiffTags(oMap, nMap)
+
+		// remove the tags which are no longer present
+		if len(removedTags) > 0 {
 			deleteTagsHttpUrl := "v1/private-certificates/{certificate_id}/tags/delete"
-			if err = deleteTags(d.Id(), client, oMap, deleteTagsHttpUrl, "{certificate_id}"); err != nil {
+			if err = deleteTags(d.Id(), client, removedTags, deleteTagsHttpUrl, "{certificate_id}"); err != nil {
 				return diag.FromErr(err)
 			}
 		}
 
-		// set new tags
-		if len(nMap) > 0 {
+		// create the new tags and update the tags whose value is changed
+		if len(upsertedTags) > 0 {
 			createTagsHttpUrl := "v1/private-certificates/{certificate_id}/tags/create"
-			if err := createTags(d.Id(), client, nMap, createTagsHttpUrl, "{certificate_id}"); err != nil {
+			if err := createTags(d.Id(), client, upsertedTags, createTagsHttpUrl, "{certificate_id}"); err != nil {
 				return diag.FromErr(err)
 			}
 		}
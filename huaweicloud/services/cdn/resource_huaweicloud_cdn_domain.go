@@ -2477,10 +2477,12 @@ func updateDomainTags(hcCdnClient *cdnv2.CdnClient, d *schema.ResourceData) erro
 	oTagsRaw, nTagsRaw := d.GetChange("tags")
 	oTagsMap := oTagsRaw.(map[string]interface{})
 	nTagsMap := nTagsRaw.(map[string]interface{})
+	removedTags, upsertedTags := utils.DiffTags(oTagsMap, nTagsMap)
 
-	if len(oTagsMap) > 0 {
+	// remove the tags which are no longer present
+	if len(removedTags) > 0 {
 		var tagList []string
-		for k := range oTagsMap {
+		for k := range removedTags {
 			tagList = append(tagList, k)
 		}
 		deleteTagsReq := model.BatchDeleteTagsRequest{
@@ -2495,9 +2497,10 @@ func updateDomainTags(hcCdnClient *cdnv2.CdnClient, d *schema.ResourceData) erro
 		}
 	}
 
-	if len(nTagsMap) > 0 {
-		tagList := make([]model.TagMap, 0, len(nTagsMap))
-		for k, v := range nTagsMap {
+	// create the new tags and update the tags whose value is changed
+	if len(upsertedTags) > 0 {
+		tagList := make([]model.TagMap, 0, len(upsertedTags))
+		for k, v := range upsertedTags {
 			tag := model.TagMap{
 				Key:   k,
 				Value: utils.String(v.(string)),
@@ -1055,16 +1055,18 @@ func updateTags(d *schema.ResourceData, meta interface{}) error {
 	oMap := oRaw.(map[string]interface{})
 	nMap := nRaw.(map[string]interface{})
 
-	// Remove old tags.
-	if len(oMap) > 0 {
-		if err := deleteTags(client, oMap, d.Id()); err != nil {
+	removedTags, upsertedTags := utils.DiffTags(oMap, nMap)
+
+	// remove the tags which are no longer present
+	if len(removedTags) > 0 {
+		if err := deleteTags(client, removedTags, d.Id()); err != nil {
 			return err
 		}
 	}
 
-	// Set new tags.
-	if len(nMap) > 0 {
-		if err := createTags(client, nMap, d.Id()); err != nil {
+	// create the new tags and update the tags whose value is changed
+	if len(upsertedTags) > 0 {
+		if err := createTags(client, upsertedTags, d.Id()); err != nil {
 			return err
 		}
 	}
@@ -1758,8 +1758,10 @@ func checkCssClusterIsReady(detail *model.ShowClusterDetailResponse) bool {
 }
 
 func updateCssTags(cssV1Client *cssv1.CssClient, id string, old, new map[string]interface{}) error {
-	// remove old tags
-	for k := range old {
+	removedTags, upsertedTags := utils.DiffTags(old, new)
+
+	// remove the tags which are no longer present
+	for k := range removedTags {
 		_, err := cssV1Client.DeleteClustersTags(&model.DeleteClustersTagsRequest{
 			ResourceType: "css-cluster",
 			ClusterId:    id,
@@ -1770,8 +1772,8 @@ func updateCssTags(cssV1Client *cssv1.CssClient, id string, old, new map[string]
 		}
 	}
 
-	// set new tags
-	for k, v := range new {
+	// create the new tags and update the tags whose value is changed
+	for k, v := range upsertedTags {
 		_, err := cssV1Client.CreateClustersTags(&model.CreateClustersTagsRequest{
 			ResourceType: "css-cluster",
 			ClusterId:    id,
@@ -307,7 +307,7 @@ func buildlogstashClusterCreateParameters(d *schema.ResourceData, conf *config.C
 			Version: d.Get("engine_version").(string),
 		},
 		EnterpriseProjectId: utils.StringIgnoreEmpty(conf.GetEnterpriseProjectID(d)),
-		Tags:                buildLogstashCssTags(d.Get("tags").(map[string]interface{})),
+		Tags:                buildLogstashCssTags(utils.GetAllResourceTags(d)),
 		Instance: &model.CreateClusterInstanceBody{
 			FlavorRef: d.Get("node_config.0.flavor").(string),
 			Nics: &model.CreateClusterInstanceNicsBody{
@@ -470,7 +470,7 @@ func resourceLogstashClusterUpdate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		client, err := conf.CssV1Client(region)
 		if err != nil {
 			return diag.Errorf("error creating CSS V1 client: %s", err)
@@ -242,17 +242,20 @@ func buildTransferBucketOpts(d *schema.ResourceData) *cts.TrackerObsInfo {
 func updateResourceTags(ctsClient *client.CtsClient, d *schema.ResourceData) error {
 	oldRaw, newRaw := d.GetChange("tags")
 	id := d.Id()
+	removedTags, upsertedTags := utils.DiffTags(oldRaw.(map[string]interface{}), newRaw.(map[string]interface{}))
 
-	if oldTags := oldRaw.(map[string]interface{}); len(oldTags) > 0 {
-		oldTagList := expandResourceTags(oldTags)
+	// remove the tags which are no longer present
+	if len(removedTags) > 0 {
+		oldTagList := expandResourceTags(removedTags)
 		_, err := ctsClient.BatchDeleteResourceTags(buildDeleteTagOpt(oldTagList, id))
 		if err != nil {
 			return err
 		}
 	}
 
-	if newTags := newRaw.(map[string]interface{}); len(newTags) > 0 {
-		newTagsList := expandResourceTags(newTags)
+	// create the new tags and update the tags whose value is changed
+	if len(upsertedTags) > 0 {
+		newTagsList := expandResourceTags(upsertedTags)
 		_, err := ctsClient.BatchCreateResourceTags(buildCreateTagOpt(newTagsList, id))
 		if err != nil {
 			return err
@@ -400,9 +400,10 @@ func resourceCTSTrackerDelete(_ context.Context, d *schema.ResourceData, meta in
 		return diag.Errorf("falied to reset CTS system tracker: %s", err)
 	}
 
+	// the system tracker is only reset, so all of its tags are removed
 	oldRaw, _ := d.GetChange("tags")
-	if oldTags := oldRaw.(map[string]interface{}); len(oldTags) > 0 {
-		oldTagList := expandResourceTags(oldTags)
+	if removedTags, _ := utils.DiffTags(oldRaw.(map[string]interface{}), nil); len(removedTags) > 0 {
+		oldTagList := expandResourceTags(removedTags)
 		_, err = ctsClient.BatchDeleteResourceTags(buildDeleteTagOpt(oldTagList, d.Id()))
 		if err != nil {
 			return diag.Errorf("falied to delete CTS system tracker tags: %s", err)
@@ -1337,18 +1337,20 @@ func refreshDcsInstancePort(c *golangsdk.ServiceClient, id string) resource.Stat
 }
 
 func updateDcsTags(c *golangsdk.ServiceClient, id string, oldVal, newVal map[string]interface{}) error {
-	// remove old tags
-	if len(oldVal) > 0 {
-		tagList := buildDcsTagsParams(oldVal)
+	removedTags, upsertedTags := utils.DiffTags(oldVal, newVal)
+
+	// remove the tags which are no longer present
+	if len(removedTags) > 0 {
+		tagList := buildDcsTagsParams(removedTags)
 		err := dcsTags.Delete(c, id, tagList)
 		if err != nil {
 			return err
 		}
 	}
 
-	// add new tags
-	if len(newVal) > 0 {
-		tagList := buildDcsTagsParams(newVal)
+	// create the new tags and update the tags whose value is changed
+	if len(upsertedTags) > 0 {
+		tagList := buildDcsTagsParams(upsertedTags)
 		err := dcsTags.Create(c, id, tagList)
 		if err != nil {
 			return err
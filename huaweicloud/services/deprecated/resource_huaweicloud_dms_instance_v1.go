@@ -229,7 +229,7 @@ func resourceDmsInstancesV1Create(d *schema.ResourceData, meta interface{}) erro
 	d.SetId(v.InstanceID)
 
 	//set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	if len(tagRaw) > 0 {
 		dmsV2Client, err := config.DmsV2Client(config.GetRegion(d))
 		if err != nil {
@@ -341,7 +341,7 @@ func resourceDmsInstancesV1Update(d *schema.ResourceData, meta interface{}) erro
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		dmsV2Client, err := config.DmsV2Client(config.GetRegion(d))
 		if err != nil {
 			return fmtp.Errorf("Error updating HuaweiCloud dms instance v2 client: %s", err)
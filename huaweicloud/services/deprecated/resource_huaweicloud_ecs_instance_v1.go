@@ -255,8 +255,8 @@ func resourceEcsInstanceV1Create(d *schema.ResourceData, meta interface{}) error
 		createOpts.MetaData = &metadata
 	}
 
-	if tags, ok := d.GetOk("tags"); ok {
-		createOpts.ServerTags = utils.ExpandResourceTags(tags.(map[string]interface{}))
+	if tags := utils.GetAllResourceTags(d); len(tags) > 0 {
+		createOpts.ServerTags = utils.ExpandResourceTags(tags)
 	}
 
 	logp.Printf("[DEBUG] Create Options: %#v", createOpts)
@@ -474,7 +474,7 @@ func resourceEcsInstanceV1Update(d *schema.ResourceData, meta interface{}) error
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		ecsClient, err := config.ComputeV1Client(config.GetRegion(d))
 		if err != nil {
 			return fmtp.Errorf("Error creating HuaweiCloud compute v1 client: %s", err)
@@ -0,0 +1,146 @@
+package dew
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/chnsz/golangsdk/openstack/csms/v1/secrets"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+var _ ephemeral.EphemeralResourceWithConfigure = &csmsSecretVersionEphemeralResource{}
+
+// csmsSecretVersionEphemeralResource is the ephemeral version of the data source huaweicloud_csms_secret_version,
+// the secret text is only available during the terraform operation and never persisted to the state.
+type csmsSecretVersionEphemeralResource struct {
+	cfg *config.Config
+}
+
+type csmsSecretVersionModel struct {
+	Region     types.String `tfsdk:"region"`
+	SecretName types.String `tfsdk:"secret_name"`
+	Version    types.String `tfsdk:"version"`
+	SecretText types.String `tfsdk:"secret_text"`
+	KmsKeyID   types.String `tfsdk:"kms_key_id"`
+	Status     types.List   `tfsdk:"status"`
+	CreatedAt  types.String `tfsdk:"created_at"`
+}
+
+// @API DEW GET /v1/{project_id}/secrets/{secret_name}/versions
+// @API DEW GET /v1/{project_id}/secrets/{secret_name}/versions/{version_id}
+func NewCsmsSecretVersionEphemeralResource() ephemeral.EphemeralResource {
+	return &csmsSecretVersionEphemeralResource{}
+}
+
+func (r *csmsSecretVersionEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest,
+	resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_csms_secret_version"
+}
+
+func (r *csmsSecretVersionEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest,
+	resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Fetches a version of a CSMS secret without persisting the secret text.",
+		Attributes: map[string]schema.Attribute{
+			"region": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The region in which to query the secret.",
+			},
+			"secret_name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the secret.",
+			},
+			"version": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The version ID of the secret, defaults to the latest version.",
+			},
+			"secret_text": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The plaintext of the secret version.",
+			},
+			"kms_key_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the KMS key used to encrypt the secret.",
+			},
+			"status": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "The stages of the secret version.",
+			},
+			"created_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "The creation time of the secret version.",
+			},
+		},
+	}
+}
+
+func (r *csmsSecretVersionEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest,
+	resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	cfg, ok := req.ProviderData.(*config.Config)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data",
+			fmt.Sprintf("Expected *config.Config, but got %T.", req.ProviderData))
+		return
+	}
+	r.cfg = cfg
+}
+
+func (r *csmsSecretVersionEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest,
+	resp *ephemeral.OpenResponse) {
+	var data csmsSecretVersionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	region := data.Region.ValueString()
+	if region == "" {
+		region = r.cfg.Region
+	}
+
+	var version *secrets.Version
+	var err error
+
+	secretName := data.SecretName.ValueString()
+	if ver := data.Version.ValueString(); ver != "" {
+		version, err = queryVersion(r.cfg, region, secretName, ver)
+	} else {
+		version, err = queryLatestVersion(r.cfg, region, secretName)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error querying CSMS secret version", err.Error())
+		return
+	}
+
+	vMetadata := version.VersionMetadata
+	status, diags := types.ListValueFrom(ctx, types.StringType, vMetadata.VersionStages)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Region = types.StringValue(region)
+	data.SecretName = types.StringValue(vMetadata.SecretName)
+	data.Version = types.StringValue(vMetadata.ID)
+	data.SecretText = types.StringValue(version.SecretString)
+	data.KmsKeyID = types.StringValue(vMetadata.KmsKeyID)
+	data.Status = status
+	data.CreatedAt = types.StringValue(
+		time.Unix(int64(vMetadata.CreateTime)/1000, 0).UTC().Format("2006-01-02 15:04:05 MST"))
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
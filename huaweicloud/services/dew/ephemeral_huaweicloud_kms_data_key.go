@@ -0,0 +1,127 @@
+package dew
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/chnsz/golangsdk/openstack/kms/v1/keys"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+var _ ephemeral.EphemeralResourceWithConfigure = &kmsDataKeyEphemeralResource{}
+
+// kmsDataKeyEphemeralResource is the ephemeral version of the data source huaweicloud_kms_data_key, a new data key
+// is generated in each terraform operation and the plaintext is never persisted to the state.
+type kmsDataKeyEphemeralResource struct {
+	cfg *config.Config
+}
+
+type kmsDataKeyModel struct {
+	Region            types.String `tfsdk:"region"`
+	KeyID             types.String `tfsdk:"key_id"`
+	EncryptionContext types.String `tfsdk:"encryption_context"`
+	DatakeyLength     types.String `tfsdk:"datakey_length"`
+	PlainText         types.String `tfsdk:"plain_text"`
+	CipherText        types.String `tfsdk:"cipher_text"`
+}
+
+// @API DEW POST /v1.0/{project_id}/kms/create-datakey
+func NewKmsDataKeyEphemeralResource() ephemeral.EphemeralResource {
+	return &kmsDataKeyEphemeralResource{}
+}
+
+func (r *kmsDataKeyEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest,
+	resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_kms_data_key"
+}
+
+func (r *kmsDataKeyEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest,
+	resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Generates a KMS data key without persisting the plaintext.",
+		Attributes: map[string]schema.Attribute{
+			"region": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The region in which to generate the data key.",
+			},
+			"key_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The ID of the KMS key used to encrypt the data key.",
+			},
+			"encryption_context": schema.StringAttribute{
+				Optional:    true,
+				Description: "The key/value pairs in JSON format used as the encryption context.",
+			},
+			"datakey_length": schema.StringAttribute{
+				Required:    true,
+				Description: "The bit length of the data key.",
+			},
+			"plain_text": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The plaintext of the data key in hex.",
+			},
+			"cipher_text": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ciphertext of the data key in hex.",
+			},
+		},
+	}
+}
+
+func (r *kmsDataKeyEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest,
+	resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	cfg, ok := req.ProviderData.(*config.Config)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data",
+			fmt.Sprintf("Expected *config.Config, but got %T.", req.ProviderData))
+		return
+	}
+	r.cfg = cfg
+}
+
+func (r *kmsDataKeyEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest,
+	resp *ephemeral.OpenResponse) {
+	var data kmsDataKeyModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	region := data.Region.ValueString()
+	if region == "" {
+		region = r.cfg.Region
+	}
+	client, err := r.cfg.KmsKeyV1Client(region)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating KMS client", err.Error())
+		return
+	}
+
+	opts := &keys.DataEncryptOpts{
+		KeyID:             data.KeyID.ValueString(),
+		EncryptionContext: data.EncryptionContext.ValueString(),
+		DatakeyLength:     data.DatakeyLength.ValueString(),
+	}
+	v, err := keys.DataEncryptGet(client, opts).ExtractDataKey()
+	if err != nil {
+		resp.Diagnostics.AddError("Error generating KMS data key", err.Error())
+		return
+	}
+
+	data.Region = types.StringValue(region)
+	data.PlainText = types.StringValue(v.PlainText)
+	data.CipherText = types.StringValue(v.CipherText)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
@@ -137,8 +137,7 @@ func resourceCsmsSecretCreate(ctx context.Context, d *schema.ResourceData, meta
 	d.SetId(id)
 
 	// Save tags
-	if t, ok := d.GetOk("tags"); ok {
-		tMaps := t.(map[string]interface{})
+	if tMaps := utils.GetAllResourceTags(d); len(tMaps) > 0 {
 		tagMaps := utils.ExpandResourceTags(tMaps)
 		err = tags.Create(client, serviceType, rst.ID, tagMaps).ExtractErr()
 		if err != nil {
@@ -311,7 +310,7 @@ func resourceCsmsSecretUpdate(ctx context.Context, d *schema.ResourceData, meta
 	}
 
 	// Update tags
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		err = utils.UpdateResourceTags(client, d, serviceType, id)
 		if err != nil {
 			return diag.Errorf("failed to update CSMS secret tags: %s", err)
@@ -196,7 +196,7 @@ func resourceStreamCreate(ctx context.Context, d *schema.ResourceData, meta inte
 		DataType:          d.Get("data_type").(string),
 		DataSchema:        d.Get("data_schema").(string),
 		CompressionFormat: d.Get("compression_format").(string),
-		Tags:              utils.ExpandResourceTags(d.Get("tags").(map[string]interface{})),
+		Tags:              utils.ExpandResourceTags(utils.GetAllResourceTags(d)),
 	}
 
 	if v, ok := d.GetOk("csv_delimiter"); ok {
@@ -320,7 +320,7 @@ func resourceStreamUpdate(ctx context.Context, d *schema.ResourceData, meta inte
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		streamId := d.Get("stream_id").(string)
 		tagErr := utils.UpdateResourceTags(client, d, "stream", streamId)
 		if tagErr != nil {
@@ -26,17 +26,16 @@ func removeTags(client *golangsdk.ServiceClient, resourceId, resourceType string
 }
 
 func updateResourceTags(client *golangsdk.ServiceClient, resourceId, resourceType string, oldTags, newTags interface{}) error {
-	// remove old tags and set new tags
-	oldTagsRaw := oldTags.(map[string]interface{})
-	if len(oldTagsRaw) > 0 {
-		if err := removeTags(client, resourceId, resourceType, oldTagsRaw); err != nil {
+	// remove the tags which are no longer present, then create the new tags and update the tags whose value is changed
+	removedTags, upsertedTags := utils.DiffTags(oldTags.(map[string]interface{}), newTags.(map[string]interface{}))
+	if len(removedTags) > 0 {
+		if err := removeTags(client, resourceId, resourceType, removedTags); err != nil {
 			return err
 		}
 	}
 
-	newTagsRaw := newTags.(map[string]interface{})
-	if len(newTagsRaw) > 0 {
-		if err := addTags(client, resourceId, resourceType, newTagsRaw); err != nil {
+	if len(upsertedTags) > 0 {
+		if err := addTags(client, resourceId, resourceType, upsertedTags); err != nil {
 			return err
 		}
 	}
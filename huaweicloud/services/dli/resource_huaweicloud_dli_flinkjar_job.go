@@ -380,7 +380,7 @@ func resourceFlinkJarJobUpdate(ctx context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
-	if d.HasChangesExcept("tags") {
+	if d.HasChangesExcept("tags", "tags_all") {
 		client, err := config.DliV1Client(region)
 		if err != nil {
 			return diag.Errorf("error creating DLI v1 client, err=%s", err)
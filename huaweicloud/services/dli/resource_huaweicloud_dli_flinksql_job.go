@@ -355,7 +355,7 @@ func updateJobConfig(client *golangsdk.ServiceClient, jobId int, operatorConfig,
 }
 
 func addTagsToResource(cfg *config.Config, region string, d *schema.ResourceData) error {
-	if raw, ok := d.GetOk("tags"); ok {
+	if raw, ok := d.GetOk(utils.ResourceTagsKey(d)); ok {
 		v3Client, err := cfg.DliV3Client(region)
 		if err != nil {
 			return fmt.Errorf("error creating DLI v3 client: %s", err)
@@ -505,14 +505,14 @@ func resourceFlinkSqlJobDelete(ctx context.Context, d *schema.ResourceData, meta
 }
 
 func updateTagsToResource(cfg *config.Config, region string, d *schema.ResourceData) error {
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		v3Client, err := cfg.DliV3Client(region)
 		if err != nil {
 			return fmt.Errorf("error creating DLI v3 client: %s", err)
 		}
 
 		id := d.Id()
-		oldTags, newTags := d.GetChange("tags")
+		oldTags, newTags := utils.GetAllResourceTagsChange(d)
 		err = updateResourceTags(v3Client, id, "dli_flink_job", oldTags, newTags)
 		if err != nil {
 			return fmt.Errorf("error updating tags of the flink job (%s): %s", id, err)
@@ -528,7 +528,7 @@ func resourceFlinkSqlJobUpdate(ctx context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
-	if d.HasChangesExcept("tags", "graph_type") {
+	if d.HasChangesExcept("tags", "tags_all", "graph_type") {
 		client, err := config.DliV1Client(region)
 		if err != nil {
 			return diag.Errorf("error creating DLI v1 client, err=%s", err)
@@ -93,7 +93,7 @@ func buildDliDependentPackageCreateOpts(d *schema.ResourceData) resources.Create
 		Kind:    d.Get("type").(string),
 		Group:   d.Get("group_name").(string),
 		IsAsync: d.Get("is_async").(bool),
-		Tags:    utils.ExpandResourceTags(d.Get("tags").(map[string]interface{})),
+		Tags:    utils.ExpandResourceTags(utils.GetAllResourceTags(d)),
 	}
 	return result
 }
@@ -242,14 +242,14 @@ func ResourceDliDependentPackageV2Update(ctx context.Context, d *schema.Resource
 		return diag.FromErr(err)
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		v3Client, err := cfg.DliV3Client(region)
 		if err != nil {
 			return diag.Errorf("error creating DLI v3 client: %s", err)
 		}
 
 		resourceId := getASCIIFormationId(d.Id())
-		oldTags, newTags := d.GetChange("tags")
+		oldTags, newTags := utils.GetAllResourceTagsChange(d)
 		err = updateResourceTags(v3Client, resourceId, "dli_package_resource", oldTags, newTags)
 		if err != nil {
 			return diag.Errorf("error updating tags of the package (%s): %s", resourceId, err)
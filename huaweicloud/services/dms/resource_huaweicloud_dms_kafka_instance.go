@@ -660,7 +660,7 @@ func createKafkaInstanceWithFlavor(ctx context.Context, d *schema.ResourceData,
 	createOpts.AvailableZones = availableZones
 
 	// set tags
-	if tagRaw := d.Get("tags").(map[string]interface{}); len(tagRaw) > 0 {
+	if tagRaw := utils.GetAllResourceTags(d); len(tagRaw) > 0 {
 		createOpts.Tags = utils.ExpandResourceTags(tagRaw)
 	}
 	log.Printf("[DEBUG] Create DMS Kafka instance options: %#v", createOpts)
@@ -792,7 +792,7 @@ func createKafkaInstanceWithProductID(ctx context.Context, d *schema.ResourceDat
 	}
 
 	// set tags
-	if tagsRaw := d.Get("tags").(map[string]interface{}); len(tagsRaw) > 0 {
+	if tagsRaw := utils.GetAllResourceTags(d); len(tagsRaw) > 0 {
 		createOpts.Tags = utils.ExpandResourceTags(tagsRaw)
 	}
 	log.Printf("[DEBUG] Create DMS Kafka instance options: %#v", createOpts)
@@ -1194,7 +1194,7 @@ func resourceDmsKafkaInstanceUpdate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		// update tags
 		if err = utils.UpdateResourceTags(client, d, engineKafka, d.Id()); err != nil {
 			mErr = multierror.Append(mErr, fmt.Errorf("error updating tags of Kafka instance: %s, err: %s",
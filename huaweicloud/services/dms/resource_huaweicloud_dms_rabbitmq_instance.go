@@ -366,7 +366,7 @@ func createRabbitMQInstanceWithFlavor(ctx context.Context, d *schema.ResourceDat
 	}
 
 	// set tags
-	if tagRaw := d.Get("tags").(map[string]interface{}); len(tagRaw) > 0 {
+	if tagRaw := utils.GetAllResourceTags(d); len(tagRaw) > 0 {
 		createOpts.Tags = utils.ExpandResourceTags(tagRaw)
 	}
 
@@ -525,7 +525,7 @@ func createRabbitMQInstanceWithProductID(ctx context.Context, d *schema.Resource
 	}
 
 	// set tags
-	if tagRaw := d.Get("tags").(map[string]interface{}); len(tagRaw) > 0 {
+	if tagRaw := utils.GetAllResourceTags(d); len(tagRaw) > 0 {
 		createOpts.Tags = utils.ExpandResourceTags(tagRaw)
 	}
 
@@ -727,7 +727,7 @@ func resourceDmsRabbitmqInstanceUpdate(ctx context.Context, d *schema.ResourceDa
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		// update tags
 		tagErr := utils.UpdateResourceTags(client, d, engineRabbitMQ, d.Id())
 		if tagErr != nil {
@@ -115,7 +115,7 @@ func resourceDNSPtrRecordCreate(ctx context.Context, d *schema.ResourceData, met
 }
 
 func getPtrRecordsTagList(d *schema.ResourceData) []ptrrecords.Tag {
-	tagMap := d.Get("tags").(map[string]interface{})
+	tagMap := utils.GetAllResourceTags(d)
 	var tagList []ptrrecords.Tag
 	for k, v := range tagMap {
 		tagList = append(tagList, ptrrecords.Tag{
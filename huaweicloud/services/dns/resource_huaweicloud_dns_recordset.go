@@ -236,7 +236,7 @@ func buildCreateDNSRecordsetBodyParams(d *schema.ResourceData) map[string]interf
 		"ttl":         utils.ValueIgnoreEmpty(d.Get("ttl")),
 		"records":     utils.ValueIgnoreEmpty(d.Get("records")),
 		"line":        utils.ValueIgnoreEmpty(d.Get("line_id")),
-		"tags":        utils.ExpandResourceTagsMap(d.Get("tags").(map[string]interface{})),
+		"tags":        utils.ExpandResourceTagsMap(utils.GetAllResourceTags(d)),
 		"weight":      utils.ValueIgnoreEmpty(d.Get("weight")),
 	}
 	return bodyParams
@@ -370,7 +370,7 @@ func resourceDNSRecordsetUpdate(ctx context.Context, d *schema.ResourceData, met
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		resourceType, err := utils.GetDNSRecordSetTagType(zoneType)
 		if err != nil {
 			return diag.FromErr(err)
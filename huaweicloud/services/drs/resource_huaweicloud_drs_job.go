@@ -1039,7 +1039,7 @@ func resourceJobUpdate(ctx context.Context, d *schema.ResourceData, meta interfa
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		tagErr := utils.UpdateResourceTags(clientV5, d, "jobs/"+d.Get("type").(string), d.Id())
 		if tagErr != nil {
 			return diag.Diagnostics{
@@ -1428,7 +1428,7 @@ func buildCreateParamter(d *schema.ResourceData, projectId, enterpriseProjectID
 		SourceEndpoint:   *sourceDb,
 		TargetEndpoint:   *targetDb,
 		SubnetId:         subnetId,
-		Tags:             utils.ExpandResourceTags(d.Get("tags").(map[string]interface{})),
+		Tags:             utils.ExpandResourceTags(utils.GetAllResourceTags(d)),
 		SysTags:          utils.BuildSysTags(enterpriseProjectID),
 		MasterAz:         d.Get("master_az").(string),
 		SlaveAz:          d.Get("slave_az").(string),
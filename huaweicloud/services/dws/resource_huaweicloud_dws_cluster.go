@@ -1276,18 +1276,20 @@ func updateClusterTags(client *golangsdk.ServiceClient, d *schema.ResourceData,
 	oMap := oRaw.(map[string]interface{})
 	nMap := nRaw.(map[string]interface{})
 
-	// remove old tags
-	if len(oMap) > 0 {
-		taglist := utils.ExpandResourceTags(oMap)
+	removedTags, upsertedTags := utils.DiffTags(oMap, nMap)
+
+	// remove the tags which are no longer present
+	if len(removedTags) > 0 {
+		taglist := utils.ExpandResourceTags(removedTags)
 		err := deleteClusterTags(client, id, taglist)
 		if err != nil {
 			return err
 		}
 	}
 
-	// set new tags
-	if len(nMap) > 0 {
-		taglist := utils.ExpandResourceTags(nMap)
+	// create the new tags and update the tags whose value is changed
+	if len(upsertedTags) > 0 {
+		taglist := utils.ExpandResourceTags(upsertedTags)
 		err := addClusterTags(client, id, taglist)
 		if err != nil {
 			return err
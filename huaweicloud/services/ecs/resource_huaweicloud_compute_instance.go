@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -71,6 +72,8 @@ func ResourceComputeInstance() *schema.Resource {
 			StateContext: resourceComputeInstanceImportState,
 		},
 
+		CustomizeDiff: common.PreflightQuotaCheck(computeInstanceQuotaDemands),
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
 			Update: schema.DefaultTimeout(30 * time.Minute),
@@ -133,9 +136,22 @@ func ResourceComputeInstance() *schema.Resource {
 				Description: "schema: Computed",
 			},
 			"admin_pass": {
-				Type:      schema.TypeString,
-				Sensitive: true,
-				Optional:  true,
+				Type:          schema.TypeString,
+				Sensitive:     true,
+				Optional:      true,
+				ConflictsWith: []string{"admin_pass_wo"},
+			},
+			"admin_pass_wo": {
+				Type:          schema.TypeString,
+				Sensitive:     true,
+				Optional:      true,
+				WriteOnly:     true,
+				ConflictsWith: []string{"admin_pass"},
+			},
+			"admin_pass_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"admin_pass_wo"},
 			},
 			"key_pair": {
 				Type:     schema.TypeString,
@@ -626,8 +642,8 @@ func resourceComputeInstanceCreate(ctx context.Context, d *schema.ResourceData,
 		AutoTerminateTime: d.Get("auto_terminate_time").(string),
 	}
 
-	if tags, ok := d.GetOk("tags"); ok {
-		createOpts.ServerTags = utils.ExpandResourceTags(tags.(map[string]interface{}))
+	if tags := utils.GetAllResourceTags(d); len(tags) > 0 {
+		createOpts.ServerTags = utils.ExpandResourceTags(tags)
 	}
 
 	var extendParam cloudservers.ServerExtendParam
@@ -685,7 +701,7 @@ func resourceComputeInstanceCreate(ctx context.Context, d *schema.ResourceData,
 
 	log.Printf("[DEBUG] ECS create options: %#v", createOpts)
 	// Add password here so it wouldn't go in the above log entry
-	createOpts.AdminPass = d.Get("admin_pass").(string)
+	createOpts.AdminPass = getAdminPass(d)
 
 	if d.Get("charging_mode") == "prePaid" {
 		// prePaid.
@@ -1059,12 +1075,11 @@ func resourceComputeInstanceUpdate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	if d.HasChange("admin_pass") {
-		if newPwd, ok := d.Get("admin_pass").(string); ok {
-			err := cloudservers.ChangeAdminPassword(ecsClient, serverID, newPwd).ExtractErr()
-			if err != nil {
-				return diag.Errorf("error changing admin password of server (%s): %s", serverID, err)
-			}
+	// the write-only password is changed only when its version is changed
+	if d.HasChanges("admin_pass", "admin_pass_wo_version") {
+		err := cloudservers.ChangeAdminPassword(ecsClient, serverID, getAdminPass(d)).ExtractErr()
+		if err != nil {
+			return diag.Errorf("error changing admin password of server (%s): %s", serverID, err)
 		}
 	}
 
@@ -1105,7 +1120,7 @@ func resourceComputeInstanceUpdate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		tagErr := utils.UpdateResourceTags(ecsClient, d, "cloudservers", serverID)
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of instance:%s, err:%s", serverID, err)
@@ -1194,7 +1209,7 @@ func resourceComputeInstanceUpdate(ctx context.Context, d *schema.ResourceData,
 			InUsedKeyPair:    o.(string),
 			NewKeyPair:       n.(string),
 			InUsedPrivateKey: d.Get("private_key").(string),
-			Password:         d.Get("admin_pass").(string),
+			Password:         getAdminPass(d),
 			Timeout:          d.Timeout(schema.TimeoutUpdate),
 		}
 		if err := common.UpdateEcsInstanceKeyPair(ctx, ecsClient, kmsClient, keyPairOpts); err != nil {
@@ -1639,6 +1654,73 @@ func getFlavorID(d *schema.ResourceData) (string, error) {
 	return flavorID, nil
 }
 
+// getAdminPass returns the admin password, which is specified by either admin_pass or the write-only admin_pass_wo.
+func getAdminPass(d *schema.ResourceData) string {
+	if v, ok := d.GetOk("admin_pass"); ok {
+		return v.(string)
+	}
+	return common.GetWriteOnlyString(d, cty.GetAttrPath("admin_pass_wo"))
+}
+
+// computeInstanceQuotaDemands returns the ECS, EVS and EIP quotas requested by the instance being created, the
+// demands which are unknown during the plan are not counted.
+// @API ECS GET /v2.1/{project_id}/flavors/{flavor_id}
+func computeInstanceQuotaDemands(_ context.Context, d *schema.ResourceDiff,
+	cfg *config.Config) (map[string]map[string]int, error) {
+	ecsDemands := map[string]int{
+		common.QuotaECSInstances: 1,
+	}
+	if flavorID := d.Get("flavor_id").(string); flavorID != "" && d.NewValueKnown("flavor_id") {
+		region := cfg.Region
+		if v, ok := d.GetOk("region"); ok {
+			region = v.(string)
+		}
+		client, err := cfg.NewServiceClient("ecs", region)
+		if err != nil {
+			return nil, fmt.Errorf("error creating ECS client: %s", err)
+		}
+
+		getFlavorPath := client.Endpoint + "v2.1/{project_id}/flavors/{flavor_id}"
+		getFlavorPath = strings.ReplaceAll(getFlavorPath, "{project_id}", client.ProjectID)
+		getFlavorPath = strings.ReplaceAll(getFlavorPath, "{flavor_id}", flavorID)
+		getFlavorOpt := golangsdk.RequestOpts{
+			KeepResponseBody: true,
+		}
+		resp, err := client.Request("GET", getFlavorPath, &getFlavorOpt)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving ECS flavor (%s): %s", flavorID, err)
+		}
+		respBody, err := utils.FlattenResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+		ecsDemands[common.QuotaECSCores] = int(utils.PathSearch("flavor.vcpus", respBody, float64(0)).(float64))
+		ecsDemands[common.QuotaECSRAM] = int(utils.PathSearch("flavor.ram", respBody, float64(0)).(float64))
+	}
+
+	dataDisks := d.Get("data_disks").([]interface{})
+	evsDemands := map[string]int{
+		common.QuotaEVSVolumes: 1 + len(dataDisks),
+	}
+	// the size of the system disk is 0 if it's computed from the image, only the known sizes are counted
+	if d.NewValueKnown("system_disk_size") && d.NewValueKnown("data_disks") {
+		gigabytes := d.Get("system_disk_size").(int)
+		for _, v := range dataDisks {
+			gigabytes += v.(map[string]interface{})["size"].(int)
+		}
+		evsDemands[common.QuotaEVSGigabytes] = gigabytes
+	}
+
+	demands := map[string]map[string]int{
+		"ecs": ecsDemands,
+		"evs": evsDemands,
+	}
+	if _, ok := d.GetOk("eip_type"); ok {
+		demands["vpc"] = map[string]int{common.QuotaVPCPublicIPs: 1}
+	}
+	return demands, nil
+}
+
 func getVpcID(d *schema.ResourceData, client *golangsdk.ServiceClient) (string, error) {
 	var networkID string
 
@@ -82,6 +82,8 @@ func ResourceVpcEIPV1() *schema.Resource {
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		CustomizeDiff: common.PreflightQuotaCheck(vpcEipQuotaDemands),
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
 			Update: schema.DefaultTimeout(5 * time.Minute),
@@ -378,6 +380,13 @@ func createPostPaidEip(ctx context.Context, cfg *config.Config, client *golangsd
 	return nil
 }
 
+func vpcEipQuotaDemands(_ context.Context, _ *schema.ResourceDiff, _ *config.Config) (map[string]map[string]int,
+	error) {
+	return map[string]map[string]int{
+		"vpc": {common.QuotaVPCPublicIPs: 1},
+	}, nil
+}
+
 func resourceVpcEipCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	cfg := meta.(*config.Config)
 	region := cfg.GetRegion(d)
@@ -411,7 +420,7 @@ func resourceVpcEipCreate(ctx context.Context, d *schema.ResourceData, meta inte
 	}
 
 	// create tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	if len(tagRaw) > 0 {
 		taglist := utils.ExpandResourceTags(tagRaw)
 		if tagErr := tags.Create(vpcV2Client, "publicips", d.Id(), taglist).ExtractErr(); tagErr != nil {
@@ -702,7 +711,7 @@ func resourceVpcEipUpdate(ctx context.Context, d *schema.ResourceData, meta inte
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		tagErr := utils.UpdateResourceTags(vpcV2Client, d, "publicips", d.Id())
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of VPC (%s): %s", d.Id(), tagErr)
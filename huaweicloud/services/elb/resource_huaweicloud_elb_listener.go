@@ -449,7 +449,7 @@ func resourceListenerV3Create(ctx context.Context, d *schema.ResourceData, meta
 	d.SetId(listener.ID)
 
 	// set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	if len(tagRaw) > 0 {
 		elbV2Client, err := cfg.ElbV2Client(cfg.GetRegion(d))
 		if err != nil {
@@ -583,7 +583,7 @@ func resourceListenerV3Update(ctx context.Context, d *schema.ResourceData, meta
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		elbV2Client, err := cfg.ElbV2Client(cfg.GetRegion(d))
 		if err != nil {
 			return diag.Errorf("error creating ELB 2.0 client: %s", err)
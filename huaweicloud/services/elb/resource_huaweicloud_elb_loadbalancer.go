@@ -463,7 +463,7 @@ func resourceLoadBalancerV3Create(ctx context.Context, d *schema.ResourceData, m
 	d.SetId(loadBalancerID)
 
 	// set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	if len(tagRaw) > 0 {
 		elbV2Client, err := cfg.ElbV2Client(cfg.GetRegion(d))
 		if err != nil {
@@ -643,7 +643,7 @@ func resourceLoadBalancerV3Update(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		elbV2Client, err := cfg.ElbV2Client(cfg.GetRegion(d))
 		if err != nil {
 			return diag.Errorf("error creating ELB 2.0 client: %s", err)
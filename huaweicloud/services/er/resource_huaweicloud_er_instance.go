@@ -181,7 +181,7 @@ func resourceInstanceCreate(ctx context.Context, d *schema.ResourceData, meta in
 		return diag.Errorf("error waiting for the create operation of the instance (%s) to complete: %s", d.Id(), err)
 	}
 
-	if _, ok := d.GetOk("tags"); ok {
+	if len(utils.GetAllResourceTags(d)) > 0 {
 		err = utils.UpdateResourceTags(client, d, "instance", d.Id())
 		if err != nil {
 			return diag.Errorf("error creating instance tags: %s", err)
@@ -387,7 +387,7 @@ func resourceInstanceUpdate(ctx context.Context, d *schema.ResourceData, meta in
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		err = utils.UpdateResourceTags(client, d, "instance", instanceId)
 		if err != nil {
 			return diag.Errorf("error updating instance tags: %s", err)
@@ -118,7 +118,7 @@ func buildRouteTableCreateOpts(d *schema.ResourceData) routetables.CreateOpts {
 	return routetables.CreateOpts{
 		Name:        d.Get("name").(string),
 		Description: d.Get("description").(string),
-		Tags:        utils.ExpandResourceTags(d.Get("tags").(map[string]interface{})),
+		Tags:        utils.ExpandResourceTags(utils.GetAllResourceTags(d)),
 	}
 }
 
@@ -252,7 +252,7 @@ func resourceRouteTableUpdate(ctx context.Context, d *schema.ResourceData, meta
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		err = utils.UpdateResourceTags(client, d, "route-table", d.Id())
 		if err != nil {
 			return diag.Errorf("error updating route table tags: %s", err)
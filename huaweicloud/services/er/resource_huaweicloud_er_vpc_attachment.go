@@ -130,7 +130,7 @@ func resourceVpcAttachmentCreate(ctx context.Context, d *schema.ResourceData, me
 		Name:                d.Get("name").(string),
 		Description:         d.Get("description").(string),
 		AutoCreateVpcRoutes: utils.Bool(d.Get("auto_create_vpc_routes").(bool)),
-		Tags:                utils.ExpandResourceTags(d.Get("tags").(map[string]interface{})),
+		Tags:                utils.ExpandResourceTags(utils.GetAllResourceTags(d)),
 	}
 	instanceId := d.Get("instance_id").(string)
 	resp, err := vpcattachments.Create(client, instanceId, opts)
@@ -256,7 +256,7 @@ func resourceVpcAttachmentUpdate(ctx context.Context, d *schema.ResourceData, me
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		err = utils.UpdateResourceTags(client, d, "vpc-attachment", d.Id())
 		if err != nil {
 			return diag.Errorf("error updating VPC attachment tags: %s", err)
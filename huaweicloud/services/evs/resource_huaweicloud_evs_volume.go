@@ -54,6 +54,8 @@ func ResourceEvsVolume() *schema.Resource {
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		CustomizeDiff: common.PreflightQuotaCheck(evsVolumeQuotaDemands),
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
 			Update: schema.DefaultTimeout(3 * time.Minute),
@@ -268,6 +270,19 @@ func buildEvsVolumeCreateOpts(d *schema.ResourceData, cfg *config.Config) cloudv
 	return result
 }
 
+// evsVolumeQuotaDemands returns the EVS quotas requested by the volume being created, the size is not counted if it's
+// unknown or computed from the backup.
+func evsVolumeQuotaDemands(_ context.Context, d *schema.ResourceDiff, _ *config.Config) (map[string]map[string]int,
+	error) {
+	demands := map[string]int{
+		common.QuotaEVSVolumes: 1,
+	}
+	if size := d.Get("size").(int); size > 0 && d.NewValueKnown("size") {
+		demands[common.QuotaEVSGigabytes] = size
+	}
+	return map[string]map[string]int{"evs": demands}, nil
+}
+
 func resourceEvsVolumeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	cfg := meta.(*config.Config)
 	// The v2 client is used to obtain the volume detail.
@@ -530,7 +545,7 @@ func resourceEvsVolumeUpdate(ctx context.Context, d *schema.ResourceData, meta i
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		tagErr := utils.UpdateResourceTags(evsV2Client, d, "cloudvolumes", d.Id())
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of volume:%s, err:%s", d.Id(), tagErr)
@@ -619,7 +634,7 @@ func resourceEvsVolumeUpdate(ctx context.Context, d *schema.ResourceData, meta i
 
 func resourceContainerTags(d *schema.ResourceData) map[string]string {
 	m := make(map[string]string)
-	for key, val := range d.Get("tags").(map[string]interface{}) {
+	for key, val := range utils.GetAllResourceTags(d) {
 		m[key] = val.(string)
 	}
 	return m
@@ -934,19 +934,22 @@ func updateFunctionTags(client *golangsdk.ServiceClient, d *schema.ResourceData)
 		nMap        = nRaw.(map[string]interface{})
 		functionUrn = d.Id()
 	)
+	removedTags, upsertedTags := utils.DiffTags(oMap, nMap)
 
-	if len(oMap) > 0 {
+	// remove the tags which are no longer present
+	if len(removedTags) > 0 {
 		opts := function.TagsActionOpts{
-			Tags: utils.ExpandResourceTags(oMap),
+			Tags: utils.ExpandResourceTags(removedTags),
 		}
 		if err := function.DeleteResourceTags(client, functionUrn, opts); err != nil {
 			return fmt.Errorf("failed to delete tags from FunctionGraph function (%s): %s", functionUrn, err)
 		}
 	}
 
-	if len(nMap) > 0 {
+	// create the new tags and update the tags whose value is changed
+	if len(upsertedTags) > 0 {
 		opts := function.TagsActionOpts{
-			Tags: utils.ExpandResourceTags(nMap),
+			Tags: utils.ExpandResourceTags(upsertedTags),
 		}
 		if err := function.CreateResourceTags(client, functionUrn, opts); err != nil {
 			return fmt.Errorf("failed to add tags to FunctionGraph function (%s): %s", functionUrn, err)
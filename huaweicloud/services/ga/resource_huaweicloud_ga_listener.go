@@ -434,16 +434,18 @@ func resourceListenerUpdate(ctx context.Context, d *schema.ResourceData, meta in
 		oldMap := oldRaw.(map[string]interface{})
 		newMap := newRaw.(map[string]interface{})
 
-		// remove old tags
-		if len(oldMap) > 0 {
-			if err = deleteTags(client, "ga-listeners", d.Id(), oldMap); err != nil {
+		removedTags, upsertedTags := utils.DiffTags(oldMap, newMap)
+
+		// remove the tags which are no longer present
+		if len(removedTags) > 0 {
+			if err = deleteTags(client, "ga-listeners", d.Id(), removedTags); err != nil {
 				return diag.FromErr(err)
 			}
 		}
 
-		// set new tags
-		if len(newMap) > 0 {
-			if err := createTags(client, "ga-listeners", d.Id(), newMap); err != nil {
+		// create the new tags and update the tags whose value is changed
+		if len(upsertedTags) > 0 {
+			if err := createTags(client, "ga-listeners", d.Id(), upsertedTags); err != nil {
 				return diag.FromErr(err)
 			}
 		}
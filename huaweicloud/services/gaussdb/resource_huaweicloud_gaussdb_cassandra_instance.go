@@ -476,7 +476,7 @@ func resourceGeminiDBInstanceV3Create(ctx context.Context, d *schema.ResourceDat
 	}
 
 	// set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	if len(tagRaw) > 0 {
 		taglist := utils.ExpandResourceTags(tagRaw)
 		if tagErr := tags.Create(client, "instances", d.Id(), taglist).ExtractErr(); tagErr != nil {
@@ -681,7 +681,7 @@ func resourceGeminiDBInstanceV3Update(ctx context.Context, d *schema.ResourceDat
 	}
 	// update tags
 	instanceId := d.Id()
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		tagErr := utils.UpdateResourceTags(client, d, "instances", instanceId)
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of GeminiDB %q: %s", instanceId, tagErr)
@@ -619,7 +619,7 @@ func resourceGaussDBInstanceCreate(ctx context.Context, d *schema.ResourceData,
 	}
 
 	// set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	if len(tagRaw) > 0 {
 		taglist := utils.ExpandResourceTags(tagRaw)
 		if tagErr := tags.Create(client, "instances", d.Id(), taglist).ExtractErr(); tagErr != nil {
@@ -1037,7 +1037,7 @@ func resourceGaussDBInstanceUpdate(ctx context.Context, d *schema.ResourceData,
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		tagErr := utils.UpdateResourceTags(client, d, "instances", instanceId)
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of Gaussdb mysql instance %q: %s", instanceId, tagErr)
@@ -321,15 +321,18 @@ func resourceQuotaUpdate(ctx context.Context, d *schema.ResourceData, meta inter
 		oRaw, nRaw := d.GetChange("tags")
 		oMap := oRaw.(map[string]interface{})
 		nMap := nRaw.(map[string]interface{})
+		removedTags, upsertedTags := utils.DiffTags(oMap, nMap)
 
-		if len(oMap) > 0 {
-			oldKeys := getOldTagKeys(oMap)
+		// remove the tags which are no longer present
+		if len(removedTags) > 0 {
+			oldKeys := getOldTagKeys(removedTags)
 			if err := utils.DeleteResourceTagsWithKeys(client, oldKeys, "hss", id); err != nil {
 				return diag.FromErr(err)
 			}
 		}
-		if len(nMap) > 0 {
-			if err := createQuotaTags(client, id, nMap); err != nil {
+		// create the new tags and update the tags whose value is changed
+		if len(upsertedTags) > 0 {
+			if err := createQuotaTags(client, id, upsertedTags); err != nil {
 				return diag.FromErr(err)
 			}
 		}
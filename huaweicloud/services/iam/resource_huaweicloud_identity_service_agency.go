@@ -343,16 +343,18 @@ func resourceIAMServiceAgencyUpdate(ctx context.Context, d *schema.ResourceData,
 		oMap := oRaw.(map[string]interface{})
 		nMap := nRaw.(map[string]interface{})
 
-		// remove old tags
-		if len(oMap) > 0 {
-			if err = deleteTags(client, oMap, "agency", d.Id()); err != nil {
+		removedTags, upsertedTags := utils.DiffTags(oMap, nMap)
+
+		// remove the tags which are no longer present
+		if len(removedTags) > 0 {
+			if err = deleteTags(client, removedTags, "agency", d.Id()); err != nil {
 				return diag.FromErr(err)
 			}
 		}
 
-		// set new tags
-		if len(nMap) > 0 {
-			if err := createTags(client, nMap, "agency", d.Id()); err != nil {
+		// create the new tags and update the tags whose value is changed
+		if len(upsertedTags) > 0 {
+			if err := createTags(client, upsertedTags, "agency", d.Id()); err != nil {
 				return diag.FromErr(err)
 			}
 		}
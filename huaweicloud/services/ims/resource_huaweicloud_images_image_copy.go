@@ -236,7 +236,7 @@ func resourceImsImageCopyCreate(ctx context.Context, d *schema.ResourceData, met
 	}
 
 	// set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	if len(tagRaw) > 0 {
 		tagList := utils.ExpandResourceTags(tagRaw)
 		if tagErr := tags.Create(imsV2Client, "images", d.Id(), tagList).ExtractErr(); tagErr != nil {
@@ -302,7 +302,7 @@ func resourceImsImageCopyUpdate(ctx context.Context, d *schema.ResourceData, met
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		tagErr := utils.UpdateResourceTags(imsClient, d, "images", d.Id())
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of IMS image :%s, err:%s", d.Id(), tagErr)
@@ -407,9 +407,11 @@ func ResourceDeviceDelete(_ context.Context, d *schema.ResourceData, meta interf
 }
 
 func bindDeviceTags(client *iotdav5.IoTDAClient, deviceId string, oMap, nMap map[string]interface{}) error {
-	// remove old tags
-	if len(oMap) > 0 {
-		keys := ExpandKeyOfTags(oMap)
+	removedTags, upsertedTags := utils.DiffTags(oMap, nMap)
+
+	// remove the tags which are no longer present
+	if len(removedTags) > 0 {
+		keys := ExpandKeyOfTags(removedTags)
 		_, err := client.UntagDevice(&model.UntagDeviceRequest{
 			Body: &model.UnbindTagsDto{
 				ResourceType: "device",
@@ -422,9 +424,9 @@ func bindDeviceTags(client *iotdav5.IoTDAClient, deviceId string, oMap, nMap map
 		}
 	}
 
-	// set new tags
-	if len(nMap) > 0 {
-		taglist := ExpandResourceTags(nMap)
+	// create the new tags and update the tags whose value is changed
+	if len(upsertedTags) > 0 {
+		taglist := ExpandResourceTags(upsertedTags)
 		_, err := client.TagDevice(&model.TagDeviceRequest{
 			Body: &model.BindTagsDto{
 				ResourceType: "device",
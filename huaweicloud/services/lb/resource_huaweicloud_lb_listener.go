@@ -189,7 +189,7 @@ func resourceListenerV2Create(ctx context.Context, d *schema.ResourceData, meta
 	d.SetId(listener.ID)
 
 	// set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	if len(tagRaw) > 0 {
 		tagList := utils.ExpandResourceTags(tagRaw)
 		if tagErr := tags.Create(lbv2Client, "listeners", listener.ID, tagList).ExtractErr(); tagErr != nil {
@@ -333,7 +333,7 @@ func resourceListenerV2Update(ctx context.Context, d *schema.ResourceData, meta
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		tagErr := utils.UpdateResourceTags(lbv2Client, d, "listeners", d.Id())
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of ELB listener:%s, err:%s", d.Id(), tagErr)
@@ -9,6 +9,8 @@ import (
 	"github.com/jmespath/go-jmespath"
 
 	"github.com/chnsz/golangsdk"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
 )
 
 // parseQueryError500 is a method used to parse whether a 500 error message means the resources not found.
@@ -80,11 +82,13 @@ func updateTags(client *golangsdk.ServiceClient, resourceType, resourceId string
 			200, 201,
 		},
 	}
-	// remove old tags
-	if len(oMap) > 0 {
+	removedTags, upsertedTags := utils.DiffTags(oMap, nMap)
+
+	// remove the tags which are no longer present
+	if len(removedTags) > 0 {
 		opts.JSONBody = map[string]interface{}{
 			"action": "delete",
-			"tags":   expandTagsToList(oMap),
+			"tags":   expandTagsToList(removedTags),
 		}
 		_, err := client.Request("POST", path, &opts)
 		if err != nil {
@@ -92,11 +96,11 @@ func updateTags(client *golangsdk.ServiceClient, resourceType, resourceId string
 		}
 	}
 
-	// set new tags
-	if len(nMap) > 0 {
+	// create the new tags and update the tags whose value is changed
+	if len(upsertedTags) > 0 {
 		opts.JSONBody = map[string]interface{}{
 			"action": "create",
-			"tags":   expandTagsToList(nMap),
+			"tags":   expandTagsToList(upsertedTags),
 		}
 
 		_, err := client.Request("POST", path, &opts)
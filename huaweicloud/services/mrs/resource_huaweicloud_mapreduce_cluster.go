@@ -1480,10 +1480,12 @@ func updateResourceTagsWithSleep(conn *golangsdk.ServiceClient, d *schema.Resour
 		oMap := oRaw.(map[string]interface{})
 		nMap := nRaw.(map[string]interface{})
 
-		// remove old tags
-		if len(oMap) > 0 {
-			removedTags := utils.ExpandResourceTags(oMap)
-			err := tags.Delete(conn, resourceType, id, removedTags).ExtractErr()
+		removedTags, upsertedTags := utils.DiffTags(oMap, nMap)
+
+		// remove the tags which are no longer present
+		if len(removedTags) > 0 {
+			taglist := utils.ExpandResourceTags(removedTags)
+			err := tags.Delete(conn, resourceType, id, taglist).ExtractErr()
 			if err != nil {
 				return err
 			}
@@ -1491,9 +1493,9 @@ func updateResourceTagsWithSleep(conn *golangsdk.ServiceClient, d *schema.Resour
 			time.Sleep(5 * time.Second)
 		}
 
-		// set new tags
-		if len(nMap) > 0 {
-			taglist := utils.ExpandResourceTags(nMap)
+		// create the new tags and update the tags whose value is changed
+		if len(upsertedTags) > 0 {
+			taglist := utils.ExpandResourceTags(upsertedTags)
 			err := tags.Create(conn, resourceType, id, taglist).ExtractErr()
 			if err != nil {
 				return err
@@ -183,12 +183,12 @@ func resourcePublicGatewayCreate(ctx context.Context, d *schema.ResourceData, me
 		return diag.FromErr(err)
 	}
 
-	if gatewayTags, ok := d.GetOk("tags"); ok {
+	if gatewayTags := utils.GetAllResourceTags(d); len(gatewayTags) > 0 {
 		networkClient, err := cfg.NetworkingV2Client(region)
 		if err != nil {
 			return diag.Errorf("error creating VPC v2.0 client: %s", err)
 		}
-		taglist := utils.ExpandResourceTags(gatewayTags.(map[string]interface{}))
+		taglist := utils.ExpandResourceTags(gatewayTags)
 		err = tags.Create(networkClient, "nat_gateways", d.Id(), taglist).ExtractErr()
 		if err != nil {
 			return diag.Errorf("error setting tags to the NAT gateway: %s", err)
@@ -247,7 +247,7 @@ func resourcePublicGatewayUpdate(ctx context.Context, d *schema.ResourceData, me
 		gatewayId = d.Id()
 	)
 
-	if d.HasChangeExcept("tags") {
+	if d.HasChangesExcept("tags", "tags_all") {
 		client, err := cfg.NatGatewayClient(region)
 		if err != nil {
 			return diag.Errorf("error creating NAT v2 client: %s", err)
@@ -275,7 +275,7 @@ func resourcePublicGatewayUpdate(ctx context.Context, d *schema.ResourceData, me
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		networkClient, err := cfg.NetworkingV2Client(region)
 		if err != nil {
 			return diag.Errorf("error creating VPC v2.0 client: %s", err)
@@ -139,7 +139,7 @@ func resourcePrivateGatewayCreate(ctx context.Context, d *schema.ResourceData, m
 		Description:         d.Get("description").(string),
 		Spec:                d.Get("spec").(string),
 		EnterpriseProjectId: cfg.GetEnterpriseProjectID(d),
-		Tags:                utils.ExpandResourceTags(d.Get("tags").(map[string]interface{})),
+		Tags:                utils.ExpandResourceTags(utils.GetAllResourceTags(d)),
 	}
 
 	log.Printf("[DEBUG] The create options of the private NAT gateway is: %#v", opts)
@@ -193,7 +193,7 @@ func resourcePrivateGatewayUpdate(ctx context.Context, d *schema.ResourceData, m
 	}
 
 	gatewayId := d.Id()
-	if d.HasChangeExcept("tags") {
+	if d.HasChangesExcept("tags", "tags_all") {
 		opts := gateways.UpdateOpts{
 			Name:        d.Get("name").(string),
 			Description: utils.String(d.Get("description").(string)),
@@ -209,7 +209,7 @@ func resourcePrivateGatewayUpdate(ctx context.Context, d *schema.ResourceData, m
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		err = utils.UpdateResourceTags(natClient, d, "private-nat-gateways", gatewayId)
 		if err != nil {
 			return diag.Errorf("error updating tags of the private NAT gateway (%s): %s", gatewayId, err)
@@ -94,7 +94,7 @@ func resourcePrivateTransitIpCreate(ctx context.Context, d *schema.ResourceData,
 		SubnetId:            d.Get("subnet_id").(string),
 		IpAddress:           d.Get("ip_address").(string),
 		EnterpriseProjectId: cfg.GetEnterpriseProjectID(d),
-		Tags:                utils.ExpandResourceTags(d.Get("tags").(map[string]interface{})),
+		Tags:                utils.ExpandResourceTags(utils.GetAllResourceTags(d)),
 	}
 
 	log.Printf("[DEBUG] The create options of the transit IP (Private NAT) is: %#v", opts)
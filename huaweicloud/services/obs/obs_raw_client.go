@@ -0,0 +1,140 @@
+package obs
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chnsz/golangsdk/openstack/obs"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+// obsRawClient sends the bucket requests whose sub-resources (e.g. object-lock and inventory) are not signed by the
+// OBS SDK. The requests are signed with the OBS signature and the errors are returned as obs.ObsError, so they can be
+// handled in the same way as the errors of the SDK.
+type obsRawClient struct {
+	accessKey     string
+	secretKey     string
+	securityToken string
+	endpoint      *url.URL
+	httpClient    *http.Client
+}
+
+func newObsRawClient(cfg *config.Config, region string) (*obsRawClient, error) {
+	if cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("missing credentials for OBS, need access_key and secret_key values for provider")
+	}
+
+	endpoint, err := url.Parse(cfg.ObjectStorageEndpoint(region))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OBS endpoint: %s", err)
+	}
+	return &obsRawClient{
+		accessKey:     cfg.AccessKey,
+		secretKey:     cfg.SecretKey,
+		securityToken: cfg.SecurityToken,
+		endpoint:      endpoint,
+		httpClient:    &cfg.DomainClient.HTTPClient,
+	}, nil
+}
+
+// buildRequestURL returns the URL of the bucket sub-resource and the canonicalized resource used for signing, the
+// other query parameters (e.g. the inventory ID) are not signed, which is the same as the SDK.
+func (c *obsRawClient) buildRequestURL(bucket, subResource string, params map[string]string) (string, string) {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	query := subResource
+	for _, k := range keys {
+		query += fmt.Sprintf("&%s=%s", k, url.QueryEscape(params[k]))
+	}
+
+	canonicalizedResource := fmt.Sprintf("/%s/?%s", bucket, subResource)
+	// the endpoint with an IP address can only be accessed in path style
+	if obs.IsIP(c.endpoint.Hostname()) {
+		return fmt.Sprintf("%s://%s/%s?%s", c.endpoint.Scheme, c.endpoint.Host, bucket, query), canonicalizedResource
+	}
+	return fmt.Sprintf("%s://%s.%s/?%s", c.endpoint.Scheme, bucket, c.endpoint.Host, query), canonicalizedResource
+}
+
+func (c *obsRawClient) sign(method, canonicalizedResource string, headers http.Header) {
+	obsHeaders := make([]string, 0)
+	for k, v := range headers {
+		if key := strings.ToLower(k); strings.HasPrefix(key, "x-obs-") {
+			obsHeaders = append(obsHeaders, fmt.Sprintf("%s:%s\n", key, strings.Join(v, ",")))
+		}
+	}
+	sort.Strings(obsHeaders)
+
+	stringToSign := strings.Join([]string{method, headers.Get("Content-MD5"), headers.Get("Content-Type"),
+		headers.Get("Date")}, "\n") + "\n" + strings.Join(obsHeaders, "") + canonicalizedResource
+	signature := obs.Base64Encode(obs.HmacSha1([]byte(c.secretKey), []byte(stringToSign)))
+	headers.Set("Authorization", fmt.Sprintf("OBS %s:%s", c.accessKey, signature))
+}
+
+// doRequest sends the request of the bucket sub-resource, the request body and the response body are in XML format.
+// The response body is not parsed when the result is nil.
+func (c *obsRawClient) doRequest(method, bucket, subResource string, params map[string]string,
+	input, result interface{}) error {
+	var body []byte
+	headers := http.Header{}
+	headers.Set("Date", obs.FormatUtcToRfc1123(time.Now().UTC()))
+	if c.securityToken != "" {
+		headers.Set("x-obs-security-token", c.securityToken)
+	}
+	if input != nil {
+		var err error
+		if body, err = xml.Marshal(input); err != nil {
+			return err
+		}
+		headers.Set("Content-Type", "application/xml")
+		headers.Set("Content-MD5", obs.Base64Md5(body))
+	}
+
+	requestURL, canonicalizedResource := c.buildRequestURL(bucket, subResource, params)
+	c.sign(method, canonicalizedResource, headers)
+
+	req, err := http.NewRequest(method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = headers
+	req.ContentLength = int64(len(body))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		obsError := obs.ObsError{}
+		obsError.StatusCode = resp.StatusCode
+		obsError.Status = resp.Status
+		obsError.RequestId = resp.Header.Get("x-obs-request-id")
+		if len(respBody) > 0 {
+			// the error body is ignored if it is not a valid XML, the status code is enough to handle the error
+			_ = xml.Unmarshal(respBody, &obsError)
+		}
+		return obsError
+	}
+
+	if result == nil || len(respBody) == 0 {
+		return nil
+	}
+	return xml.Unmarshal(respBody, result)
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"log"
+	"net/http"
 	"net/url"
 	"time"
 
@@ -49,6 +51,8 @@ import (
 // @API OBS GET ?storageinfo
 // @API OBS PUT ?storageClass
 // @API OBS GET ?storageClass
+// @API OBS PUT ?object-lock
+// @API OBS GET ?object-lock
 // @API OBS PUT ?encryption
 // @API OBS DELETE ?encryption
 // @API OBS GET ?encryption
@@ -344,6 +348,39 @@ func ResourceObsBucket() *schema.Resource {
 				Optional: true,
 				ForceNew: true,
 			},
+			"object_lock_enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"worm_policy": {
+				Type:         schema.TypeList,
+				Optional:     true,
+				MaxItems:     1,
+				RequiredWith: []string{"object_lock_enabled"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"mode": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      objectLockModeCompliance,
+							ValidateFunc: validation.StringInSlice([]string{objectLockModeCompliance}, false),
+						},
+						"days": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntBetween(1, 36500),
+							ExactlyOneOf: []string{"worm_policy.0.days", "worm_policy.0.years"},
+						},
+						"years": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntBetween(1, 100),
+						},
+					},
+				},
+			},
 			"encryption": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -411,7 +448,12 @@ func resourceObsBucketCreate(ctx context.Context, d *schema.ResourceData, meta i
 	}
 
 	log.Printf("[DEBUG] OBS bucket create opts: %#v", opts)
-	_, err = obsClient.CreateBucket(opts)
+	if d.Get("object_lock_enabled").(bool) {
+		// WORM can only be enabled when creating the bucket, and the versioning is enabled automatically
+		_, err = obsClient.CreateBucket(opts, obs.WithCustomHeader(objectLockEnabledHeader, "true"))
+	} else {
+		_, err = obsClient.CreateBucket(opts)
+	}
 	if err != nil {
 		return diag.FromErr(getObsError("Error creating bucket", bucket, err))
 	}
@@ -505,6 +547,12 @@ func resourceObsBucketUpdate(ctx context.Context, d *schema.ResourceData, meta i
 		}
 	}
 
+	if d.HasChange("worm_policy") {
+		if err := resourceObsBucketWormPolicyUpdate(conf, region, d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	if d.HasChange("enterprise_project_id") && !d.IsNewResource() {
 		// the API Limitations: still requires `project_id` field when migrating the EPS of OBS bucket
 		if err := resourceObsBucketEnterpriseProjectIdUpdate(ctx, d, conf, obsClient, region); err != nil {
@@ -586,6 +634,11 @@ func resourceObsBucketRead(_ context.Context, d *schema.ResourceData, meta inter
 		return diag.FromErr(err)
 	}
 
+	// Read the WORM configuration
+	if err := setObsBucketObjectLock(conf, region, d); err != nil {
+		return diag.FromErr(err)
+	}
+
 	// Read the logging configuration
 	if err := setObsBucketLogging(obsClientWithSignature, d); err != nil {
 		return diag.FromErr(err)
@@ -815,6 +868,53 @@ func resourceObsBucketEncryptionUpdate(config *config.Config, obsClient *obs.Obs
 	return nil
 }
 
+const (
+	objectLockEnabledHeader  = "x-obs-bucket-object-lock-enabled"
+	objectLockEnabled        = "Enabled"
+	objectLockModeCompliance = "COMPLIANCE"
+)
+
+// The object lock (WORM) configuration is not supported by the SDK, so it's sent with the raw client.
+type bucketObjectLockConfiguration struct {
+	XMLName           xml.Name                          `xml:"ObjectLockConfiguration"`
+	ObjectLockEnabled string                            `xml:"ObjectLockEnabled,omitempty"`
+	Rule              *bucketObjectLockDefaultRetention `xml:"Rule>DefaultRetention,omitempty"`
+}
+
+type bucketObjectLockDefaultRetention struct {
+	Mode  string `xml:"Mode"`
+	Days  int    `xml:"Days,omitempty"`
+	Years int    `xml:"Years,omitempty"`
+}
+
+func resourceObsBucketWormPolicyUpdate(conf *config.Config, region string, d *schema.ResourceData) error {
+	rawClient, err := newObsRawClient(conf, region)
+	if err != nil {
+		return err
+	}
+
+	bucket := d.Id()
+	configuration := bucketObjectLockConfiguration{
+		ObjectLockEnabled: objectLockEnabled,
+	}
+	// the default retention is removed by setting the configuration without a rule
+	if rawPolicies := d.Get("worm_policy").([]interface{}); len(rawPolicies) > 0 && rawPolicies[0] != nil {
+		policy := rawPolicies[0].(map[string]interface{})
+		configuration.Rule = &bucketObjectLockDefaultRetention{
+			Mode:  policy["mode"].(string),
+			Days:  policy["days"].(int),
+			Years: policy["years"].(int),
+		}
+	}
+
+	log.Printf("[DEBUG] set WORM configuration of OBS bucket %s: %#v", bucket, configuration)
+	err = rawClient.doRequest(http.MethodPut, bucket, "object-lock", nil, &configuration, nil)
+	if err != nil {
+		return getObsError("Error setting WORM configuration of OBS bucket", bucket, err)
+	}
+	return nil
+}
+
 func resourceObsBucketLoggingUpdate(obsClient *obs.ObsClient, d *schema.ResourceData) error {
 	bucket := d.Get("bucket").(string)
 	rawLogging := d.Get("logging").(*schema.Set).List()
@@ -1362,6 +1462,59 @@ func setObsBucketEncryption(obsClient *obs.ObsClient, d *schema.ResourceData) er
 	return nil
 }
 
+func setObsBucketObjectLock(conf *config.Config, region string, d *schema.ResourceData) error {
+	rawClient, err := newObsRawClient(conf, region)
+	if err != nil {
+		return err
+	}
+
+	bucket := d.Id()
+	var configuration bucketObjectLockConfiguration
+	err = rawClient.doRequest(http.MethodGet, bucket, "object-lock", nil, nil, &configuration)
+	if err != nil {
+		if !isObjectLockNotSupported(err) {
+			return getObsError("Error getting WORM configuration of OBS bucket", bucket, err)
+		}
+		// the WORM is not enabled for the bucket, or it's not supported by the bucket or the OBS endpoint
+		log.Printf("[DEBUG] the WORM configuration of OBS bucket %s is not available: %s", bucket, err)
+		configuration = bucketObjectLockConfiguration{}
+	}
+
+	log.Printf("[DEBUG] getting WORM configuration of OBS bucket %s: %#v", bucket, configuration)
+	policies := make([]map[string]interface{}, 0, 1)
+	if rule := configuration.Rule; rule != nil {
+		policies = append(policies, map[string]interface{}{
+			"mode":  rule.Mode,
+			"days":  rule.Days,
+			"years": rule.Years,
+		})
+	}
+
+	mErr := multierror.Append(nil,
+		d.Set("object_lock_enabled", configuration.ObjectLockEnabled == objectLockEnabled),
+		d.Set("worm_policy", policies),
+	)
+	if mErr.ErrorOrNil() != nil {
+		return fmt.Errorf("error saving WORM configuration of OBS bucket %s: %s", bucket, mErr)
+	}
+	return nil
+}
+
+// isObjectLockNotSupported checks whether the error means that the WORM configuration does not exist or is not
+// supported, e.g. the parallel file system and the OBS-compatible endpoints which do not implement the API.
+func isObjectLockNotSupported(err error) bool {
+	obsError, ok := err.(obs.ObsError)
+	if !ok {
+		return false
+	}
+
+	switch obsError.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	}
+	return utils.StrSliceContains([]string{"FsNotSupport", "MethodNotAllowed", "NotImplemented"}, obsError.Code)
+}
+
 func setObsBucketLogging(obsClient *obs.ObsClient, d *schema.ResourceData) error {
 	bucket := d.Id()
 	output, err := obsClient.GetBucketLoggingConfiguration(bucket)
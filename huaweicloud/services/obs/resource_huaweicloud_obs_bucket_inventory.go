@@ -0,0 +1,278 @@
+package obs
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/obs"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/common"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+var inventoryOptionalFields = []string{
+	"Size", "LastModifiedDate", "ETag", "StorageClass", "IsMultipartUploaded", "ReplicationStatus",
+	"EncryptionStatus",
+}
+
+// The inventory configurations are not supported by the SDK, so they are sent with the raw client.
+type bucketInventoryConfiguration struct {
+	XMLName                xml.Name                   `xml:"InventoryConfiguration"`
+	ID                     string                     `xml:"Id"`
+	IsEnabled              bool                       `xml:"IsEnabled"`
+	Filter                 *bucketInventoryFilter     `xml:"Filter,omitempty"`
+	Destination            bucketInventoryDestination `xml:"Destination"`
+	Frequency              string                     `xml:"Schedule>Frequency"`
+	IncludedObjectVersions string                     `xml:"IncludedObjectVersions"`
+	OptionalFields         []string                   `xml:"OptionalFields>Field,omitempty"`
+}
+
+type bucketInventoryFilter struct {
+	Prefix string `xml:"Prefix"`
+}
+
+type bucketInventoryDestination struct {
+	Format string `xml:"Format"`
+	Bucket string `xml:"Bucket"`
+	Prefix string `xml:"Prefix,omitempty"`
+}
+
+// @API OBS PUT ?inventory
+// @API OBS GET ?inventory
+// @API OBS DELETE ?inventory
+func ResourceObsBucketInventory() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceObsBucketInventoryCreate,
+		ReadContext:   resourceObsBucketInventoryRead,
+		UpdateContext: resourceObsBucketInventoryUpdate,
+		DeleteContext: resourceObsBucketInventoryDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceObsBucketInventoryImportState,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"bucket": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"configuration_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.All(
+					validation.StringLenBetween(1, 64),
+					validation.StringDoesNotContainAny("/"),
+				),
+			},
+			"destination_bucket": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"frequency": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"Daily", "Weekly"}, false),
+			},
+			"destination_prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"format": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "CSV",
+				ValidateFunc: validation.StringInSlice([]string{"CSV"}, false),
+			},
+			"included_object_versions": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "Current",
+				ValidateFunc: validation.StringInSlice([]string{"All", "Current"}, false),
+			},
+			"included_fields": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(inventoryOptionalFields, false),
+				},
+			},
+			"filter_prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+	}
+}
+
+func buildBucketInventoryConfiguration(d *schema.ResourceData) *bucketInventoryConfiguration {
+	configuration := bucketInventoryConfiguration{
+		ID:        d.Get("configuration_id").(string),
+		IsEnabled: d.Get("enabled").(bool),
+		Destination: bucketInventoryDestination{
+			Format: d.Get("format").(string),
+			Bucket: d.Get("destination_bucket").(string),
+			Prefix: d.Get("destination_prefix").(string),
+		},
+		Frequency:              d.Get("frequency").(string),
+		IncludedObjectVersions: d.Get("included_object_versions").(string),
+		OptionalFields:         utils.ExpandToStringListBySet(d.Get("included_fields").(*schema.Set)),
+	}
+	if v, ok := d.GetOk("filter_prefix"); ok {
+		configuration.Filter = &bucketInventoryFilter{Prefix: v.(string)}
+	}
+	return &configuration
+}
+
+func setBucketInventory(rawClient *obsRawClient, bucket string, configuration *bucketInventoryConfiguration) error {
+	params := map[string]string{"id": configuration.ID}
+	return rawClient.doRequest(http.MethodPut, bucket, "inventory", params, configuration, nil)
+}
+
+// GetBucketInventory returns the inventory configuration of the bucket, an obs.ObsError with the status code 404 is
+// returned if the configuration does not exist.
+func GetBucketInventory(cfg *config.Config, region, bucket, configurationId string) (interface{}, error) {
+	rawClient, err := newObsRawClient(cfg, region)
+	if err != nil {
+		return nil, err
+	}
+	return getBucketInventory(rawClient, bucket, configurationId)
+}
+
+func getBucketInventory(rawClient *obsRawClient, bucket, configurationId string) (*bucketInventoryConfiguration,
+	error) {
+	var configuration bucketInventoryConfiguration
+	params := map[string]string{"id": configurationId}
+	if err := rawClient.doRequest(http.MethodGet, bucket, "inventory", params, nil, &configuration); err != nil {
+		return nil, err
+	}
+	return &configuration, nil
+}
+
+func resourceObsBucketInventoryCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	rawClient, err := newObsRawClient(cfg, cfg.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("Error creating OBS client: %s", err)
+	}
+
+	bucket := d.Get("bucket").(string)
+	configuration := buildBucketInventoryConfiguration(d)
+	log.Printf("[DEBUG] create inventory configuration of OBS bucket %s: %#v", bucket, configuration)
+	if err := setBucketInventory(rawClient, bucket, configuration); err != nil {
+		return diag.FromErr(getObsError("Error creating inventory configuration of OBS bucket", bucket, err))
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", bucket, configuration.ID))
+	return resourceObsBucketInventoryRead(ctx, d, meta)
+}
+
+func resourceObsBucketInventoryRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	rawClient, err := newObsRawClient(cfg, region)
+	if err != nil {
+		return diag.Errorf("Error creating OBS client: %s", err)
+	}
+
+	bucket := d.Get("bucket").(string)
+	configuration, err := getBucketInventory(rawClient, bucket, d.Get("configuration_id").(string))
+	if err != nil {
+		if obsError, ok := err.(obs.ObsError); ok && obsError.StatusCode == 404 {
+			return common.CheckDeletedDiag(d, golangsdk.ErrDefault404{}, "OBS bucket inventory")
+		}
+		return diag.FromErr(getObsError("Error retrieving inventory configuration of OBS bucket", bucket, err))
+	}
+
+	filterPrefix := ""
+	if configuration.Filter != nil {
+		filterPrefix = configuration.Filter.Prefix
+	}
+	mErr := multierror.Append(nil,
+		d.Set("region", region),
+		d.Set("enabled", configuration.IsEnabled),
+		d.Set("destination_bucket", configuration.Destination.Bucket),
+		d.Set("destination_prefix", configuration.Destination.Prefix),
+		d.Set("format", configuration.Destination.Format),
+		d.Set("frequency", configuration.Frequency),
+		d.Set("included_object_versions", configuration.IncludedObjectVersions),
+		d.Set("included_fields", configuration.OptionalFields),
+		d.Set("filter_prefix", filterPrefix),
+	)
+	if err := mErr.ErrorOrNil(); err != nil {
+		return diag.Errorf("error setting OBS bucket inventory fields: %s", err)
+	}
+	return nil
+}
+
+func resourceObsBucketInventoryUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	rawClient, err := newObsRawClient(cfg, cfg.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("Error creating OBS client: %s", err)
+	}
+
+	// the inventory configuration with the same ID is overwritten
+	bucket := d.Get("bucket").(string)
+	if err := setBucketInventory(rawClient, bucket, buildBucketInventoryConfiguration(d)); err != nil {
+		return diag.FromErr(getObsError("Error updating inventory configuration of OBS bucket", bucket, err))
+	}
+	return resourceObsBucketInventoryRead(ctx, d, meta)
+}
+
+func resourceObsBucketInventoryDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	rawClient, err := newObsRawClient(cfg, cfg.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("Error creating OBS client: %s", err)
+	}
+
+	bucket := d.Get("bucket").(string)
+	params := map[string]string{"id": d.Get("configuration_id").(string)}
+	log.Printf("[DEBUG] delete inventory configuration %s of OBS bucket %s", params["id"], bucket)
+	if err := rawClient.doRequest(http.MethodDelete, bucket, "inventory", params, nil, nil); err != nil {
+		if obsError, ok := err.(obs.ObsError); ok && obsError.StatusCode == 404 {
+			return common.CheckDeletedDiag(d, golangsdk.ErrDefault404{}, "OBS bucket inventory")
+		}
+		return diag.FromErr(getObsError("Error deleting inventory configuration of OBS bucket", bucket, err))
+	}
+	return nil
+}
+
+func resourceObsBucketInventoryImportState(_ context.Context, d *schema.ResourceData,
+	_ interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid format specified for import ID, want '<bucket>/<configuration_id>', but got '%s'",
+			d.Id())
+	}
+
+	mErr := multierror.Append(nil,
+		d.Set("bucket", parts[0]),
+		d.Set("configuration_id", parts[1]),
+	)
+	return []*schema.ResourceData{d}, mErr.ErrorOrNil()
+}
@@ -0,0 +1,330 @@
+package obs
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/chnsz/golangsdk/openstack/obs"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/utils"
+)
+
+const (
+	notificationSignedUrlExpires = 300
+	notificationFilterPrefix     = "prefix"
+	notificationFilterSuffix     = "suffix"
+)
+
+var notificationEventTypes = []string{
+	"ObjectCreated:*", "ObjectCreated:Put", "ObjectCreated:Post", "ObjectCreated:Copy",
+	"ObjectCreated:CompleteMultipartUpload", "ObjectRemoved:*", "ObjectRemoved:Delete",
+	"ObjectRemoved:DeleteMarkerCreated",
+}
+
+// The notification configurations of the SDK do not support the FunctionGraph targets, so the configurations are
+// sent and parsed with the signed URLs.
+type bucketNotificationConfiguration struct {
+	XMLName                     xml.Name                          `xml:"NotificationConfiguration"`
+	TopicConfigurations         []bucketNotificationTopic         `xml:"TopicConfiguration"`
+	FunctionGraphConfigurations []bucketNotificationFunctionGraph `xml:"FunctionGraphConfiguration"`
+}
+
+type bucketNotificationFilter struct {
+	FilterRules []obs.FilterRule `xml:"Object>FilterRule"`
+}
+
+type bucketNotificationTopic struct {
+	ID     string                    `xml:"Id,omitempty"`
+	Filter *bucketNotificationFilter `xml:"Filter,omitempty"`
+	Topic  string                    `xml:"Topic"`
+	Events []string                  `xml:"Event"`
+}
+
+type bucketNotificationFunctionGraph struct {
+	ID            string                    `xml:"Id,omitempty"`
+	Filter        *bucketNotificationFilter `xml:"Filter,omitempty"`
+	FunctionGraph string                    `xml:"FunctionGraph"`
+	Events        []string                  `xml:"Event"`
+}
+
+func notificationTargetSchema(target string) *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeList,
+		Optional:     true,
+		AtLeastOneOf: []string{"topic_configurations", "function_graph_configurations"},
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				target: {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"events": {
+					Type:     schema.TypeList,
+					Required: true,
+					Elem: &schema.Schema{
+						Type:         schema.TypeString,
+						ValidateFunc: validation.StringInSlice(notificationEventTypes, false),
+					},
+				},
+				"id": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Computed: true,
+				},
+				"filter_prefix": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"filter_suffix": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+// @API OBS PUT ?notification
+// @API OBS GET ?notification
+func ResourceObsBucketNotification() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceObsBucketNotificationPut,
+		ReadContext:   resourceObsBucketNotificationRead,
+		UpdateContext: resourceObsBucketNotificationPut,
+		DeleteContext: resourceObsBucketNotificationDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"bucket": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"topic_configurations":          notificationTargetSchema("topic"),
+			"function_graph_configurations": notificationTargetSchema("function_graph"),
+		},
+	}
+}
+
+func buildNotificationFilter(rawMap map[string]interface{}) *bucketNotificationFilter {
+	rules := make([]obs.FilterRule, 0, 2)
+	if v := rawMap["filter_prefix"].(string); v != "" {
+		rules = append(rules, obs.FilterRule{Name: notificationFilterPrefix, Value: v})
+	}
+	if v := rawMap["filter_suffix"].(string); v != "" {
+		rules = append(rules, obs.FilterRule{Name: notificationFilterSuffix, Value: v})
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	return &bucketNotificationFilter{FilterRules: rules}
+}
+
+func buildBucketNotificationConfiguration(d *schema.ResourceData) *bucketNotificationConfiguration {
+	configuration := bucketNotificationConfiguration{}
+	for _, raw := range d.Get("topic_configurations").([]interface{}) {
+		rawMap := raw.(map[string]interface{})
+		configuration.TopicConfigurations = append(configuration.TopicConfigurations, bucketNotificationTopic{
+			ID:     rawMap["id"].(string),
+			Filter: buildNotificationFilter(rawMap),
+			Topic:  rawMap["topic"].(string),
+			Events: utils.ExpandToStringList(rawMap["events"].([]interface{})),
+		})
+	}
+	for _, raw := range d.Get("function_graph_configurations").([]interface{}) {
+		rawMap := raw.(map[string]interface{})
+		configuration.FunctionGraphConfigurations = append(configuration.FunctionGraphConfigurations,
+			bucketNotificationFunctionGraph{
+				ID:            rawMap["id"].(string),
+				Filter:        buildNotificationFilter(rawMap),
+				FunctionGraph: rawMap["function_graph"].(string),
+				Events:        utils.ExpandToStringList(rawMap["events"].([]interface{})),
+			})
+	}
+	return &configuration
+}
+
+func setBucketNotification(obsClient *obs.ObsClient, bucket string,
+	configuration *bucketNotificationConfiguration) error {
+	body, err := xml.Marshal(configuration)
+	if err != nil {
+		return err
+	}
+
+	signed, err := obsClient.CreateSignedUrl(&obs.CreateSignedUrlInput{
+		Method:      obs.HttpMethodPut,
+		Bucket:      bucket,
+		SubResource: obs.SubResourceNotification,
+		Expires:     notificationSignedUrlExpires,
+		Headers:     map[string]string{"Content-Type": "application/xml"},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = obsClient.SetBucketNotificationWithSignedUrl(signed.SignedUrl, signed.ActualSignedRequestHeaders,
+		bytes.NewReader(body))
+	return err
+}
+
+func getBucketNotification(obsClient *obs.ObsClient, bucket string) (*bucketNotificationConfiguration, error) {
+	signed, err := obsClient.CreateSignedUrl(&obs.CreateSignedUrlInput{
+		Method:      obs.HttpMethodGet,
+		Bucket:      bucket,
+		SubResource: obs.SubResourceNotification,
+		Expires:     notificationSignedUrlExpires,
+	})
+	if err != nil {
+		return nil, err
+	}
+	output, err := obsClient.GetObjectWithSignedUrl(signed.SignedUrl, signed.ActualSignedRequestHeaders)
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+
+	body, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, err
+	}
+	var configuration bucketNotificationConfiguration
+	if err := xml.Unmarshal(body, &configuration); err != nil {
+		return nil, fmt.Errorf("error parsing the notification configuration: %s", err)
+	}
+	return &configuration, nil
+}
+
+func resourceObsBucketNotificationPut(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	obsClient, err := cfg.ObjectStorageClientWithSignature(cfg.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("Error creating OBS client with signature: %s", err)
+	}
+
+	bucket := d.Get("bucket").(string)
+	log.Printf("[DEBUG] set notification configuration of OBS bucket %s", bucket)
+	if err := setBucketNotification(obsClient, bucket, buildBucketNotificationConfiguration(d)); err != nil {
+		return diag.FromErr(getObsError("Error setting notification configuration of OBS bucket", bucket, err))
+	}
+
+	d.SetId(bucket)
+	return resourceObsBucketNotificationRead(ctx, d, meta)
+}
+
+func flattenNotificationFilter(result map[string]interface{}, filter *bucketNotificationFilter) {
+	if filter == nil {
+		return
+	}
+	for _, rule := range filter.FilterRules {
+		switch rule.Name {
+		case notificationFilterPrefix:
+			result["filter_prefix"] = rule.Value
+		case notificationFilterSuffix:
+			result["filter_suffix"] = rule.Value
+		}
+	}
+}
+
+func flattenNotificationTopics(topics []bucketNotificationTopic) []map[string]interface{} {
+	if len(topics) == 0 {
+		return nil
+	}
+
+	result := make([]map[string]interface{}, len(topics))
+	for i, topic := range topics {
+		result[i] = map[string]interface{}{
+			"id":     topic.ID,
+			"topic":  topic.Topic,
+			"events": topic.Events,
+		}
+		flattenNotificationFilter(result[i], topic.Filter)
+	}
+	return result
+}
+
+func flattenNotificationFunctionGraphs(functions []bucketNotificationFunctionGraph) []map[string]interface{} {
+	if len(functions) == 0 {
+		return nil
+	}
+
+	result := make([]map[string]interface{}, len(functions))
+	for i, function := range functions {
+		result[i] = map[string]interface{}{
+			"id":             function.ID,
+			"function_graph": function.FunctionGraph,
+			"events":         function.Events,
+		}
+		flattenNotificationFilter(result[i], function.Filter)
+	}
+	return result
+}
+
+func resourceObsBucketNotificationRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	region := cfg.GetRegion(d)
+	obsClient, err := cfg.ObjectStorageClientWithSignature(region)
+	if err != nil {
+		return diag.Errorf("Error creating OBS client with signature: %s", err)
+	}
+
+	bucket := d.Id()
+	configuration, err := getBucketNotification(obsClient, bucket)
+	if err != nil {
+		if obsError, ok := err.(obs.ObsError); ok && obsError.StatusCode == 404 {
+			log.Printf("[WARN] OBS bucket(%s) not found", bucket)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(getObsError("Error retrieving notification configuration of OBS bucket", bucket, err))
+	}
+	if len(configuration.TopicConfigurations) == 0 && len(configuration.FunctionGraphConfigurations) == 0 {
+		log.Printf("[WARN] the notification configuration of OBS bucket(%s) not found", bucket)
+		d.SetId("")
+		return nil
+	}
+
+	mErr := multierror.Append(nil,
+		d.Set("region", region),
+		d.Set("bucket", bucket),
+		d.Set("topic_configurations", flattenNotificationTopics(configuration.TopicConfigurations)),
+		d.Set("function_graph_configurations",
+			flattenNotificationFunctionGraphs(configuration.FunctionGraphConfigurations)),
+	)
+	if err := mErr.ErrorOrNil(); err != nil {
+		return diag.Errorf("error setting OBS bucket notification fields: %s", err)
+	}
+	return nil
+}
+
+func resourceObsBucketNotificationDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	cfg := meta.(*config.Config)
+	obsClient, err := cfg.ObjectStorageClientWithSignature(cfg.GetRegion(d))
+	if err != nil {
+		return diag.Errorf("Error creating OBS client with signature: %s", err)
+	}
+
+	bucket := d.Id()
+	log.Printf("[DEBUG] delete notification configuration of OBS bucket %s", bucket)
+	// the notification configuration is removed by setting an empty configuration
+	if err := setBucketNotification(obsClient, bucket, &bucketNotificationConfiguration{}); err != nil {
+		return diag.FromErr(getObsError("Error deleting notification configuration of OBS bucket", bucket, err))
+	}
+	return nil
+}
@@ -6,21 +6,42 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/chnsz/golangsdk/openstack/obs"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 )
 
+const (
+	// the size unit of the multipart upload arguments is MB
+	multipartSizeUnit = 1024 * 1024
+
+	defaultMultipartThreshold = 100
+	defaultPartSize           = 10
+	defaultConcurrency        = 4
+)
+
+// the arguments which change the object content, the other ones only take effect in the next upload
+var objectContentArguments = []string{
+	"source", "content", "copy_source", "source_hash", "storage_class", "acl", "encryption", "kms_key_id",
+	"content_type", "etag",
+}
+
 // @API OBS HEAD /
 // @API OBS HEAD /{ObjectName}
 // @API OBS PUT /{ObjectName}
 // @API OBS DELETE /{ObjectName}
+// @API OBS POST /{ObjectName}?uploads
+// @API OBS PUT /{ObjectName}?partNumber={partNumber}&uploadId={uploadId}
+// @API OBS POST /{ObjectName}?uploadId={uploadId}
+// @API OBS DELETE /{ObjectName}?uploadId={uploadId}
 func ResourceObsBucketObject() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceObsBucketObjectPut,
@@ -58,7 +79,44 @@ func ResourceObsBucketObject() *schema.Resource {
 			"content": {
 				Type:         schema.TypeString,
 				Optional:     true,
-				ExactlyOneOf: []string{"source", "content"},
+				ExactlyOneOf: []string{"source", "content", "copy_source"},
+			},
+
+			"copy_source": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[^/]+/.+$`), "the format must be <bucket>/<key>"),
+			},
+
+			"source_hash": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"multipart_threshold": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultMultipartThreshold,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+
+			"part_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultPartSize,
+				ValidateFunc: validation.IntBetween(1, 5120),
+			},
+
+			"concurrency": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultConcurrency,
+				ValidateFunc: validation.IntBetween(1, 100),
+			},
+
+			"checkpoint_file": {
+				Type:     schema.TypeString,
+				Optional: true,
 			},
 
 			"storage_class": {
@@ -90,8 +148,8 @@ func ResourceObsBucketObject() *schema.Resource {
 
 			"etag": {
 				Type: schema.TypeString,
-				// This will conflict with server-side-encryption and multi-part upload
-				// if/when it's actually implemented. The Etag then won't match raw-file MD5.
+				// This will conflict with server-side-encryption and multipart upload,
+				// the Etag then won't match raw-file MD5, use source_hash instead.
 				Optional: true,
 				Computed: true,
 			},
@@ -110,9 +168,14 @@ func ResourceObsBucketObject() *schema.Resource {
 }
 
 func resourceObsBucketObjectPut(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	var resp *obs.PutObjectOutput
+	var versionId string
 	var err error
 
+	// changing the upload arguments (e.g. part_size) does not upload the object again
+	if !d.IsNewResource() && !d.HasChanges(objectContentArguments...) {
+		return resourceObsBucketObjectRead(ctx, d, meta)
+	}
+
 	conf := meta.(*config.Config)
 	obsClient, err := conf.ObjectStorageClient(conf.GetRegion(d))
 	if err != nil {
@@ -131,9 +194,10 @@ func resourceObsBucketObjectPut(ctx context.Context, d *schema.ResourceData, met
 
 	source := d.Get("source").(string)
 	content := d.Get("content").(string)
+	copySource := d.Get("copy_source").(string)
 	if source != "" {
 		// check source file whether exist
-		_, err = os.Stat(source)
+		fileInfo, err := os.Stat(source)
 		if err != nil {
 			if os.IsNotExist(err) {
 				return diag.Errorf("source file %s is not exist", source)
@@ -141,26 +205,32 @@ func resourceObsBucketObjectPut(ctx context.Context, d *schema.ResourceData, met
 			return diag.FromErr(err)
 		}
 
-		// put source file
-		resp, err = putFileToObject(obsClient, d)
+		if fileInfo.Size() > int64(d.Get("multipart_threshold").(int))*multipartSizeUnit {
+			// upload the large file in parts
+			versionId, err = uploadFileToObject(obsClient, d)
+		} else {
+			// put source file
+			versionId, err = putFileToObject(obsClient, d)
+		}
 	}
 
 	if content != "" {
 		// put content
-		resp, err = putContentToObject(obsClient, d)
+		versionId, err = putContentToObject(obsClient, d)
+	}
+
+	if copySource != "" {
+		// copy the object in the server side
+		versionId, err = copyObjectFromSource(obsClient, d)
 	}
 
 	if err != nil {
 		return diag.FromErr(getObsError("Error putting object to OBS bucket", bucket, err))
 	}
-	if resp == nil {
-		return diag.Errorf("putting object to OBS bucket %s without null response", bucket)
-	}
 
-	log.Printf("[DEBUG] Response of putting %s to OBS Bucket %s: %#v", key, bucket, resp)
 	mErr := &multierror.Error{}
-	if resp.VersionId != "null" {
-		mErr = multierror.Append(mErr, d.Set("version_id", resp.VersionId))
+	if versionId != "null" {
+		mErr = multierror.Append(mErr, d.Set("version_id", versionId))
 	} else {
 		mErr = multierror.Append(mErr, d.Set("version_id", ""))
 	}
@@ -172,7 +242,7 @@ func resourceObsBucketObjectPut(ctx context.Context, d *schema.ResourceData, met
 	return resourceObsBucketObjectRead(ctx, d, meta)
 }
 
-func putContentToObject(obsClient *obs.ObsClient, d *schema.ResourceData) (*obs.PutObjectOutput, error) {
+func putContentToObject(obsClient *obs.ObsClient, d *schema.ResourceData) (string, error) {
 	bucket := d.Get("bucket").(string)
 	key := d.Get("key").(string)
 	content := d.Get("content").(string)
@@ -203,10 +273,15 @@ func putContentToObject(obsClient *obs.ObsClient, d *schema.ResourceData) (*obs.
 	body := bytes.NewReader([]byte(content))
 	putInput.Body = body
 
-	return obsClient.PutObject(putInput)
+	resp, err := obsClient.PutObject(putInput)
+	if err != nil {
+		return "", err
+	}
+	log.Printf("[DEBUG] Response of putting %s to OBS Bucket %s: %#v", key, bucket, resp)
+	return resp.VersionId, nil
 }
 
-func putFileToObject(obsClient *obs.ObsClient, d *schema.ResourceData) (*obs.PutObjectOutput, error) {
+func putFileToObject(obsClient *obs.ObsClient, d *schema.ResourceData) (string, error) {
 	bucket := d.Get("bucket").(string)
 	key := d.Get("key").(string)
 
@@ -233,7 +308,96 @@ func putFileToObject(obsClient *obs.ObsClient, d *schema.ResourceData) (*obs.Put
 	}
 
 	log.Printf("[DEBUG] putting %s to OBS Bucket %s, opts: %#v", key, bucket, putInput)
-	return obsClient.PutFile(putInput)
+	resp, err := obsClient.PutFile(putInput)
+	if err != nil {
+		return "", err
+	}
+	log.Printf("[DEBUG] Response of putting %s to OBS Bucket %s: %#v", key, bucket, resp)
+	return resp.VersionId, nil
+}
+
+// uploadFileToObject uploads the source file in parts concurrently, the uploaded parts are recorded in the checkpoint
+// file if it's specified, so the upload can be resumed from the recorded parts after it's interrupted.
+func uploadFileToObject(obsClient *obs.ObsClient, d *schema.ResourceData) (string, error) {
+	bucket := d.Get("bucket").(string)
+	key := d.Get("key").(string)
+
+	uploadInput := &obs.UploadFileInput{}
+	uploadInput.Bucket = bucket
+	uploadInput.Key = key
+	uploadInput.UploadFile = d.Get("source").(string)
+	uploadInput.PartSize = int64(d.Get("part_size").(int)) * multipartSizeUnit
+	uploadInput.TaskNum = d.Get("concurrency").(int)
+	if v, ok := d.GetOk("checkpoint_file"); ok {
+		uploadInput.EnableCheckpoint = true
+		uploadInput.CheckpointFile = v.(string)
+	}
+
+	if v, ok := d.GetOk("acl"); ok {
+		uploadInput.ACL = obs.AclType(v.(string))
+	}
+	if v, ok := d.GetOk("storage_class"); ok {
+		uploadInput.StorageClass = obs.StorageClassType(v.(string))
+	}
+	if v, ok := d.GetOk("content_type"); ok {
+		uploadInput.ContentType = v.(string)
+	}
+
+	var sseKmsHeader = obs.SseKmsHeader{}
+	if d.Get("encryption").(bool) {
+		sseKmsHeader.Encryption = obs.DEFAULT_SSE_KMS_ENCRYPTION
+		sseKmsHeader.Key = d.Get("kms_key_id").(string)
+		uploadInput.SseHeader = sseKmsHeader
+	}
+
+	log.Printf("[DEBUG] uploading %s to OBS Bucket %s in parts, opts: %#v", key, bucket, uploadInput)
+	resp, err := obsClient.UploadFile(uploadInput)
+	if err != nil {
+		return "", err
+	}
+	log.Printf("[DEBUG] Response of uploading %s to OBS Bucket %s: %#v", key, bucket, resp)
+	return resp.VersionId, nil
+}
+
+func copyObjectFromSource(obsClient *obs.ObsClient, d *schema.ResourceData) (string, error) {
+	bucket := d.Get("bucket").(string)
+	key := d.Get("key").(string)
+	// the format of copy_source has been validated
+	source := strings.SplitN(d.Get("copy_source").(string), "/", 2)
+
+	copyInput := &obs.CopyObjectInput{}
+	copyInput.Bucket = bucket
+	copyInput.Key = key
+	copyInput.CopySourceBucket = source[0]
+	copyInput.CopySourceKey = source[1]
+
+	if v, ok := d.GetOk("acl"); ok {
+		copyInput.ACL = obs.AclType(v.(string))
+	}
+	if v, ok := d.GetOk("storage_class"); ok {
+		copyInput.StorageClass = obs.StorageClassType(v.(string))
+	}
+	// the metadata of the source object is kept unless the content type is specified in the configuration, the
+	// content type in the state may be computed from the previous source object
+	if rawConfig := d.GetRawConfig(); !rawConfig.IsNull() && !rawConfig.GetAttr("content_type").IsNull() {
+		copyInput.MetadataDirective = obs.ReplaceMetadata
+		copyInput.ContentType = d.Get("content_type").(string)
+	}
+
+	var sseKmsHeader = obs.SseKmsHeader{}
+	if d.Get("encryption").(bool) {
+		sseKmsHeader.Encryption = obs.DEFAULT_SSE_KMS_ENCRYPTION
+		sseKmsHeader.Key = d.Get("kms_key_id").(string)
+		copyInput.SseHeader = sseKmsHeader
+	}
+
+	log.Printf("[DEBUG] copying %s to OBS Bucket %s, opts: %#v", key, bucket, copyInput)
+	resp, err := obsClient.CopyObject(copyInput)
+	if err != nil {
+		return "", err
+	}
+	log.Printf("[DEBUG] Response of copying %s to OBS Bucket %s: %#v", key, bucket, resp)
+	return resp.VersionId, nil
 }
 
 func resourceObsBucketObjectRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -321,9 +485,13 @@ func resourceObsBucketObjectImport(_ context.Context, d *schema.ResourceData, _
 	bucket := parts[0]
 	key := parts[1]
 
+	// the upload arguments are not stored in the object, set them to the defaults
 	mErr := multierror.Append(nil,
 		d.Set("bucket", bucket),
 		d.Set("key", key),
+		d.Set("multipart_threshold", defaultMultipartThreshold),
+		d.Set("part_size", defaultPartSize),
+		d.Set("concurrency", defaultConcurrency),
 	)
 	if mErr.ErrorOrNil() != nil {
 		return nil, fmt.Errorf("error setting attributes of OBS bucket %s: %s", bucket, mErr)
@@ -411,13 +411,15 @@ func updateRAMShareTags(client *golangsdk.ServiceClient, d *schema.ResourceData)
 	oMap := oRaw.(map[string]interface{})
 	nMap := nRaw.(map[string]interface{})
 
-	// remove old tags
-	if len(oMap) > 0 {
+	removedTags, upsertedTags := utils.DiffTags(oMap, nMap)
+
+	// remove the tags which are no longer present
+	if len(removedTags) > 0 {
 		deleteTagsPath := fmt.Sprintf("%s/%s", ramShareTagsPath, "delete")
 		deleteTagsOpt := golangsdk.RequestOpts{
 			KeepResponseBody: true,
 			JSONBody: map[string]interface{}{
-				"tags": utils.ExpandResourceTagsMap(oMap),
+				"tags": utils.ExpandResourceTagsMap(removedTags),
 			},
 		}
 		_, err := client.Request("POST", deleteTagsPath, &deleteTagsOpt)
@@ -426,13 +428,13 @@ func updateRAMShareTags(client *golangsdk.ServiceClient, d *schema.ResourceData)
 		}
 	}
 
-	// set new tags
-	if len(nMap) > 0 {
+	// create the new tags and update the tags whose value is changed
+	if len(upsertedTags) > 0 {
 		createTagsPath := fmt.Sprintf("%s/%s", ramShareTagsPath, "create")
 		createTagsOpt := golangsdk.RequestOpts{
 			KeepResponseBody: true,
 			JSONBody: map[string]interface{}{
-				"tags": utils.ExpandResourceTagsMap(nMap),
+				"tags": utils.ExpandResourceTagsMap(upsertedTags),
 			},
 		}
 		_, err := client.Request("POST", createTagsPath, &createTagsOpt)
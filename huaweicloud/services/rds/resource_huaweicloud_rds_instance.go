@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -132,9 +133,22 @@ func ResourceRdsInstance() *schema.Resource {
 							ForceNew: true,
 						},
 						"password": {
-							Type:      schema.TypeString,
-							Sensitive: true,
-							Optional:  true,
+							Type:          schema.TypeString,
+							Sensitive:     true,
+							Optional:      true,
+							ConflictsWith: []string{"db.0.password_wo"},
+						},
+						"password_wo": {
+							Type:          schema.TypeString,
+							Sensitive:     true,
+							Optional:      true,
+							WriteOnly:     true,
+							ConflictsWith: []string{"db.0.password"},
+						},
+						"password_wo_version": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							RequiredWith: []string{"db.0.password_wo"},
 						},
 						"port": {
 							Type:     schema.TypeInt,
@@ -589,7 +603,7 @@ func resourceRdsInstanceCreate(ctx context.Context, d *schema.ResourceData, meta
 
 	log.Printf("[DEBUG] Create Options: %#v", createOpts)
 	// Add password here so it wouldn't go in the above log entry
-	createOpts.Password = d.Get("db.0.password").(string)
+	createOpts.Password = getRdsRootPassword(d)
 
 	res, err := instances.Create(client, createOpts).Extract()
 	if err != nil {
@@ -676,7 +690,7 @@ func resourceRdsInstanceCreate(ctx context.Context, d *schema.ResourceData, meta
 		}
 	}
 
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	if len(tagRaw) > 0 {
 		taglist := utils.ExpandResourceTags(tagRaw)
 		if tagErr := tags.Create(client, "instances", instanceID, taglist).ExtractErr(); tagErr != nil {
@@ -806,6 +820,7 @@ func resourceRdsInstanceRead(ctx context.Context, d *schema.ResourceData, meta i
 	}
 	if len(d.Get("db").([]interface{})) > 0 {
 		database["password"] = d.Get("db.0.password")
+		database["password_wo_version"] = d.Get("db.0.password_wo_version")
 	}
 	dbList[0] = database
 	if err := d.Set("db", dbList); err != nil {
@@ -1030,7 +1045,7 @@ func resourceRdsInstanceUpdate(ctx context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		tagErr := utils.UpdateResourceTags(client, d, "instances", instanceID)
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of RDS instance (%s): %s", instanceID, tagErr)
@@ -2333,14 +2348,24 @@ func rdsInstanceStateRefreshFunc(client *golangsdk.ServiceClient, instanceID str
 	}
 }
 
+// getRdsRootPassword returns the root password, which is specified by either db.0.password or the write-only
+// db.0.password_wo.
+func getRdsRootPassword(d *schema.ResourceData) string {
+	if v, ok := d.GetOk("db.0.password"); ok {
+		return v.(string)
+	}
+	return common.GetWriteOnlyString(d, cty.GetAttrPath("db").IndexInt(0).GetAttr("password_wo"))
+}
+
 func updateRdsRootPassword(ctx context.Context, d *schema.ResourceData, client *golangsdk.ServiceClient,
 	instanceID string) error {
-	if !d.HasChange("db.0.password") {
+	// the write-only password is changed only when its version is changed
+	if !d.HasChanges("db.0.password", "db.0.password_wo_version") {
 		return nil
 	}
 
 	updateOpts := instances.RestRootPasswordOpts{
-		DbUserPwd: d.Get("db.0.password").(string),
+		DbUserPwd: getRdsRootPassword(d),
 	}
 
 	retryFunc := func() (interface{}, bool, error) {
@@ -197,7 +197,7 @@ func resourceProtectedInstanceUpdate(ctx context.Context, d *schema.ResourceData
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		if err := utils.UpdateResourceTags(client, d, "protected-instances", d.Id()); err != nil {
 			return diag.Errorf("error updating tags of SDRS protected instance %s: %s", d.Id(), err)
 		}
@@ -219,7 +219,7 @@ func resourceSFSFileSystemV2Create(ctx context.Context, d *schema.ResourceData,
 	}
 
 	// create tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	if len(tagRaw) > 0 {
 		taglist := utils.ExpandResourceTags(tagRaw)
 		if tagErr := tags.Create(sfsClient, "sfs", d.Id(), taglist).ExtractErr(); tagErr != nil {
@@ -408,7 +408,7 @@ func resourceSFSFileSystemV2Update(ctx context.Context, d *schema.ResourceData,
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		tagErr := utils.UpdateResourceTags(sfsClient, d, "sfs", d.Id())
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of sfs:%s, err:%s", d.Id(), tagErr)
@@ -17,6 +17,7 @@ import (
 	"github.com/jmespath/go-jmespath"
 
 	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/common/tags"
 	"github.com/chnsz/golangsdk/openstack/sfs_turbo/v1/shares"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/common"
@@ -525,7 +526,7 @@ func resourceSFSTurboUpdate(ctx context.Context, d *schema.ResourceData, meta in
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		if err := updateSFSTurboTags(sfsClient, d); err != nil {
 			return diag.Errorf("error updating tags of SFS Turbo %s: %s", resourceId, err)
 		}
@@ -577,23 +578,26 @@ func resourceSFSTurboUpdate(ctx context.Context, d *schema.ResourceData, meta in
 }
 
 func updateSFSTurboTags(client *golangsdk.ServiceClient, d *schema.ResourceData) error {
-	// remove old tags
-	oldKeys := getOldTagKeys(d)
-	if err := utils.DeleteResourceTagsWithKeys(client, oldKeys, "sfs-turbo", d.Id()); err != nil {
+	oMap, nMap := utils.GetAllResourceTagsChange(d)
+	removedTags, upsertedTags := utils.DiffTags(oMap, nMap)
+
+	// remove the tags which are no longer present
+	if err := utils.DeleteResourceTagsWithKeys(client, getTagKeys(removedTags), "sfs-turbo", d.Id()); err != nil {
 		return err
 	}
 
-	// set new tags
-	return utils.CreateResourceTags(client, d, "sfs-turbo", d.Id())
+	// create the new tags and update the tags whose value is changed
+	if len(upsertedTags) > 0 {
+		taglist := utils.ExpandResourceTags(upsertedTags)
+		return tags.Create(client, "sfs-turbo", d.Id(), taglist).ExtractErr()
+	}
+	return nil
 }
 
-func getOldTagKeys(d *schema.ResourceData) []string {
-	oRaw, _ := d.GetChange("tags")
-	var tagKeys []string
-	if oMap := oRaw.(map[string]interface{}); len(oMap) > 0 {
-		for k := range oMap {
-			tagKeys = append(tagKeys, k)
-		}
+func getTagKeys(tagmap map[string]interface{}) []string {
+	tagKeys := make([]string, 0, len(tagmap))
+	for k := range tagmap {
+		tagKeys = append(tagKeys, k)
 	}
 	return tagKeys
 }
@@ -133,7 +133,7 @@ func resourceTopicCreate(ctx context.Context, d *schema.ResourceData, meta inter
 	}
 
 	// set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	if len(tagRaw) > 0 {
 		taglist := utils.ExpandResourceTags(tagRaw)
 		tagClient, err := cfg.SmnV2TagClient(region)
@@ -270,7 +270,7 @@ func resourceTopicUpdate(ctx context.Context, d *schema.ResourceData, meta inter
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		tagClient, err := cfg.SmnV2TagClient(region)
 		if err != nil {
 			return diag.Errorf("error creating SMN tag client: %s", err)
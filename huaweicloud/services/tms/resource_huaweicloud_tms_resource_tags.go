@@ -2,6 +2,7 @@ package tms
 
 import (
 	"context"
+	"fmt"
 	"log"
 
 	"github.com/hashicorp/go-multierror"
@@ -225,39 +226,108 @@ func resourceResourceTagsUpdate(ctx context.Context, d *schema.ResourceData, met
 		return diag.Errorf("error creating TMS v1 client: %s", err)
 	}
 
+	if err := updateResourceTags(client, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceResourceTagsRead(ctx, d, meta)
+}
+
+// updateResourceTags only removes the tags which are no longer present and creates or updates the changed tags for the
+// resources which are still managed, so that the unchanged tags will never be touched.
+func updateResourceTags(client *golangsdk.ServiceClient, d *schema.ResourceData) error {
 	var (
 		projectId        = d.Get("project_id").(string)
 		oldRes, newRes   = d.GetChange("resources")
 		oldTags, newTags = d.GetChange("tags")
+		oldTagsMap       = oldTags.(map[string]interface{})
+		newTagsMap       = newTags.(map[string]interface{})
 	)
+	removedRes, keptRes, addedRes := diffResources(buildResourcesInfo(oldRes.([]interface{})),
+		buildResourcesInfo(newRes.([]interface{})))
+	removedTags, upsertedTags := utils.DiffTags(oldTagsMap, newTagsMap)
+
+	// the resources which are no longer managed lose all the old tags, and the managed resources only lose the
+	// tags which are no longer present
+	if err := deleteResourceTags(client, projectId, removedRes, oldTagsMap); err != nil {
+		return err
+	}
+	if err := deleteResourceTags(client, projectId, keptRes, removedTags); err != nil {
+		return err
+	}
+
+	// the managed resources only get the new tags and the tags whose value is changed, and the new resources get
+	// all the tags
+	if err := createResourceTags(client, projectId, keptRes, upsertedTags); err != nil {
+		return err
+	}
+	return createResourceTags(client, projectId, addedRes, newTagsMap)
+}
+
+// diffResources returns the resources which are only present in the old resources, the resources which are present
+// in both, and the resources which are only present in the new resources.
+func diffResources(oldResources, newResources []tags.Resource) (removed, kept, added []tags.Resource) {
+	newSet := make(map[tags.Resource]bool, len(newResources))
+	for _, r := range newResources {
+		newSet[r] = true
+	}
+	oldSet := make(map[tags.Resource]bool, len(oldResources))
+	for _, r := range oldResources {
+		oldSet[r] = true
+		if newSet[r] {
+			kept = append(kept, r)
+		} else {
+			removed = append(removed, r)
+		}
+	}
+	for _, r := range newResources {
+		if !oldSet[r] {
+			added = append(added, r)
+		}
+	}
+	return
+}
+
+func deleteResourceTags(client *golangsdk.ServiceClient, projectId string, resources []tags.Resource,
+	tagMap map[string]interface{}) error {
+	if len(resources) == 0 || len(tagMap) == 0 {
+		return nil
+	}
 
 	deleteOpts := tags.BatchOpts{
 		ProjectId: projectId,
-		Resources: buildResourcesInfo(oldRes.([]interface{})),
-		Tags:      expandResourceTags(oldTags.(map[string]interface{})),
+		Resources: resources,
+		Tags:      expandResourceTags(tagMap),
 	}
 	failResp, err := tags.Delete(client, deleteOpts)
 	if err != nil {
-		return diag.Errorf("error deleting resource tags: %s", err)
+		return fmt.Errorf("error deleting resource tags: %s", err)
 	}
 	if len(failResp) > 0 {
-		return diag.Errorf("some tags were not successfully removed: %#v", failResp)
+		return fmt.Errorf("some tags were not successfully removed: %#v", failResp)
+	}
+	return nil
+}
+
+func createResourceTags(client *golangsdk.ServiceClient, projectId string, resources []tags.Resource,
+	tagMap map[string]interface{}) error {
+	if len(resources) == 0 || len(tagMap) == 0 {
+		return nil
 	}
 
 	opts := tags.BatchOpts{
 		ProjectId: projectId,
-		Resources: buildResourcesInfo(newRes.([]interface{})),
-		Tags:      expandResourceTags(newTags.(map[string]interface{})),
+		Resources: resources,
+		Tags:      expandResourceTags(tagMap),
 	}
-	failResp, err = tags.Create(client, opts)
+	failResp, err := tags.Create(client, opts)
 	if err != nil {
-		return diag.Errorf("error creating resource tags: %s", err)
+		return fmt.Errorf("error creating resource tags: %s", err)
 	}
 	if len(failResp) > 0 {
-		return diag.Errorf("some tags were not set successfully: %#v", failResp)
+		return fmt.Errorf("some tags were not set successfully: %#v", failResp)
 	}
-
-	return resourceResourceTagsRead(ctx, d, meta)
+	return nil
 }
 
 func resourceResourceTagsDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
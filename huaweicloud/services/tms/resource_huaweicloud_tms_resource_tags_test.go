@@ -0,0 +1,116 @@
+package tms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/chnsz/golangsdk"
+	tags "github.com/chnsz/golangsdk/openstack/tms/v1/resourcetags"
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+func TestUpdateResourceTags(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	// the requests received by the fake batch endpoints in the order of arrival, each one is formatted as
+	// "<action> <resource IDs> <tags>"
+	var requests []string
+	handler := func(action string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			th.TestMethod(t, r, "POST")
+
+			body, err := io.ReadAll(r.Body)
+			th.AssertNoErr(t, err)
+
+			var opts tags.BatchOpts
+			th.AssertNoErr(t, json.Unmarshal(body, &opts))
+			resourceIds := make([]string, 0, len(opts.Resources))
+			for _, resource := range opts.Resources {
+				resourceIds = append(resourceIds, resource.ResourceId)
+			}
+			tagList := make([]string, 0, len(opts.Tags))
+			for _, tag := range opts.Tags {
+				tagList = append(tagList, fmt.Sprintf("%s=%s", tag.Key, *tag.Value))
+			}
+			sort.Strings(resourceIds)
+			sort.Strings(tagList)
+			requests = append(requests, fmt.Sprintf("%s %v %v", action, resourceIds, tagList))
+
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"failed_resources": []}`)
+		}
+	}
+	th.Mux.HandleFunc("/v1.0/resource-tags/batch-delete", handler("delete"))
+	th.Mux.HandleFunc("/v1.0/resource-tags/batch-create", handler("create"))
+
+	client := &golangsdk.ServiceClient{
+		ProviderClient: &golangsdk.ProviderClient{},
+		Endpoint:       th.Endpoint(),
+		ResourceBase:   th.Endpoint() + "v1.0/",
+	}
+	r := ResourceResourceTags()
+	r.UpdateContext = func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+		return diag.FromErr(updateResourceTags(client, d))
+	}
+
+	state := &terraform.InstanceState{
+		ID: "test-id",
+		Attributes: map[string]string{
+			"id":                        "test-id",
+			"project_id":                "project-id",
+			"resources.#":               "2",
+			"resources.0.resource_type": "vpcs",
+			"resources.0.resource_id":   "vpc-1",
+			"resources.1.resource_type": "vpcs",
+			"resources.1.resource_id":   "vpc-2",
+			"tags.%":                    "3",
+			"tags.owner":                "terraform",
+			"tags.env":                  "test",
+			"tags.app":                  "demo",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"project_id": "project-id",
+		"resources": []interface{}{
+			map[string]interface{}{"resource_type": "vpcs", "resource_id": "vpc-1"},
+			map[string]interface{}{"resource_type": "vpcs", "resource_id": "vpc-3"},
+		},
+		"tags": map[string]interface{}{
+			"owner": "terraform",
+			"env":   "prod",
+			"team":  "iac",
+		},
+	})
+
+	diff, err := r.Diff(context.Background(), state, config, nil)
+	th.AssertNoErr(t, err)
+	_, diags := r.Apply(context.Background(), state, diff, nil)
+	if diags.HasError() {
+		t.Fatalf("error updating tags: %v", diags)
+	}
+
+	expected := []string{
+		// the resource which is no longer managed loses all the old tags
+		"delete [vpc-2] [app=demo env=test owner=terraform]",
+		// the resource which is still managed only loses the removed tag, and the unchanged tag is never touched
+		"delete [vpc-1] [app=demo]",
+		"create [vpc-1] [env=prod team=iac]",
+		// the new resource gets all the tags
+		"create [vpc-3] [env=prod owner=terraform team=iac]",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Fatalf("the requests sent by updateResourceTags are not as expected, want %v, but got %v",
+			expected, requests)
+	}
+}
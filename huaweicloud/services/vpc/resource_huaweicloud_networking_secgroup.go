@@ -203,7 +203,7 @@ func resourceNetworkingSecGroupCreate(ctx context.Context, d *schema.ResourceDat
 	}
 
 	// set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	if len(tagRaw) > 0 {
 		v2Client, err := cfg.NetworkingV2Client(region)
 		if err != nil {
@@ -256,7 +256,7 @@ func resourceNetworkingSecGroupCreateV1(ctx context.Context, d *schema.ResourceD
 	}
 
 	// set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	if len(tagRaw) > 0 {
 		v2Client, err := cfg.NetworkingV2Client(region)
 		if err != nil {
@@ -426,7 +426,7 @@ func resourceNetworkingSecGroupUpdate(ctx context.Context, d *schema.ResourceDat
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		v2Client, err := cfg.NetworkingV2Client(region)
 		if err != nil {
 			return diag.Errorf("error creating networking v2 client: %s", err)
@@ -45,6 +45,8 @@ func ResourceVirtualPrivateCloudV1() *schema.Resource {
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		CustomizeDiff: common.PreflightQuotaCheck(vpcQuotaDemands),
+
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
 			Delete: schema.DefaultTimeout(3 * time.Minute),
@@ -117,6 +119,13 @@ func ResourceVirtualPrivateCloudV1() *schema.Resource {
 	}
 }
 
+func vpcQuotaDemands(_ context.Context, _ *schema.ResourceDiff, _ *config.Config) (map[string]map[string]int,
+	error) {
+	return map[string]map[string]int{
+		"vpc": {common.QuotaVPCVPCs: 1},
+	}, nil
+}
+
 func resourceVirtualPrivateCloudCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	conf := meta.(*config.Config)
 	region := conf.GetRegion(d)
@@ -161,7 +170,7 @@ func resourceVirtualPrivateCloudCreate(ctx context.Context, d *schema.ResourceDa
 	}
 
 	// set tags
-	tagRaw := d.Get("tags").(map[string]interface{})
+	tagRaw := utils.GetAllResourceTags(d)
 	if len(tagRaw) > 0 {
 		v2Client, err := conf.NetworkingV2Client(region)
 		if err != nil {
@@ -299,7 +308,7 @@ func resourceVirtualPrivateCloudUpdate(ctx context.Context, d *schema.ResourceDa
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		v2Client, err := conf.NetworkingV2Client(region)
 		if err != nil {
 			return diag.Errorf("error creating VPC v2 client: %s", err)
@@ -594,17 +594,19 @@ func updateTags(client *golangsdk.ServiceClient, d *schema.ResourceData, id stri
 	oMap := oRaw.(map[string]interface{})
 	nMap := nRaw.(map[string]interface{})
 
-	// remove old tags
-	if len(oMap) > 0 {
-		err := doTagsAction(client, oMap, id, "delete")
+	removedTags, upsertedTags := utils.DiffTags(oMap, nMap)
+
+	// remove the tags which are no longer present
+	if len(removedTags) > 0 {
+		err := doTagsAction(client, removedTags, id, "delete")
 		if err != nil {
 			return err
 		}
 	}
 
-	// set new tags
-	if len(nMap) > 0 {
-		err := doTagsAction(client, nMap, id, "create")
+	// create the new tags and update the tags whose value is changed
+	if len(upsertedTags) > 0 {
+		err := doTagsAction(client, upsertedTags, id, "create")
 		if err != nil {
 			return err
 		}
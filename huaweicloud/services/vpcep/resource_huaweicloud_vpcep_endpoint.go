@@ -138,7 +138,7 @@ func resourceVPCEndpointCreate(ctx context.Context, d *schema.ResourceData, meta
 		Description:     d.Get("description").(string),
 		EnableDNS:       utils.Bool(d.Get("enable_dns").(bool)),
 		EnableWhitelist: utils.Bool(enableACL),
-		Tags:            utils.ExpandResourceTags(d.Get("tags").(map[string]interface{})),
+		Tags:            utils.ExpandResourceTags(utils.GetAllResourceTags(d)),
 	}
 
 	routeTables := d.Get("routetables").(*schema.Set)
@@ -252,7 +252,7 @@ func resourceVPCEndpointUpdate(ctx context.Context, d *schema.ResourceData, meta
 			return diag.Errorf("error updating VPC endpoint whitelist: %s", err)
 		}
 	}
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		tagErr := utils.UpdateResourceTags(vpcepClient, d, tagVPCEP, d.Id())
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of VPC endpoint %s: %s", d.Id(), tagErr)
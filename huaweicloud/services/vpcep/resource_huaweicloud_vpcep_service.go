@@ -268,7 +268,7 @@ func resourceVPCEndpointServiceCreate(ctx context.Context, d *schema.ResourceDat
 		Description: d.Get("description").(string),
 		Approval:    utils.Bool(d.Get("approval").(bool)),
 		Ports:       buildPortMappingOpts(d),
-		Tags:        utils.ExpandResourceTags(d.Get("tags").(map[string]interface{})),
+		Tags:        utils.ExpandResourceTags(utils.GetAllResourceTags(d)),
 	}
 
 	// The European station does not support this parameter, so set it separately.
@@ -384,7 +384,7 @@ func resourceVPCEndpointServiceUpdate(ctx context.Context, d *schema.ResourceDat
 	}
 
 	// update tags
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		tagErr := utils.UpdateResourceTags(vpcepClient, d, tagVPCEPService, d.Id())
 		if tagErr != nil {
 			return diag.Errorf("error updating tags of VPC endpoint service %s: %s", d.Id(), tagErr)
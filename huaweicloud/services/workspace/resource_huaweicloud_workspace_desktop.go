@@ -284,7 +284,7 @@ func buildDesktopCreateOpts(d *schema.ResourceData, conf *config.Config) desktop
 		DataVolumes:         buildDesktopDataVolumes(d.Get("data_volume").([]interface{})),
 		Nics:                buildDesktopNics(d.Get("nic").([]interface{})),
 		SecurityGroups:      buildDesktopSecurityGroups(d.Get("security_groups").(*schema.Set)),
-		Tags:                utils.ExpandResourceTags(d.Get("tags").(map[string]interface{})),
+		Tags:                utils.ExpandResourceTags(utils.GetAllResourceTags(d)),
 		EnterpriseProjectId: conf.GetEnterpriseProjectID(d),
 	}
 	return result
@@ -752,7 +752,7 @@ func resourceDesktopUpdate(ctx context.Context, d *schema.ResourceData, meta int
 		}
 	}
 
-	if d.HasChange("tags") {
+	if d.HasChanges("tags", "tags_all") {
 		err = utils.UpdateResourceTags(client, d, "desktops", desktopId)
 		if err != nil {
 			return diag.Errorf("error updating tags of Workspace desktop (%s): %s", desktopId, err)
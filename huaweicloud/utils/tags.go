@@ -1,8 +1,10 @@
 package utils
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
@@ -12,36 +14,176 @@ import (
 
 const SysTagKeyEnterpriseProjectId = "_sys_enterprise_project_id"
 
+// ignoreTagsKey is the context key of the tag keys and key prefixes ignored by the provider configuration.
+type ignoreTagsKey struct{}
+
+type ignoreTags struct {
+	keys        []string
+	keyPrefixes []string
+}
+
+// ContextWithIgnoreTags returns a copy of the context which carries the tag keys and key prefixes ignored by the
+// provider configuration. The clients of the provider configuration are built with the context, so that the tags
+// helpers called with the clients honour the ignored tags of their own provider configuration.
+func ContextWithIgnoreTags(ctx context.Context, keys, keyPrefixes []string) context.Context {
+	if len(keys) == 0 && len(keyPrefixes) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, ignoreTagsKey{}, ignoreTags{keys: keys, keyPrefixes: keyPrefixes})
+}
+
+// clientIgnoreTags returns the tag keys and key prefixes ignored by the provider configuration of the client.
+func clientIgnoreTags(client *golangsdk.ServiceClient) (keys, keyPrefixes []string) {
+	if client == nil || client.ProviderClient == nil || client.Context == nil {
+		return nil, nil
+	}
+	ignored, _ := client.Context.Value(ignoreTagsKey{}).(ignoreTags)
+	return ignored.keys, ignored.keyPrefixes
+}
+
+// RemoveIgnoredTags returns a new map without the tags whose keys are in keys or start with any of keyPrefixes.
+// The tags whose keys are found in the configured tags (configuredTags) are always kept, so that an ignored tag
+// which is also managed by the resource never causes a diff.
+func RemoveIgnoredTags(tagmap, configuredTags map[string]interface{}, keys, keyPrefixes []string) map[string]interface{} {
+	result := make(map[string]interface{}, len(tagmap))
+	for k, v := range tagmap {
+		if _, ok := configuredTags[k]; ok || !isIgnoredTag(k, keys, keyPrefixes) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func isIgnoredTag(key string, keys, keyPrefixes []string) bool {
+	if StrSliceContains(keys, key) {
+		return true
+	}
+	for _, prefix := range keyPrefixes {
+		if prefix != "" && strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeDefaultTags returns a new map which contains both the provider-level default tags and the resource tags,
+// the resource tags take precedence over the default tags with the same key.
+func MergeDefaultTags(defaultTags map[string]string, resourceTags map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(defaultTags)+len(resourceTags))
+	for k, v := range defaultTags {
+		result[k] = v
+	}
+	for k, v := range resourceTags {
+		result[k] = v
+	}
+	return result
+}
+
+// RemoveDefaultTags returns a new map without the tags which are inherited from the provider-level default tags.
+// A tag is treated as inherited when it has the same key and value as a default tag and its key is not found in
+// the configured tags (configuredTags), so that an explicitly configured tag will never be removed.
+func RemoveDefaultTags(defaultTags map[string]string, tagmap, configuredTags map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(tagmap))
+	for k, v := range tagmap {
+		if defaultValue, ok := defaultTags[k]; ok && defaultValue == v {
+			if _, ok := configuredTags[k]; !ok {
+				continue
+			}
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// ResourceTagsKey returns the key of the tags that should be applied to the resource.
+// If the resource supports the provider-level default tags, the key is "tags_all", otherwise "tags".
+func ResourceTagsKey(d *schema.ResourceData) string {
+	if _, ok := d.Get("tags_all").(map[string]interface{}); ok {
+		return "tags_all"
+	}
+	return "tags"
+}
+
+// GetAllResourceTags returns the tags that should be applied to the resource, including the provider-level default
+// tags if the resource has a "tags_all" attribute.
+func GetAllResourceTags(d *schema.ResourceData) map[string]interface{} {
+	return d.Get(ResourceTagsKey(d)).(map[string]interface{})
+}
+
+// GetAllResourceTagsChange returns the old and new tags that should be applied to the resource, including the
+// provider-level default tags if the resource has a "tags_all" attribute.
+func GetAllResourceTagsChange(d *schema.ResourceData) (oMap, nMap map[string]interface{}) {
+	oRaw, nRaw := d.GetChange("tags")
+	oMap, nMap = oRaw.(map[string]interface{}), nRaw.(map[string]interface{})
+	if ResourceTagsKey(d) != "tags_all" {
+		return
+	}
+
+	oAllRaw, nAllRaw := d.GetChange("tags_all")
+	// the old "tags_all" is empty if the resource was created before it was introduced, so merge the old "tags"
+	// into it to make sure that the removed tags can be deleted.
+	oMap = MergeDefaultTags(nil, oMap)
+	for k, v := range oAllRaw.(map[string]interface{}) {
+		oMap[k] = v
+	}
+	nMap = nAllRaw.(map[string]interface{})
+	return
+}
+
 // CreateResourceTags is a helper to create the tags for a resource.
-// It expects the schema name must be "tags"
+// It expects the schema name must be "tags", the provider-level default tags are also created if the resource
+// has a "tags_all" attribute.
 func CreateResourceTags(client *golangsdk.ServiceClient, d *schema.ResourceData, resourceType, id string) error {
-	if tagRaw := d.Get("tags").(map[string]interface{}); len(tagRaw) > 0 {
+	if tagRaw := GetAllResourceTags(d); len(tagRaw) > 0 {
 		tagList := ExpandResourceTags(tagRaw)
 		return tags.Create(client, resourceType, id, tagList).ExtractErr()
 	}
 	return nil
 }
 
+// DiffTags computes the changes between the old tags and the new tags, it returns the tags which should be removed
+// (the keys are not present in the new tags) and the tags which should be created or updated (the keys are not
+// present in the old tags or the values are changed), so that the unchanged tags will never be touched.
+func DiffTags(oldTags, newTags map[string]interface{}) (removedTags, upsertedTags map[string]interface{}) {
+	removedTags = make(map[string]interface{})
+	upsertedTags = make(map[string]interface{})
+
+	for k, v := range oldTags {
+		if _, ok := newTags[k]; !ok {
+			removedTags[k] = v
+		}
+	}
+	for k, v := range newTags {
+		if oldValue, ok := oldTags[k]; !ok || oldValue != v {
+			upsertedTags[k] = v
+		}
+	}
+	return
+}
+
 // UpdateResourceTags is a helper to update the tags for a resource.
-// It expects the tags field to be named "tags"
+// It expects the tags field to be named "tags", the provider-level default tags are also updated if the resource
+// has a "tags_all" attribute.
 func UpdateResourceTags(conn *golangsdk.ServiceClient, d *schema.ResourceData, resourceType, id string) error {
-	if d.HasChange("tags") {
-		oRaw, nRaw := d.GetChange("tags")
-		oMap := oRaw.(map[string]interface{})
-		nMap := nRaw.(map[string]interface{})
-
-		// remove old tags
-		if len(oMap) > 0 {
-			taglist := ExpandResourceTags(oMap)
+	if d.HasChanges("tags", "tags_all") {
+		oMap, nMap := GetAllResourceTagsChange(d)
+		removedTags, upsertedTags := DiffTags(oMap, nMap)
+		// the ignored tags are managed externally, so they're never removed unless they're configured before
+		keys, keyPrefixes := clientIgnoreTags(conn)
+		removedTags = RemoveIgnoredTags(removedTags, nil, keys, keyPrefixes)
+
+		// remove the tags which are no longer present
+		if len(removedTags) > 0 {
+			taglist := ExpandResourceTags(removedTags)
 			err := tags.Delete(conn, resourceType, id, taglist).ExtractErr()
 			if err != nil {
 				return err
 			}
 		}
 
-		// set new tags
-		if len(nMap) > 0 {
-			taglist := ExpandResourceTags(nMap)
+		// create the new tags and update the tags whose value is changed
+		if len(upsertedTags) > 0 {
+			taglist := ExpandResourceTags(upsertedTags)
 			err := tags.Create(conn, resourceType, id, taglist).ExtractErr()
 			if err != nil {
 				return err
@@ -68,13 +210,32 @@ func DeleteResourceTagsWithKeys(client *golangsdk.ServiceClient, tagKeys []strin
 
 // SetResourceTagsToState is a helper to query tags of resource, then set to state.
 // The schema argument name must be: tags
+// If the resource has a "tags_all" attribute, all tags of the resource will be saved into it, and the tags inherited
+// from the provider-level default tags will be removed from "tags" by the provider.
+// The tags ignored by the provider configuration of the client are not saved unless they're found in the current
+// tags of the resource, which are the configured tags when creating or updating the resource.
 func SetResourceTagsToState(d *schema.ResourceData, client *golangsdk.ServiceClient, resourceType, id string) error {
 	// set tags
 	if resourceTags, err := tags.Get(client, resourceType, id).Extract(); err == nil {
-		tagmap := TagsToMap(resourceTags.Tags)
-		if err := d.Set("tags", tagmap); err != nil {
+		tagmap := make(map[string]interface{})
+		for k, v := range TagsToMap(resourceTags.Tags) {
+			tagmap[k] = v
+		}
+		keys, keyPrefixes := clientIgnoreTags(client)
+
+		configuredTags := d.Get("tags").(map[string]interface{})
+		if err := d.Set("tags", RemoveIgnoredTags(tagmap, configuredTags, keys, keyPrefixes)); err != nil {
 			return fmt.Errorf("error saving tags to state for %s (%s): %s", resourceType, id, err)
 		}
+		if ResourceTagsKey(d) == "tags_all" {
+			configuredTags = MergeDefaultTags(nil, configuredTags)
+			for k, v := range d.Get("tags_all").(map[string]interface{}) {
+				configuredTags[k] = v
+			}
+			if err := d.Set("tags_all", RemoveIgnoredTags(tagmap, configuredTags, keys, keyPrefixes)); err != nil {
+				return fmt.Errorf("error saving tags_all to state for %s (%s): %s", resourceType, id, err)
+			}
+		}
 	} else {
 		log.Printf("[WARN] Error fetching tags of %s (%s): %s", resourceType, id, err)
 	}
@@ -0,0 +1,326 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/chnsz/golangsdk"
+	"github.com/chnsz/golangsdk/openstack/common/tags"
+	th "github.com/chnsz/golangsdk/testhelper"
+)
+
+func TestAccFunction_MergeDefaultTags(t *testing.T) {
+	var (
+		defaultTags = map[string]string{
+			"owner": "terraform",
+			"env":   "test",
+		}
+		resourceTags = map[string]interface{}{
+			"env": "prod",
+			"app": "demo",
+		}
+		expected = map[string]interface{}{
+			"owner": "terraform",
+			"env":   "prod",
+			"app":   "demo",
+		}
+	)
+
+	testOutput := MergeDefaultTags(defaultTags, resourceTags)
+	if !reflect.DeepEqual(testOutput, expected) {
+		t.Fatalf("The processing result of MergeDefaultTags method is not as expected, want %s, but %s",
+			Green(expected), Yellow(testOutput))
+	}
+	t.Logf("The processing result of MergeDefaultTags method meets expectation: %s", Green(expected))
+}
+
+func TestAccFunction_RemoveDefaultTags(t *testing.T) {
+	var (
+		defaultTags = map[string]string{
+			"owner": "terraform",
+			"env":   "test",
+			"team":  "iac",
+		}
+		tagmap = map[string]interface{}{
+			"owner": "terraform",
+			"env":   "prod",
+			"team":  "iac",
+			"app":   "demo",
+		}
+		configuredTags = map[string]interface{}{
+			"team": "iac",
+		}
+		expected = map[string]interface{}{
+			"env":  "prod",
+			"team": "iac",
+			"app":  "demo",
+		}
+	)
+
+	testOutput := RemoveDefaultTags(defaultTags, tagmap, configuredTags)
+	if !reflect.DeepEqual(testOutput, expected) {
+		t.Fatalf("The processing result of RemoveDefaultTags method is not as expected, want %s, but %s",
+			Green(expected), Yellow(testOutput))
+	}
+	t.Logf("The processing result of RemoveDefaultTags method meets expectation: %s", Green(expected))
+}
+
+func TestAccFunction_RemoveIgnoredTags(t *testing.T) {
+	var (
+		testInput = map[string]interface{}{
+			"owner":              "terraform",
+			"CreatedBy":          "cbr",
+			"finops:cost_center": "cc-001",
+			"finops:owner":       "finops",
+		}
+		// the ignored tag which is also configured is kept
+		configuredTags = map[string]interface{}{
+			"owner":        "terraform",
+			"finops:owner": "finops",
+		}
+		expected = map[string]interface{}{
+			"owner":        "terraform",
+			"finops:owner": "finops",
+		}
+	)
+
+	testOutput := RemoveIgnoredTags(testInput, configuredTags, []string{"CreatedBy"}, []string{"finops:"})
+	if !reflect.DeepEqual(testOutput, expected) {
+		t.Fatalf("The processing result of RemoveIgnoredTags method is not as expected, want %s, but %s",
+			Green(expected), Yellow(testOutput))
+	}
+	t.Logf("The processing result of RemoveIgnoredTags method meets expectation: %s", Green(expected))
+}
+
+func TestAccFunction_DiffTags(t *testing.T) {
+	var (
+		oldTags = map[string]interface{}{
+			"owner": "terraform",
+			"env":   "test",
+			"app":   "demo",
+		}
+		newTags = map[string]interface{}{
+			"owner": "terraform",
+			"env":   "prod",
+			"team":  "iac",
+		}
+		expectedRemoved = map[string]interface{}{
+			"app": "demo",
+		}
+		expectedUpserted = map[string]interface{}{
+			"env":  "prod",
+			"team": "iac",
+		}
+	)
+
+	removedTags, upsertedTags := DiffTags(oldTags, newTags)
+	if !reflect.DeepEqual(removedTags, expectedRemoved) {
+		t.Fatalf("The removed tags of DiffTags method is not as expected, want %s, but %s",
+			Green(expectedRemoved), Yellow(removedTags))
+	}
+	if !reflect.DeepEqual(upsertedTags, expectedUpserted) {
+		t.Fatalf("The upserted tags of DiffTags method is not as expected, want %s, but %s",
+			Green(expectedUpserted), Yellow(upsertedTags))
+	}
+	t.Logf("The processing result of DiffTags method meets expectation: %s, %s",
+		Green(expectedRemoved), Green(expectedUpserted))
+}
+
+func TestAccFunction_UpdateResourceTags(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	// the requests received by the fake tags endpoint, in the order of arrival
+	var actions []tags.ActionOpts
+	th.Mux.HandleFunc("/v1/project-id/vpcs/test-id/tags/action", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "POST")
+
+		body, err := io.ReadAll(r.Body)
+		th.AssertNoErr(t, err)
+
+		var action tags.ActionOpts
+		th.AssertNoErr(t, json.Unmarshal(body, &action))
+		sort.Slice(action.Tags, func(i, j int) bool { return action.Tags[i].Key < action.Tags[j].Key })
+		actions = append(actions, action)
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client := &golangsdk.ServiceClient{
+		ProviderClient: &golangsdk.ProviderClient{ProjectID: "project-id"},
+		Endpoint:       th.Endpoint(),
+		ResourceBase:   th.Endpoint() + "v1/project-id/",
+	}
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"tags": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+		UpdateContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+			return diag.FromErr(UpdateResourceTags(client, d, "vpcs", d.Id()))
+		},
+	}
+
+	state := &terraform.InstanceState{
+		ID: "test-id",
+		Attributes: map[string]string{
+			"id":         "test-id",
+			"tags.%":     "3",
+			"tags.owner": "terraform",
+			"tags.env":   "test",
+			"tags.app":   "demo",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"tags": map[string]interface{}{
+			"owner": "terraform",
+			"env":   "prod",
+			"team":  "iac",
+		},
+	})
+
+	diff, err := r.Diff(context.Background(), state, config, nil)
+	th.AssertNoErr(t, err)
+	_, diags := r.Apply(context.Background(), state, diff, nil)
+	if diags.HasError() {
+		t.Fatalf("error updating tags: %v", diags)
+	}
+
+	expected := []tags.ActionOpts{
+		{
+			Action: "delete",
+			Tags:   []tags.ResourceTag{{Key: "app", Value: "demo"}},
+		},
+		{
+			Action: "create",
+			Tags:   []tags.ResourceTag{{Key: "env", Value: "prod"}, {Key: "team", Value: "iac"}},
+		},
+	}
+	if !reflect.DeepEqual(actions, expected) {
+		t.Fatalf("The requests sent by UpdateResourceTags method are not as expected, want %s, but %s",
+			Green(fmt.Sprintf("%+v", expected)), Yellow(fmt.Sprintf("%+v", actions)))
+	}
+	t.Logf("The requests sent by UpdateResourceTags method meet expectation: %s",
+		Green(fmt.Sprintf("%+v", expected)))
+}
+
+func TestAccFunction_IgnoredTags(t *testing.T) {
+	th.SetupHTTP()
+	defer th.TeardownHTTP()
+
+	// the tags of the resource in the cloud, "CreatedBy" and "finops:cost_center" are applied externally
+	th.Mux.HandleFunc("/v1/project-id/vpcs/test-id/tags", func(w http.ResponseWriter, r *http.Request) {
+		th.TestMethod(t, r, "GET")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"tags": [{"key": "owner", "value": "terraform"}, {"key": "CreatedBy", "value": "cbr"},
+{"key": "finops:cost_center", "value": "cc-001"}, {"key": "finops:owner", "value": "finops"}]}`)
+	})
+	var actions []tags.ActionOpts
+	th.Mux.HandleFunc("/v1/project-id/vpcs/test-id/tags/action", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		th.AssertNoErr(t, err)
+		var action tags.ActionOpts
+		th.AssertNoErr(t, json.Unmarshal(body, &action))
+		actions = append(actions, action)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	client := &golangsdk.ServiceClient{
+		ProviderClient: &golangsdk.ProviderClient{
+			ProjectID: "project-id",
+			Context:   ContextWithIgnoreTags(context.Background(), []string{"CreatedBy"}, []string{"finops:"}),
+		},
+		Endpoint:     th.Endpoint(),
+		ResourceBase: th.Endpoint() + "v1/project-id/",
+	}
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"tags": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+		ReadContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+			return diag.FromErr(SetResourceTagsToState(d, client, "vpcs", d.Id()))
+		},
+		UpdateContext: func(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+			if err := UpdateResourceTags(client, d, "vpcs", d.Id()); err != nil {
+				return diag.FromErr(err)
+			}
+			return diag.FromErr(SetResourceTagsToState(d, client, "vpcs", d.Id()))
+		},
+	}
+
+	// "CreatedBy" was saved before it's ignored, and the ignored "finops:owner" is also configured
+	state := &terraform.InstanceState{
+		ID: "test-id",
+		Attributes: map[string]string{
+			"id":             "test-id",
+			"tags.%":         "2",
+			"tags.owner":     "terraform",
+			"tags.CreatedBy": "cbr",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"tags": map[string]interface{}{
+			"owner":        "terraform",
+			"finops:owner": "finops",
+		},
+	})
+
+	diff, err := r.Diff(context.Background(), state, config, nil)
+	th.AssertNoErr(t, err)
+	state, diags := r.Apply(context.Background(), state, diff, nil)
+	if diags.HasError() {
+		t.Fatalf("error updating tags: %v", diags)
+	}
+
+	// the ignored tag which is no longer configured is not removed
+	expectedActions := []tags.ActionOpts{
+		{
+			Action: "create",
+			Tags:   []tags.ResourceTag{{Key: "finops:owner", Value: "finops"}},
+		},
+	}
+	if !reflect.DeepEqual(actions, expectedActions) {
+		t.Fatalf("The requests sent by UpdateResourceTags method are not as expected, want %s, but %s",
+			Green(fmt.Sprintf("%+v", expectedActions)), Yellow(fmt.Sprintf("%+v", actions)))
+	}
+
+	// only the ignored tag which is configured is saved, so there is no diff with the configuration
+	expected := map[string]string{
+		"id":                "test-id",
+		"tags.%":            "2",
+		"tags.owner":        "terraform",
+		"tags.finops:owner": "finops",
+	}
+	if !reflect.DeepEqual(state.Attributes, expected) {
+		t.Fatalf("The tags saved by SetResourceTagsToState method are not as expected, want %s, but %s",
+			Green(expected), Yellow(state.Attributes))
+	}
+
+	state, diags = r.RefreshWithoutUpgrade(context.Background(), state, nil)
+	if diags.HasError() {
+		t.Fatalf("error reading tags: %v", diags)
+	}
+	diff, err = r.Diff(context.Background(), state, config, nil)
+	th.AssertNoErr(t, err)
+	if diff != nil && !diff.Empty() {
+		t.Fatalf("expect no diff after refreshing, but got %#v", diff)
+	}
+	t.Logf("The ignored tags are honoured by the tags helpers: %s", Green(state.Attributes))
+}
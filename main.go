@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6/tf6server"
 
 	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
 )
 
 func main() {
@@ -13,6 +16,23 @@ func main() {
 	// prevent duplicate timestamp and incorrect log level setting
 	log.SetFlags(log.Flags() &^ (log.Ldate | log.Ltime))
 
-	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: huaweicloud.Provider})
+	debug := flag.Bool("debug", false, "set to true to run the provider with support for debuggers like delve")
+	flag.Parse()
+
+	serverFactory, err := huaweicloud.ProviderServerFactory(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var serveOpts []tf6server.ServeOpt
+	if *debug {
+		serveOpts = append(serveOpts, tf6server.WithManagedDebug())
+	}
+
+	err = tf6server.Serve("registry.terraform.io/huaweicloud/huaweicloud", serverFactory, serveOpts...)
+	// the spans are exported in batches, so export the remaining ones before the provider process exits
+	config.ShutdownTelemetry()
+	if err != nil {
+		log.Fatal(err)
+	}
 }
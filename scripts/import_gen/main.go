@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud"
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud/config"
+)
+
+// tagFlags collects the repeatable -tag flags in the format of key=value.
+type tagFlags map[string]string
+
+func (t tagFlags) String() string {
+	pairs := make([]string, 0, len(t))
+	for k, v := range t {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (t tagFlags) Set(value string) error {
+	k, v, ok := strings.Cut(value, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("the tag must be in the format of key=value")
+	}
+	t[k] = v
+	return nil
+}
+
+// typeFlags collects the repeatable -type flags.
+type typeFlags []string
+
+func (t *typeFlags) String() string {
+	return strings.Join(*t, ",")
+}
+
+func (t *typeFlags) Set(value string) error {
+	if _, ok := resourceTypes[value]; !ok {
+		return fmt.Errorf("the RMS resource type %s is not supported", value)
+	}
+	*t = append(*t, value)
+	return nil
+}
+
+var (
+	regionID            string
+	enterpriseProjectID string
+	outputFile          string
+	tags                = tagFlags{}
+	types               typeFlags
+
+	commandLine flag.FlagSet
+)
+
+func init() {
+	commandLine.Init(os.Args[0], flag.ExitOnError)
+
+	commandLine.StringVar(&regionID, "region", "", "Only generates the resources in the region.")
+	commandLine.StringVar(&enterpriseProjectID, "enterprise-project-id", "",
+		"Only generates the resources in the enterprise project.")
+	commandLine.Var(tags, "tag", "Only generates the resources with the tag in the format of key=value, "+
+		"can be specified multiple times.")
+	commandLine.Var(&types, "type", "Only generates the resources of the RMS type in the format of provider.type, "+
+		"e.g. ecs.cloudservers, can be specified multiple times.")
+	commandLine.StringVar(&outputFile, "out", "", "The file to write the HCL, defaults to the standard output.")
+
+	commandLine.Usage = func() {
+		fmt.Fprintf(commandLine.Output(), "Usage of %s:\n\n", os.Args[0])
+		fmt.Fprintf(commandLine.Output(), "The credentials are read from the environment variables of the provider, "+
+			"e.g. HW_ACCESS_KEY, HW_SECRET_KEY and HW_REGION_NAME.\n\n")
+		commandLine.PrintDefaults()
+	}
+}
+
+func main() {
+	commandLine.Parse(os.Args[1:]) //nolint: errcheck
+
+	provider := huaweicloud.Provider()
+	diags := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{}))
+	if diags.HasError() {
+		fmt.Printf("failed to configure the provider: %v\n", diags)
+		os.Exit(1)
+	}
+	cfg := provider.Meta().(*config.Config)
+
+	client, err := cfg.NewServiceClient("rms", cfg.Region)
+	if err != nil {
+		fmt.Printf("error creating RMS v1 client: %s\n", err)
+		os.Exit(2)
+	}
+
+	// only one type can be specified in each query
+	queryTypes := []string(types)
+	if len(queryTypes) == 0 {
+		queryTypes = []string{""}
+	}
+	var resources []rmsResource
+	for _, t := range queryTypes {
+		f := &filters{
+			regionID:            regionID,
+			enterpriseProjectID: enterpriseProjectID,
+			resourceType:        t,
+			tags:                tags,
+		}
+		result, err := listResources(client, cfg.DomainID, f)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(3)
+		}
+		resources = append(resources, result...)
+	}
+
+	var output io.Writer = os.Stdout
+	if outputFile != "" {
+		file, err := os.Create(outputFile)
+		if err != nil {
+			fmt.Printf("failed to create output file: %s\n", err)
+			os.Exit(4)
+		}
+		defer file.Close()
+		output = file
+	}
+
+	if err := generate(output, provider.ResourcesMap, cfg.Region, resources); err != nil {
+		fmt.Printf("failed to write the HCL: %s\n", err)
+		os.Exit(5)
+	}
+}
+
+// generate writes the import blocks and the skeleton resources, the RMS resources which can not be mapped to the
+// huaweicloud resources are skipped and counted in the standard error. The resources outside the default region are
+// managed by the provider aliases of their regions, which are written at the end.
+func generate(w io.Writer, schemas map[string]*schema.Resource, defaultRegion string, resources []rmsResource) error {
+	r := newRenderer(schemas, defaultRegion)
+	skipped := make(map[string]int)
+	for i := range resources {
+		res := &resources[i]
+		rt, ok := resourceTypes[res.rmsType()]
+		if !ok || schemas[rt.name] == nil {
+			skipped[res.rmsType()]++
+			continue
+		}
+		if err := r.render(w, rt, res); err != nil {
+			return err
+		}
+	}
+	if err := r.renderProviders(w); err != nil {
+		return err
+	}
+
+	for t, count := range skipped {
+		fmt.Fprintf(os.Stderr, "skipped %d resources of the unsupported RMS type %s\n", count, t)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/huaweicloud/terraform-provider-huaweicloud/huaweicloud"
+)
+
+func TestResourceTypes(t *testing.T) {
+	provider := huaweicloud.Provider()
+	for rmsType, rt := range resourceTypes {
+		if _, ok := provider.ResourcesMap[rt.name]; !ok {
+			t.Errorf("the resource %s of the RMS type %s does not exist", rt.name, rmsType)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	resources := []rmsResource{
+		{
+			ID:       "vpc-id",
+			Name:     "My VPC",
+			Provider: "vpc",
+			Type:     "vpcs",
+			RegionID: "cn-north-4",
+			Tags:     map[string]string{"owner": "${team}"},
+			Properties: map[string]interface{}{
+				"cidr": "192.168.0.0/16",
+			},
+		},
+		{
+			ID:       "another-vpc-id",
+			Name:     "my-vpc",
+			Provider: "vpc",
+			Type:     "vpcs",
+			RegionID: "cn-north-4",
+		},
+		{
+			ID:       "bucket-id",
+			Name:     "bucket",
+			Provider: "obs",
+			Type:     "buckets",
+			RegionID: "cn-north-4",
+		},
+		{
+			ID:       "subnet-id",
+			Name:     "subnet",
+			Provider: "vpc",
+			Type:     "subnets",
+			RegionID: "ap-southeast-1",
+		},
+		{
+			ID:       "unknown-id",
+			Provider: "unknown",
+			Type:     "unknown",
+		},
+	}
+
+	b := &strings.Builder{}
+	if err := generate(b, huaweicloud.Provider().ResourcesMap, "cn-north-4", resources); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	output := b.String()
+	expected := []string{
+		"import {\n  to = huaweicloud_vpc.my_vpc\n  id = \"vpc-id\"\n}",
+		"import {\n  to = huaweicloud_vpc.my-vpc\n  id = \"another-vpc-id\"\n}",
+		`resource "huaweicloud_vpc" "my_vpc" {`,
+		`  cidr = "192.168.0.0/16"`,
+		`  name = "My VPC"`,
+		`    "owner" = "$${team}"`,
+		"import {\n  to = huaweicloud_obs_bucket.bucket\n  id = \"bucket\"\n}",
+		`  bucket = "bucket"`,
+		// the resources outside the default region are managed by the provider aliases
+		"import {\n  to = huaweicloud_vpc_subnet.subnet\n  id = \"subnet-id\"\n  provider = huaweicloud.ap_southeast_1\n}",
+		"resource \"huaweicloud_vpc_subnet\" \"subnet\" {\n  provider = huaweicloud.ap_southeast_1\n",
+		"provider \"huaweicloud\" {\n  alias  = \"ap_southeast_1\"\n  region = \"ap-southeast-1\"\n}",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("expect the output to contain %q, but got:\n%s", e, output)
+		}
+	}
+	if strings.Contains(output, "# TODO: bucket is required") {
+		t.Errorf("the bucket name is not written to the bucket argument:\n%s", output)
+	}
+	if strings.Count(output, `provider "huaweicloud"`) != 1 {
+		t.Errorf("expect only the provider alias of ap-southeast-1, but got:\n%s", output)
+	}
+	if strings.Contains(output, "unknown-id") {
+		t.Errorf("the resource of the unsupported type is not skipped:\n%s", output)
+	}
+}
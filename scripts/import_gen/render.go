@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var invalidLabelChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// renderer renders the import blocks and the skeleton resources, the labels of the resources are unique.
+type renderer struct {
+	resources map[string]*schema.Resource
+	labels    map[string]bool
+	// defaultRegion is the region of the default provider configuration, the resources in the other regions are
+	// managed by the provider aliases of their regions
+	defaultRegion string
+	// aliasRegions are the regions of the provider aliases referenced by the resources
+	aliasRegions map[string]bool
+}
+
+func newRenderer(resources map[string]*schema.Resource, defaultRegion string) *renderer {
+	return &renderer{
+		resources:     resources,
+		labels:        make(map[string]bool),
+		defaultRegion: defaultRegion,
+		aliasRegions:  make(map[string]bool),
+	}
+}
+
+// providerAlias returns the alias of the provider configuration in the region, e.g. ap_southeast_1.
+func providerAlias(region string) string {
+	return strings.ReplaceAll(region, "-", "_")
+}
+
+// provider returns the provider alias reference of the RMS resource, or an empty string if the resource is global or
+// in the default region.
+func (r *renderer) provider(res *rmsResource) string {
+	if res.RegionID == "" || res.RegionID == "global" || res.RegionID == r.defaultRegion {
+		return ""
+	}
+	r.aliasRegions[res.RegionID] = true
+	return "huaweicloud." + providerAlias(res.RegionID)
+}
+
+// renderProviders writes the provider aliases referenced by the rendered resources.
+func (r *renderer) renderProviders(w io.Writer) error {
+	regions := make([]string, 0, len(r.aliasRegions))
+	for region := range r.aliasRegions {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	b := &strings.Builder{}
+	for _, region := range regions {
+		fmt.Fprintf(b, "provider \"huaweicloud\" {\n  alias  = %q\n  region = %s\n}\n\n", providerAlias(region),
+			hclString(region))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// label returns a unique resource label built from the name of the RMS resource.
+func (r *renderer) label(resourceType string, res *rmsResource) string {
+	label := strings.Trim(invalidLabelChars.ReplaceAllString(strings.ToLower(res.Name), "_"), "_-")
+	if label == "" || (label[0] >= '0' && label[0] <= '9') || label[0] == '-' {
+		label = "r_" + label
+	}
+
+	result := label
+	for i := 2; r.labels[resourceType+"."+result]; i++ {
+		result = fmt.Sprintf("%s_%d", label, i)
+	}
+	r.labels[resourceType+"."+result] = true
+	return result
+}
+
+// render writes the import block and the skeleton resource of the RMS resource.
+func (r *renderer) render(w io.Writer, rt resourceType, res *rmsResource) error {
+	importID := res.ID
+	if rt.importID != nil {
+		importID = rt.importID(res)
+	}
+	label := r.label(rt.name, res)
+	provider := r.provider(res)
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "# %s: %s\n", res.rmsType(), res.ID)
+	fmt.Fprintf(b, "import {\n  to = %s.%s\n  id = %s\n", rt.name, label, hclString(importID))
+	if provider != "" {
+		fmt.Fprintf(b, "  provider = %s\n", provider)
+	}
+	b.WriteString("}\n\n")
+	fmt.Fprintf(b, "resource %q %q {\n", rt.name, label)
+	if provider != "" {
+		fmt.Fprintf(b, "  provider = %s\n\n", provider)
+	}
+	r.renderArguments(b, rt, res)
+	b.WriteString("}\n\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// renderArguments writes the arguments which can be known from the RMS resource, and leaves TODO comments for the
+// other required arguments.
+func (r *renderer) renderArguments(b *strings.Builder, rt resourceType, res *rmsResource) {
+	nameArgument := rt.nameArgument
+	if nameArgument == "" {
+		nameArgument = "name"
+	}
+
+	resource := r.resources[rt.name]
+	keys := make([]string, 0, len(resource.Schema))
+	for k := range resource.Schema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s := resource.Schema[k]
+		if !s.Required && !s.Optional {
+			continue
+		}
+
+		switch {
+		case k == nameArgument && res.Name != "":
+			fmt.Fprintf(b, "  %s = %s\n", k, hclString(res.Name))
+		case k == "region" && res.RegionID != "" && res.RegionID != "global":
+			fmt.Fprintf(b, "  %s = %s\n", k, hclString(res.RegionID))
+		case k == "enterprise_project_id" && res.EnterpriseProjectID != "":
+			fmt.Fprintf(b, "  %s = %s\n", k, hclString(res.EnterpriseProjectID))
+		case k == "tags" && len(res.Tags) > 0:
+			renderTags(b, res.Tags)
+		case s.Required:
+			if v, ok := primitiveValue(s, res.Properties[k]); ok {
+				fmt.Fprintf(b, "  %s = %s\n", k, v)
+			} else {
+				fmt.Fprintf(b, "  # TODO: %s is required\n", k)
+			}
+		}
+	}
+}
+
+func renderTags(b *strings.Builder, tags map[string]string) {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b.WriteString("  tags = {\n")
+	for _, k := range keys {
+		fmt.Fprintf(b, "    %s = %s\n", hclString(k), hclString(tags[k]))
+	}
+	b.WriteString("  }\n")
+}
+
+// primitiveValue returns the HCL expression of the property if the types of the property and the attribute are the
+// same primitive type.
+func primitiveValue(s *schema.Schema, property interface{}) (string, bool) {
+	switch v := property.(type) {
+	case string:
+		if s.Type == schema.TypeString {
+			return hclString(v), true
+		}
+	case bool:
+		if s.Type == schema.TypeBool {
+			return strconv.FormatBool(v), true
+		}
+	case float64:
+		if s.Type == schema.TypeInt || s.Type == schema.TypeFloat {
+			return strconv.FormatFloat(v, 'f', -1, 64), true
+		}
+	}
+	return "", false
+}
+
+// hclString returns the quoted HCL string, the template sequences `${` and `%{` are escaped.
+func hclString(s string) string {
+	b := &strings.Builder{}
+	b.WriteByte('"')
+	for i, c := range s {
+		switch {
+		case c == '\\':
+			b.WriteString(`\\`)
+		case c == '"':
+			b.WriteString(`\"`)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case (c == '$' || c == '%') && strings.HasPrefix(s[i+1:], "{"):
+			b.WriteRune(c)
+			b.WriteRune(c)
+		case c < 0x20:
+			fmt.Fprintf(b, `\u%04x`, c)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/chnsz/golangsdk"
+)
+
+// rmsResource is a resource recorded by RMS, only the fields used to generate the HCL are parsed.
+type rmsResource struct {
+	ID                  string                 `json:"id"`
+	Name                string                 `json:"name"`
+	Provider            string                 `json:"provider"`
+	Type                string                 `json:"type"`
+	RegionID            string                 `json:"region_id"`
+	EnterpriseProjectID string                 `json:"ep_id"`
+	Tags                map[string]string      `json:"tags"`
+	Properties          map[string]interface{} `json:"properties"`
+}
+
+// rmsType returns the type of the resource in the format of `provider.type`.
+func (r *rmsResource) rmsType() string {
+	return r.Provider + "." + r.Type
+}
+
+// filters are the query parameters of the RMS resources.
+type filters struct {
+	regionID            string
+	enterpriseProjectID string
+	resourceType        string
+	tags                map[string]string
+}
+
+func (f *filters) queryParams(marker string) url.Values {
+	params := url.Values{}
+	if f.regionID != "" {
+		params.Set("region_id", f.regionID)
+	}
+	if f.enterpriseProjectID != "" {
+		params.Set("ep_id", f.enterpriseProjectID)
+	}
+	if f.resourceType != "" {
+		params.Set("type", f.resourceType)
+	}
+	for k, v := range f.tags {
+		params.Add("tags", k+"="+v)
+	}
+	if marker != "" {
+		params.Set("marker", marker)
+	}
+	return params
+}
+
+// listResources queries all the resources of the domain which match the filters.
+// The API is GET /v1/resource-manager/domains/{domain_id}/all-resources, which is used by the data source
+// huaweicloud_rms_resources.
+func listResources(client *golangsdk.ServiceClient, domainID string, f *filters) ([]rmsResource, error) {
+	listPath := client.Endpoint + "v1/resource-manager/domains/{domain_id}/all-resources"
+	listPath = strings.ReplaceAll(listPath, "{domain_id}", domainID)
+	opts := golangsdk.RequestOpts{
+		KeepResponseBody: true,
+	}
+
+	var resources []rmsResource
+	var marker string
+	for {
+		resp, err := client.Request("GET", listPath+"?"+f.queryParams(marker).Encode(), &opts)
+		if err != nil {
+			return nil, fmt.Errorf("error querying RMS resources: %s", err)
+		}
+
+		var body struct {
+			Resources []rmsResource `json:"resources"`
+			PageInfo  struct {
+				NextMarker string `json:"next_marker"`
+			} `json:"page_info"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing RMS resources: %s", err)
+		}
+
+		resources = append(resources, body.Resources...)
+		marker = body.PageInfo.NextMarker
+		if marker == "" {
+			return resources, nil
+		}
+	}
+}
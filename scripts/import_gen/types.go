@@ -0,0 +1,43 @@
+package main
+
+// resourceType describes how to import an RMS resource to a huaweicloud resource.
+type resourceType struct {
+	// the type of the huaweicloud resource
+	name string
+	// importID returns the import ID from the RMS resource, the RMS resource ID is used if it's nil
+	importID func(r *rmsResource) string
+	// nameArgument is the argument which the name of the RMS resource is written to, defaults to "name"
+	nameArgument string
+}
+
+// resourceTypes maps the RMS resource types, in the format of `provider.type`, to the huaweicloud resources.
+// Only the resources which can be imported by a single RMS resource are listed.
+var resourceTypes = map[string]resourceType{
+	"cce.clusters":            {name: "huaweicloud_cce_cluster"},
+	"css.clusters":            {name: "huaweicloud_css_cluster"},
+	"dcs.redis":               {name: "huaweicloud_dcs_instance"},
+	"dcs.memcached":           {name: "huaweicloud_dcs_instance"},
+	"dms.kafkas":              {name: "huaweicloud_dms_kafka_instance"},
+	"dns.zones":               {name: "huaweicloud_dns_zone"},
+	"ecs.cloudservers":        {name: "huaweicloud_compute_instance"},
+	"elb.loadbalancers":       {name: "huaweicloud_elb_loadbalancer"},
+	"evs.volumes":             {name: "huaweicloud_evs_volume"},
+	"functiongraph.functions": {name: "huaweicloud_fgs_function"},
+	"iam.users":               {name: "huaweicloud_identity_user"},
+	"ims.images":              {name: "huaweicloud_images_image"},
+	"kms.keys":                {name: "huaweicloud_kms_key"},
+	"nat.natGateways":         {name: "huaweicloud_nat_gateway"},
+	// the bucket is imported by its name
+	"obs.buckets": {
+		name:         "huaweicloud_obs_bucket",
+		importID:     func(r *rmsResource) string { return r.Name },
+		nameArgument: "bucket",
+	},
+	"rds.instances":      {name: "huaweicloud_rds_instance"},
+	"sfsturbo.shares":    {name: "huaweicloud_sfs_turbo"},
+	"vpc.bandwidths":     {name: "huaweicloud_vpc_bandwidth"},
+	"vpc.publicips":      {name: "huaweicloud_vpc_eip"},
+	"vpc.securityGroups": {name: "huaweicloud_networking_secgroup"},
+	"vpc.vpcs":           {name: "huaweicloud_vpc"},
+	"vpc.subnets":        {name: "huaweicloud_vpc_subnet"},
+}